@@ -0,0 +1,28 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/levineuwirth/gophermark/internal/cli"
+)
+
+func main() {
+	dbPath := flag.String("db", "", "use this places.sqlite `path` instead of looking for browser profiles (saved to the config)")
+	pick := flag.Bool("pick", false, "choose a browser profile to open, or two to compare, instead of the configured database")
+	find := flag.Bool("find", false, "list all available browser profiles")
+	auditReport := flag.String("audit-report", "", "audit every bookmark without the TUI and write the broken links to this JSON `file`; exits 1 if any are broken")
+	restore := flag.String("restore", "", "put this `backup` back in place of the database given with -db, or the configured one")
+	flag.Parse()
+
+	switch {
+	case *find:
+		os.Exit(cli.ListProfiles())
+	case *restore != "":
+		os.Exit(cli.RestoreBackup(*dbPath, *restore))
+	case *auditReport != "":
+		os.Exit(cli.RunAuditReport(*dbPath, *auditReport))
+	default:
+		os.Exit(cli.RunTUI(*dbPath, *pick))
+	}
+}
@@ -3,9 +3,13 @@ package audit
 import (
 	"context"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/levineuwirth/gophermark/internal/config"
 	"github.com/levineuwirth/gophermark/internal/models"
 )
 
@@ -16,20 +20,101 @@ const (
 	StatusAlive
 	StatusDead
 	StatusTimeout
+	StatusRedirectHTTPS
+	StatusSkipped
+	StatusParked
 )
 
+func (s LinkStatus) String() string {
+	switch s {
+	case StatusPending:
+		return "pending"
+	case StatusAlive:
+		return "alive"
+	case StatusDead:
+		return "dead"
+	case StatusTimeout:
+		return "timeout"
+	case StatusRedirectHTTPS:
+		return "redirect-https"
+	case StatusSkipped:
+		return "skipped"
+	case StatusParked:
+		return "parked"
+	default:
+		return "unknown"
+	}
+}
+
 type LinkResult struct {
 	Bookmark   *models.Bookmark
 	Status     LinkStatus
 	StatusCode int
+	FinalURL   string    // set when the request was redirected elsewhere
+	Redirects  int       // redirect responses followed to reach the final one
+	CheckedAt  time.Time // when the check finished
+	Cached     bool      // reused from an earlier check rather than fetched
+}
+
+// RedirectLoop reports whether the check gave up following redirects, which
+// usually means the site redirects in a loop.
+func (r LinkResult) RedirectLoop() bool {
+	return r.Redirects >= maxRedirects && r.StatusCode >= 300 && r.StatusCode < 400
 }
 
+// HTTPSUpgrade returns the https URL the bookmark redirected to when
+// nothing but the scheme changed: the host, path and query are the same.
+// Redirects to another page of the same site aren't upgrades.
+func (r LinkResult) HTTPSUpgrade() (string, bool) {
+	if r.Status != StatusRedirectHTTPS {
+		return "", false
+	}
+	original, err := url.Parse(r.Bookmark.URL)
+	if err != nil {
+		return "", false
+	}
+	final, err := url.Parse(r.FinalURL)
+	if err != nil || !isHTTPSUpgrade(original, final) {
+		return "", false
+	}
+	if strings.TrimSuffix(original.EscapedPath(), "/") != strings.TrimSuffix(final.EscapedPath(), "/") ||
+		original.RawQuery != final.RawQuery {
+		return "", false
+	}
+	return r.FinalURL, true
+}
+
+const (
+	maxRetries    = 3
+	maxRetryDelay = 30 * time.Second
+	maxRedirects  = 10
+)
+
 type Auditor struct {
 	results   map[int64]LinkResult
 	mu        sync.RWMutex
 	workers   int
 	timeout   time.Duration
 	userAgent string
+	headers   map[string]http.Header
+
+	hostLimit int
+	hostDelay time.Duration
+	hosts     map[string]*hostLimiter
+	hostsMu   sync.Mutex
+
+	detectParked bool
+
+	cache       *Cache
+	cacheMaxAge time.Duration
+}
+
+// hostLimiter caps in-flight requests to one host and spaces out their
+// start times.
+type hostLimiter struct {
+	slots chan struct{}
+	mu    sync.Mutex
+	next  time.Time
 }
 
 func NewAuditor(workers int) *Auditor {
@@ -41,20 +126,112 @@ func NewAuditor(workers int) *Auditor {
 		workers:   workers,
 		timeout:   5 * time.Second,
 		userAgent: "GopherMark/1.0",
+		headers:   make(map[string]http.Header),
+		hostLimit: 2,
+		hosts:     make(map[string]*hostLimiter),
+	}
+}
+
+// NewConfiguredAuditor is NewAuditor with the audit_* settings from cfg
+// applied. The URL cache is left to the caller, since only some audits
+// should reuse it.
+func NewConfiguredAuditor(cfg *config.Config) *Auditor {
+	auditor := NewAuditor(cfg.AuditWorkers)
+	auditor.SetTimeout(time.Duration(cfg.AuditTimeoutSeconds) * time.Second)
+	auditor.SetHostLimit(cfg.AuditHostLimit)
+	auditor.SetHostDelay(time.Duration(cfg.AuditHostDelayMs) * time.Millisecond)
+	auditor.SetDetectParked(cfg.AuditDetectParked)
+	auditor.SetUserAgent(cfg.AuditUserAgent)
+	for host, headers := range cfg.AuditHeaders {
+		auditor.SetHeaders(host, headers)
+	}
+	return auditor
+}
+
+// SetTimeout changes the per-request timeout; non-positive values are ignored.
+func (a *Auditor) SetTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		a.timeout = timeout
+	}
+}
+
+// SetHostLimit changes how many requests may be in flight to the same host
+// at once; non-positive values are ignored. Call it before auditing.
+func (a *Auditor) SetHostLimit(limit int) {
+	if limit > 0 {
+		a.hostLimit = limit
 	}
 }
 
+// SetHostDelay sets the minimum time between the start of two requests to
+// the same host. Call it before auditing.
+func (a *Auditor) SetHostDelay(delay time.Duration) {
+	if delay >= 0 {
+		a.hostDelay = delay
+	}
+}
+
+// SetUserAgent replaces the default GopherMark/1.0 User-Agent, for sites
+// that turn away unknown clients. An empty string is ignored.
+func (a *Auditor) SetUserAgent(userAgent string) {
+	if userAgent != "" {
+		a.userAgent = userAgent
+	}
+}
+
+// SetHeaders adds headers to every request to host and its subdomains, or
+// to every request when host is "*". Credentials such as Cookie or
+// Authorization should be scoped to their host. Call it before auditing.
+func (a *Auditor) SetHeaders(host string, headers map[string]string) {
+	host = strings.ToLower(host)
+	if a.headers[host] == nil {
+		a.headers[host] = make(http.Header)
+	}
+	for name, value := range headers {
+		a.headers[host].Set(name, value)
+	}
+}
+
+// setHeaders applies the User-Agent and any configured headers to req.
+func (a *Auditor) setHeaders(req *http.Request) {
+	req.Header.Set("User-Agent", a.userAgent)
+
+	host := strings.ToLower(req.URL.Hostname())
+	for pattern, headers := range a.headers {
+		if pattern != "*" && host != pattern && !strings.HasSuffix(host, "."+pattern) {
+			continue
+		}
+		for name, values := range headers {
+			req.Header[name] = values
+		}
+	}
+}
+
+// SetCache makes the auditor reuse results from cache that are younger
+// than maxAge instead of fetching the link again, and record every link it
+// does fetch. With a zero maxAge every link is fetched, but the cache is
+// still brought up to date. Call it before auditing.
+func (a *Auditor) SetCache(cache *Cache, maxAge time.Duration) {
+	a.cache = cache
+	a.cacheMaxAge = maxAge
+}
+
 func (a *Auditor) AuditAll(ctx context.Context, root *models.Bookmark) <-chan LinkResult {
+	return a.AuditBookmarks(ctx, collectBookmarks(root))
+}
+
+// AuditBookmarks checks only the given bookmarks, using the same worker pool
+// and timeout as AuditAll. Bookmarks sharing a URL are checked once, and
+// each of them gets its own copy of the result.
+func (a *Auditor) AuditBookmarks(ctx context.Context, bookmarks []*models.Bookmark) <-chan LinkResult {
 	resultChan := make(chan LinkResult, 100)
 
 	go func() {
 		defer close(resultChan)
 
-		bookmarks := collectBookmarks(root)
-
-		jobs := make(chan *models.Bookmark, len(bookmarks))
-		for _, b := range bookmarks {
-			jobs <- b
+		jobs := make(chan []*models.Bookmark, len(bookmarks))
+		for _, group := range groupByURL(bookmarks) {
+			jobs <- group
 		}
 		close(jobs)
 
@@ -63,16 +240,29 @@ func (a *Auditor) AuditAll(ctx context.Context, root *models.Bookmark) <-chan Li
 			wg.Add(1)
 			go func() {
 				defer wg.Done()
-				for bookmark := range jobs {
+				for group := range jobs {
 					select {
 					case <-ctx.Done():
 						return
 					default:
-						result := a.checkLink(bookmark)
-						a.mu.Lock()
-						a.results[bookmark.ID] = result
-						a.mu.Unlock()
-						resultChan <- result
+						result := a.checkLink(ctx, group[0])
+						if ctx.Err() != nil {
+							// Cancelled mid-request; the result is meaningless.
+							return
+						}
+						if !result.Cached {
+							result.CheckedAt = time.Now()
+							if a.cache != nil {
+								a.cache.store(group[0].URL, result)
+							}
+						}
+						for _, bookmark := range group {
+							result.Bookmark = bookmark
+							a.mu.Lock()
+							a.results[bookmark.ID] = result
+							a.mu.Unlock()
+							resultChan <- result
+						}
 					}
 				}
 			}()
@@ -84,7 +274,24 @@ func (a *Auditor) AuditAll(ctx context.Context, root *models.Bookmark) <-chan Li
 	return resultChan
 }
 
-func (a *Auditor) checkLink(bookmark *models.Bookmark) LinkResult {
+// groupByURL gathers bookmarks with the same URL, keeping them in the order
+// their URLs first appear.
+func groupByURL(bookmarks []*models.Bookmark) [][]*models.Bookmark {
+	index := make(map[string]int)
+	var groups [][]*models.Bookmark
+	for _, b := range bookmarks {
+		i, ok := index[b.URL]
+		if !ok {
+			i = len(groups)
+			index[b.URL] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], b)
+	}
+	return groups
+}
+
+func (a *Auditor) checkLink(parent context.Context, bookmark *models.Bookmark) LinkResult {
 	if bookmark.URL == "" {
 		return LinkResult{
 			Bookmark: bookmark,
@@ -92,7 +299,73 @@ func (a *Auditor) checkLink(bookmark *models.Bookmark) LinkResult {
 		}
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+	// place:, javascript:, about: and the like can't be fetched; report them
+	// separately instead of calling them dead.
+	parsed, err := url.Parse(bookmark.URL)
+	if err == nil && parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return LinkResult{
+			Bookmark: bookmark,
+			Status:   StatusSkipped,
+		}
+	}
+
+	if a.cache != nil && a.cacheMaxAge > 0 {
+		if result, ok := a.cache.lookup(bookmark.URL, a.cacheMaxAge); ok {
+			result.Bookmark = bookmark
+			return result
+		}
+	}
+
+	var host string
+	if parsed != nil {
+		host = strings.ToLower(parsed.Hostname())
+	}
+
+	// A 429 means the host is up but wants us to slow down, so wait and try
+	// again. If it never relents we still don't know whether the page is
+	// there, so report a timeout, which isn't cached and gets checked again.
+	backoff := time.Second
+	for attempt := 0; ; attempt++ {
+		result, retryAfter := a.fetch(parent, bookmark, host)
+		if result.StatusCode != http.StatusTooManyRequests {
+			return result
+		}
+		if attempt == maxRetries {
+			result.Status = StatusTimeout
+			return result
+		}
+
+		if retryAfter <= 0 {
+			retryAfter = backoff
+			backoff *= 2
+		}
+		if retryAfter > maxRetryDelay {
+			retryAfter = maxRetryDelay
+		}
+		select {
+		case <-parent.Done():
+			return LinkResult{
+				Bookmark: bookmark,
+				Status:   StatusTimeout,
+			}
+		case <-time.After(retryAfter):
+		}
+	}
+}
+
+// fetch makes a single HEAD request for bookmark once host has a free slot.
+// For a 429 response it also returns the delay the server asked for, if any.
+func (a *Auditor) fetch(parent context.Context, bookmark *models.Bookmark, host string) (LinkResult, time.Duration) {
+	release, err := a.acquireHost(parent, host)
+	if err != nil {
+		return LinkResult{
+			Bookmark: bookmark,
+			Status:   StatusTimeout,
+		}, 0
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(parent, a.timeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodHead, bookmark.URL, nil)
@@ -100,14 +373,16 @@ func (a *Auditor) checkLink(bookmark *models.Bookmark) LinkResult {
 		return LinkResult{
 			Bookmark: bookmark,
 			Status:   StatusDead,
-		}
+		}, 0
 	}
 
-	req.Header.Set("User-Agent", a.userAgent)
+	a.setHeaders(req)
 
+	redirects := 0
 	client := &http.Client{
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			if len(via) >= 10 {
+			redirects = len(via)
+			if len(via) >= maxRedirects {
 				return http.ErrUseLastResponse
 			}
 			return nil
@@ -120,25 +395,115 @@ func (a *Auditor) checkLink(bookmark *models.Bookmark) LinkResult {
 			return LinkResult{
 				Bookmark: bookmark,
 				Status:   StatusTimeout,
-			}
+			}, 0
 		}
 		return LinkResult{
 			Bookmark: bookmark,
 			Status:   StatusDead,
-		}
+		}, 0
 	}
 	defer resp.Body.Close()
 
+	// The parked-page check below reuses client, so keep the count from
+	// the HEAD request.
+	hops := redirects
+
 	status := StatusAlive
 	if resp.StatusCode >= 400 {
 		status = StatusDead
 	}
 
+	var finalURL string
+	if resp.Request != nil && resp.Request.URL.String() != bookmark.URL {
+		finalURL = resp.Request.URL.String()
+		if status == StatusAlive && isHTTPSUpgrade(req.URL, resp.Request.URL) {
+			status = StatusRedirectHTTPS
+		}
+	}
+
+	if a.detectParked && (status == StatusAlive || status == StatusRedirectHTTPS) {
+		pageURL := bookmark.URL
+		if finalURL != "" {
+			pageURL = finalURL
+		}
+		if (resp.Request != nil && isParkingHost(resp.Request.URL)) || a.isParked(ctx, client, pageURL) {
+			status = StatusParked
+		}
+	}
+
+	var retryAfter time.Duration
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+	}
+
 	return LinkResult{
 		Bookmark:   bookmark,
 		Status:     status,
 		StatusCode: resp.StatusCode,
+		FinalURL:   finalURL,
+		Redirects:  hops,
+	}, retryAfter
+}
+
+// acquireHost waits for a free request slot for host and for the host's
+// minimum delay to pass. The returned func gives the slot back.
+func (a *Auditor) acquireHost(ctx context.Context, host string) (func(), error) {
+	a.hostsMu.Lock()
+	limiter, ok := a.hosts[host]
+	if !ok {
+		limiter = &hostLimiter{slots: make(chan struct{}, a.hostLimit)}
+		a.hosts[host] = limiter
+	}
+	a.hostsMu.Unlock()
+
+	select {
+	case limiter.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	release := func() { <-limiter.slots }
+
+	if a.hostDelay > 0 {
+		limiter.mu.Lock()
+		now := time.Now()
+		start := limiter.next
+		if start.Before(now) {
+			start = now
+		}
+		limiter.next = start.Add(a.hostDelay)
+		limiter.mu.Unlock()
+
+		select {
+		case <-time.After(start.Sub(now)):
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	}
+
+	return release, nil
+}
+
+// parseRetryAfter reads a Retry-After header given either in seconds or as
+// an HTTP date, returning 0 when it's missing or unreadable.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
 	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
+
+// isHTTPSUpgrade reports whether a redirect only moved an http URL onto
+// https for the same host.
+func isHTTPSUpgrade(original, final *url.URL) bool {
+	return original.Scheme == "http" && final.Scheme == "https" &&
+		strings.EqualFold(original.Hostname(), final.Hostname())
 }
 
 func (a *Auditor) GetResult(bookmarkID int64) (LinkResult, bool) {
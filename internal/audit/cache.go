@@ -0,0 +1,119 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type cachedCheck struct {
+	Status     string    `json:"status"`
+	StatusCode int       `json:"status_code,omitempty"`
+	FinalURL   string    `json:"final_url,omitempty"`
+	Redirects  int       `json:"redirects,omitempty"`
+	CheckedAt  time.Time `json:"checked_at"`
+}
+
+// Cache remembers the outcome of recent link checks by URL, so a link is
+// fetched once however many bookmarks point at it, and not again until its
+// entry is older than the auditor's cache age.
+type Cache struct {
+	mu      sync.Mutex
+	entries map[string]cachedCheck
+}
+
+func NewCache() *Cache {
+	return &Cache{entries: make(map[string]cachedCheck)}
+}
+
+// LoadCache reads a cache written by Save. A missing file yields an empty
+// cache.
+func LoadCache(path string) (*Cache, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NewCache(), nil
+		}
+		return nil, fmt.Errorf("failed to read audit cache: %w", err)
+	}
+
+	cache := NewCache()
+	if err := json.Unmarshal(data, &cache.entries); err != nil {
+		return nil, fmt.Errorf("failed to parse audit cache: %w", err)
+	}
+	if cache.entries == nil {
+		cache.entries = make(map[string]cachedCheck)
+	}
+	return cache, nil
+}
+
+// Save writes the cache to path, leaving out entries older than maxAge so
+// the file doesn't grow forever. A non-positive maxAge keeps everything.
+func (c *Cache) Save(path string, maxAge time.Duration) error {
+	c.mu.Lock()
+	entries := make(map[string]cachedCheck, len(c.entries))
+	for url, entry := range c.entries {
+		if maxAge <= 0 || time.Since(entry.CheckedAt) < maxAge {
+			entries[url] = entry
+		}
+	}
+	c.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit cache: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write audit cache: %w", err)
+	}
+
+	return nil
+}
+
+// lookup returns the cached result for url if it was checked within maxAge.
+func (c *Cache) lookup(url string, maxAge time.Duration) (LinkResult, bool) {
+	c.mu.Lock()
+	entry, ok := c.entries[url]
+	c.mu.Unlock()
+	if !ok || time.Since(entry.CheckedAt) >= maxAge {
+		return LinkResult{}, false
+	}
+
+	status := parseLinkStatus(entry.Status)
+	if status == StatusPending {
+		return LinkResult{}, false
+	}
+	return LinkResult{
+		Status:     status,
+		StatusCode: entry.StatusCode,
+		FinalURL:   entry.FinalURL,
+		Redirects:  entry.Redirects,
+		CheckedAt:  entry.CheckedAt,
+		Cached:     true,
+	}, true
+}
+
+// store records a fresh result. Timeouts are usually passing trouble, and
+// skipped links never touch the network, so neither is worth remembering.
+func (c *Cache) store(url string, result LinkResult) {
+	if url == "" || result.Cached || result.Status == StatusPending || result.Status == StatusTimeout || result.Status == StatusSkipped {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[url] = cachedCheck{
+		Status:     result.Status.String(),
+		StatusCode: result.StatusCode,
+		FinalURL:   result.FinalURL,
+		Redirects:  result.Redirects,
+		CheckedAt:  result.CheckedAt,
+	}
+}
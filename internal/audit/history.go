@@ -0,0 +1,151 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/levineuwirth/gophermark/internal/models"
+)
+
+type savedResult struct {
+	URL        string `json:"url"`
+	Title      string `json:"title"`
+	Status     string `json:"status"`
+	StatusCode int    `json:"status_code,omitempty"`
+}
+
+type savedResults struct {
+	SavedAt  time.Time             `json:"saved_at"`
+	Database string                `json:"database"`
+	Results  map[int64]savedResult `json:"results"`
+}
+
+// SaveResults writes the outcome of an audit of database to path so the
+// next run can be compared against it.
+func SaveResults(path, database string, results map[int64]LinkResult) error {
+	saved := savedResults{
+		SavedAt:  time.Now(),
+		Database: database,
+		Results:  make(map[int64]savedResult, len(results)),
+	}
+	for id, result := range results {
+		if result.Status == StatusPending || result.Bookmark == nil {
+			continue
+		}
+		saved.Results[id] = savedResult{
+			URL:        result.Bookmark.URL,
+			Title:      result.Bookmark.Title,
+			Status:     result.Status.String(),
+			StatusCode: result.StatusCode,
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create results directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit results: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write audit results: %w", err)
+	}
+
+	return nil
+}
+
+// LoadResults reads results saved by SaveResults along with when they were
+// saved. A missing file, or one written for a different database, yields
+// nil results and no error.
+func LoadResults(path, database string) (map[int64]LinkResult, time.Time, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, time.Time{}, nil
+		}
+		return nil, time.Time{}, fmt.Errorf("failed to read audit results: %w", err)
+	}
+
+	var saved savedResults
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to parse audit results: %w", err)
+	}
+	if saved.Database != database {
+		return nil, time.Time{}, nil
+	}
+
+	results := make(map[int64]LinkResult, len(saved.Results))
+	for id, result := range saved.Results {
+		results[id] = LinkResult{
+			Bookmark:   &models.Bookmark{ID: id, Type: models.TypeBookmark, Title: result.Title, URL: result.URL},
+			Status:     parseLinkStatus(result.Status),
+			StatusCode: result.StatusCode,
+		}
+	}
+
+	return results, saved.SavedAt, nil
+}
+
+func parseLinkStatus(s string) LinkStatus {
+	for status := StatusPending; status <= StatusParked; status++ {
+		if status.String() == s {
+			return status
+		}
+	}
+	return StatusPending
+}
+
+// Transition is a bookmark whose link status changed between two audits.
+type Transition struct {
+	Before LinkResult
+	After  LinkResult
+}
+
+type ResultsDiff struct {
+	NewlyBroken []Transition
+	Recovered   []Transition
+}
+
+// DiffResults finds the links that broke or recovered between two audits.
+// Only bookmarks checked in both runs are compared, and skipped links are
+// ignored. Each list is sorted by URL.
+func DiffResults(previous, current map[int64]LinkResult) ResultsDiff {
+	var diff ResultsDiff
+	for id, after := range current {
+		before, ok := previous[id]
+		if !ok {
+			continue
+		}
+
+		switch {
+		case isWorking(before.Status) && isBroken(after.Status):
+			diff.NewlyBroken = append(diff.NewlyBroken, Transition{Before: before, After: after})
+		case isBroken(before.Status) && isWorking(after.Status):
+			diff.Recovered = append(diff.Recovered, Transition{Before: before, After: after})
+		}
+	}
+
+	sortTransitions(diff.NewlyBroken)
+	sortTransitions(diff.Recovered)
+	return diff
+}
+
+func isBroken(status LinkStatus) bool {
+	return status == StatusDead || status == StatusTimeout
+}
+
+func isWorking(status LinkStatus) bool {
+	return status == StatusAlive || status == StatusRedirectHTTPS
+}
+
+func sortTransitions(transitions []Transition) {
+	sort.Slice(transitions, func(i, j int) bool {
+		return transitions[i].After.Bookmark.URL < transitions[j].After.Bookmark.URL
+	})
+}
@@ -0,0 +1,105 @@
+package audit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxParkedBodySize bounds how much of a page is read when looking for
+// parking-page markers; they show up near the top.
+const maxParkedBodySize = 64 * 1024
+
+// parkingHosts are domain marketplaces and parking services that parked
+// domains redirect to.
+var parkingHosts = []string{
+	"sedo.com",
+	"sedoparking.com",
+	"bodis.com",
+	"parkingcrew.net",
+	"above.com",
+	"dan.com",
+	"afternic.com",
+	"hugedomains.com",
+	"undeveloped.com",
+	"parklogic.com",
+	"domainmarket.com",
+	"buydomains.com",
+}
+
+// parkingPhrases appear on typical parking and for-sale pages. They're
+// matched against the lower-cased start of the page.
+var parkingPhrases = []string{
+	"this domain is for sale",
+	"this domain may be for sale",
+	"domain is for sale",
+	"buy this domain",
+	"the domain name is for sale",
+	"this domain has been registered",
+	"this domain is parked",
+	"parked free",
+	"domain parking",
+	"parkingcrew",
+	"sedoparking",
+	"bodis.com",
+	"inquire about this domain",
+	"make an offer on this domain",
+}
+
+// SetDetectParked enables the parked-domain check: pages that answer
+// successfully are fetched in full and matched against parking-page
+// heuristics. It costs a GET per live link, so it's off by default.
+func (a *Auditor) SetDetectParked(enabled bool) {
+	a.detectParked = enabled
+}
+
+// isParked fetches pageURL and reports whether it looks like a parking or
+// domain-for-sale page.
+func (a *Auditor) isParked(ctx context.Context, client *http.Client, pageURL string) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return false
+	}
+	a.setHeaders(req)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	if resp.Request != nil && isParkingHost(resp.Request.URL) {
+		return true
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxParkedBodySize))
+	if err != nil && len(body) == 0 {
+		return false
+	}
+	return looksParked(string(body))
+}
+
+func isParkingHost(u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+	for _, parking := range parkingHosts {
+		if host == parking || strings.HasSuffix(host, "."+parking) {
+			return true
+		}
+	}
+	return false
+}
+
+func looksParked(body string) bool {
+	body = strings.ToLower(body)
+	for _, phrase := range parkingPhrases {
+		if strings.Contains(body, phrase) {
+			return true
+		}
+	}
+	return false
+}
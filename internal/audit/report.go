@@ -0,0 +1,100 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+type Report struct {
+	GeneratedAt time.Time     `json:"generated_at"`
+	Database    string        `json:"database"`
+	Checked     int           `json:"checked"`
+	Broken      []ReportEntry `json:"broken"`
+
+	// Results lists every checked link, not just the broken ones. Only
+	// NewFullReport fills it in.
+	Results []ReportEntry `json:"results,omitempty"`
+}
+
+type ReportEntry struct {
+	ID         int64     `json:"id,omitempty"`
+	URL        string    `json:"url"`
+	Title      string    `json:"title"`
+	FolderPath string    `json:"folder_path"`
+	Status     string    `json:"status"`
+	StatusCode int       `json:"status_code,omitempty"`
+	CheckedAt  time.Time `json:"checked_at,omitzero"`
+}
+
+// NewReport collects the dead and timed-out results into a report.
+// folderPaths maps bookmark IDs to their folder path.
+func NewReport(database string, results []LinkResult, folderPaths map[int64]string) Report {
+	report := Report{
+		GeneratedAt: time.Now(),
+		Database:    database,
+		Checked:     len(results),
+		Broken:      make([]ReportEntry, 0),
+	}
+
+	for _, result := range results {
+		if result.Status != StatusDead && result.Status != StatusTimeout {
+			continue
+		}
+		report.Broken = append(report.Broken, newReportEntry(result, folderPaths))
+	}
+
+	return report
+}
+
+// NewFullReport is NewReport with every result listed under Results as
+// well, ordered by bookmark ID so successive reports line up. Pending
+// results are left out.
+func NewFullReport(database string, results []LinkResult, folderPaths map[int64]string) Report {
+	var checked []LinkResult
+	for _, result := range results {
+		if result.Status != StatusPending && result.Bookmark != nil {
+			checked = append(checked, result)
+		}
+	}
+	sort.Slice(checked, func(i, j int) bool {
+		return checked[i].Bookmark.ID < checked[j].Bookmark.ID
+	})
+
+	report := NewReport(database, checked, folderPaths)
+	report.Results = make([]ReportEntry, 0, len(checked))
+	for _, result := range checked {
+		report.Results = append(report.Results, newReportEntry(result, folderPaths))
+	}
+	return report
+}
+
+func newReportEntry(result LinkResult, folderPaths map[int64]string) ReportEntry {
+	return ReportEntry{
+		ID:         result.Bookmark.ID,
+		URL:        result.Bookmark.URL,
+		Title:      result.Bookmark.Title,
+		FolderPath: folderPaths[result.Bookmark.ID],
+		Status:     result.Status.String(),
+		StatusCode: result.StatusCode,
+		CheckedAt:  result.CheckedAt,
+	}
+}
+
+func WriteReport(report Report, outputPath string) error {
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+
+	return nil
+}
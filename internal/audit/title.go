@@ -0,0 +1,84 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxTitleBodySize bounds how much of a page is read when looking for its
+// title, which belongs in the head near the top.
+const maxTitleBodySize = 128 * 1024
+
+// FetchTitle GETs pageURL with the auditor's timeout, User-Agent and headers
+// and returns the text of the page's <title>.
+func (a *Auditor) FetchTitle(parent context.Context, pageURL string) (string, error) {
+	ctx, cancel := context.WithTimeout(parent, a.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	a.setHeaders(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("timed out after %s", a.timeout)
+		}
+		return "", fmt.Errorf("failed to fetch page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("server answered %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxTitleBodySize))
+	if err != nil && len(body) == 0 {
+		return "", fmt.Errorf("failed to read page: %w", err)
+	}
+
+	title := extractTitle(string(body))
+	if title == "" {
+		return "", fmt.Errorf("page has no title")
+	}
+	return title, nil
+}
+
+// extractTitle returns the unescaped text of the first <title> element in
+// page, with runs of whitespace collapsed, or "" if there's none.
+func extractTitle(page string) string {
+	start := indexFold(page, "<title")
+	if start < 0 {
+		return ""
+	}
+	// Skip any attributes, e.g. <title data-rh="true">.
+	open := strings.IndexByte(page[start:], '>')
+	if open < 0 {
+		return ""
+	}
+	start += open + 1
+
+	end := indexFold(page[start:], "</title")
+	if end < 0 {
+		return ""
+	}
+
+	return strings.Join(strings.Fields(html.UnescapeString(page[start:start+end])), " ")
+}
+
+// indexFold is strings.Index ignoring ASCII case. Lower-casing the page
+// first could shift byte offsets in non-ASCII text.
+func indexFold(s, substr string) int {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return i
+		}
+	}
+	return -1
+}
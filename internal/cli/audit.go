@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/levineuwirth/gophermark/internal/audit"
+	"github.com/levineuwirth/gophermark/internal/config"
+	"github.com/levineuwirth/gophermark/internal/db"
+)
+
+const (
+	ExitOK          = 0
+	ExitBrokenLinks = 1
+	ExitError       = 2
+)
+
+// RunAuditReport audits every bookmark without starting the TUI, writes the
+// broken links to reportPath as JSON and returns the process exit code.
+// The database is only ever opened read-only.
+// An empty dbPath falls back to the configured database, then to the first
+// profile found on disk.
+func RunAuditReport(dbPath, reportPath string) int {
+	dbPath, err := resolveDatabasePath(dbPath)
+	if err != nil {
+		PrintError(err)
+		return ExitError
+	}
+
+	conn, err := db.OpenReadOnly(dbPath)
+	if err != nil {
+		PrintError(err)
+		return ExitError
+	}
+	defer conn.Close()
+
+	bookmarks, err := conn.FetchAllBookmarks()
+	if err != nil {
+		PrintError(err)
+		return ExitError
+	}
+
+	root, err := db.BuildTree(bookmarks)
+	if err != nil {
+		PrintError(err)
+		return ExitError
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring unreadable config: %v\n", err)
+		cfg = &config.Config{}
+	}
+
+	auditor := audit.NewConfiguredAuditor(cfg)
+	var results []audit.LinkResult
+	for result := range auditor.AuditAll(context.Background(), root) {
+		results = append(results, result)
+	}
+
+	report := audit.NewReport(dbPath, results, db.FolderPaths(root))
+	if err := audit.WriteReport(report, reportPath); err != nil {
+		PrintError(err)
+		return ExitError
+	}
+
+	fmt.Printf("Checked %d links, %d broken. Report written to %s\n", report.Checked, len(report.Broken), reportPath)
+
+	if len(report.Broken) > 0 {
+		return ExitBrokenLinks
+	}
+	return ExitOK
+}
+
+// resolveDatabasePath picks the database to use. A path given on the command
+// line is used as is, without looking at the configured one or the browser
+// profiles, once it's been checked to be a places database.
+func resolveDatabasePath(dbPath string) (string, error) {
+	if dbPath != "" {
+		return dbPath, db.CheckPlacesFile(dbPath)
+	}
+
+	if configured := configuredDatabase(); configured != "" {
+		return configured, nil
+	}
+
+	profiles, err := db.FindAllProfiles()
+	if err != nil {
+		return "", err
+	}
+	return db.DefaultProfile(profiles).Path, nil
+}
+
+// configuredDatabase returns the database path saved in the config, or ""
+// if there isn't one or the config can't be read.
+func configuredDatabase() string {
+	cfg, err := config.Load()
+	if err != nil {
+		return ""
+	}
+	return cfg.DatabasePath
+}
@@ -0,0 +1,20 @@
+package cli
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/levineuwirth/gophermark/internal/db"
+	"github.com/levineuwirth/gophermark/internal/ui"
+)
+
+// CompareProfiles shows which bookmarked URLs exist in only one of two
+// profiles. Both databases are opened read-only.
+func CompareProfiles(a, b db.ProfileInfo) error {
+	diff, err := db.CompareProfiles(a.Path, b.Path)
+	if err != nil {
+		return err
+	}
+
+	program := tea.NewProgram(ui.NewCompareModel(a.Name, b.Name, diff), tea.WithAltScreen())
+	_, err = program.Run()
+	return err
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/levineuwirth/gophermark/internal/db"
+)
+
+// OpenDatabase opens dbPath for the TUI, after checking it's a places
+// database. If the browser is running it warns on out and asks on in
+// whether to read the live file anyway or read from a snapshot copy;
+// anything else aborts with the ErrBrowserRunning error.
+func OpenDatabase(dbPath string, in io.Reader, out io.Writer) (*db.DB, error) {
+	if err := db.CheckPlacesFile(dbPath); err != nil {
+		return nil, err
+	}
+
+	conn, err := db.Open(dbPath)
+	if !errors.Is(err, db.ErrBrowserRunning) {
+		return conn, err
+	}
+
+	fmt.Fprintf(out, "⚠ %v\n", err)
+	fmt.Fprintln(out, "Reading it directly may show stale or partially written bookmarks.")
+	fmt.Fprint(out, "[s] read a snapshot copy (default)  [r] continue read-only  [q] quit: ")
+
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "", "s":
+		return db.OpenSnapshot(dbPath)
+	case "r":
+		return db.OpenReadOnly(dbPath)
+	default:
+		return nil, err
+	}
+}
+
+// PrintError reports err on stderr, followed by a suggestion for fixing it
+// when it's one of the db package's errors.
+func PrintError(err error) {
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	if advice := db.Advice(err); advice != "" {
+		fmt.Fprintln(os.Stderr, advice)
+	}
+}
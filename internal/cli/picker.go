@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/levineuwirth/gophermark/internal/db"
+)
+
+// pickProfile lists profiles on out and reads the choice from in. It returns
+// one profile to open, two to compare, or none when the user quits.
+func pickProfile(profiles []db.ProfileInfo, in io.Reader, out io.Writer) ([]db.ProfileInfo, error) {
+	defaultIndex := 0
+	fmt.Fprintln(out, "Browser profiles:")
+	for i, profile := range profiles {
+		name := profile.Name
+		if profile.Default {
+			name += " (default)"
+			defaultIndex = i
+		}
+		fmt.Fprintf(out, "  %d) %s\n     %s\n", i+1, name, profile.Path)
+	}
+	if len(profiles) > 1 {
+		fmt.Fprintln(out, "  c) compare two profiles")
+	}
+	fmt.Fprintln(out, "  q) quit")
+
+	reader := bufio.NewReader(in)
+	answer := prompt(reader, out, fmt.Sprintf("Open which profile? [%d]: ", defaultIndex+1))
+	switch {
+	case answer == "q":
+		return nil, nil
+	case answer == "c" && len(profiles) > 1:
+		otherIndex := 0
+		if defaultIndex == 0 {
+			otherIndex = 1
+		}
+		a, err := profileAt(profiles, prompt(reader, out, fmt.Sprintf("Compare profile [%d]: ", defaultIndex+1)), defaultIndex)
+		if err != nil {
+			return nil, err
+		}
+		b, err := profileAt(profiles, prompt(reader, out, fmt.Sprintf("with profile [%d]: ", otherIndex+1)), otherIndex)
+		if err != nil {
+			return nil, err
+		}
+		if a.Path == b.Path {
+			return nil, fmt.Errorf("can't compare %s with itself", a.Name)
+		}
+		return []db.ProfileInfo{a, b}, nil
+	}
+
+	profile, err := profileAt(profiles, answer, defaultIndex)
+	if err != nil {
+		return nil, err
+	}
+	return []db.ProfileInfo{profile}, nil
+}
+
+func prompt(reader *bufio.Reader, out io.Writer, question string) string {
+	fmt.Fprint(out, question)
+	answer, _ := reader.ReadString('\n')
+	return strings.ToLower(strings.TrimSpace(answer))
+}
+
+// profileAt returns the profile numbered answer in the list, or the one at
+// fallback when answer is empty.
+func profileAt(profiles []db.ProfileInfo, answer string, fallback int) (db.ProfileInfo, error) {
+	if answer == "" {
+		return profiles[fallback], nil
+	}
+	n, err := strconv.Atoi(answer)
+	if err != nil || n < 1 || n > len(profiles) {
+		return db.ProfileInfo{}, fmt.Errorf("no profile %q; pick a number from 1 to %d", answer, len(profiles))
+	}
+	return profiles[n-1], nil
+}
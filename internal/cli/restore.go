@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/levineuwirth/gophermark/internal/config"
+	"github.com/levineuwirth/gophermark/internal/staging"
+)
+
+// RestoreBackup puts backupPath back in place of the database and returns
+// the process exit code. Unlike the other runners it doesn't fall back to
+// the first profile found: overwriting the wrong profile is worse than
+// asking for -db.
+func RestoreBackup(dbPath, backupPath string) int {
+	if dbPath == "" {
+		cfg, err := config.Load()
+		if err == nil {
+			dbPath = cfg.DatabasePath
+		}
+	}
+	if dbPath == "" {
+		fmt.Fprintln(os.Stderr, "Error: no database configured; pass -db with the places.sqlite to restore")
+		return ExitError
+	}
+
+	if err := staging.RestoreBackup(dbPath, backupPath); err != nil {
+		PrintError(err)
+		return ExitError
+	}
+
+	fmt.Printf("✓ Restored %s from %s\n", dbPath, backupPath)
+	fmt.Printf("The database it replaced was saved to %s\n", staging.PreRestorePath(dbPath))
+	return ExitOK
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/levineuwirth/gophermark/internal/db"
+	"github.com/levineuwirth/gophermark/internal/ui"
+)
+
+// RunTUI opens the database and runs the bookmark manager until it quits,
+// returning the process exit code. The TUI saves the database it had open
+// to the config when it quits, so a path given with -db sticks. Without
+// one, the profile picker is shown when pick is set, or when nothing is
+// configured yet and there are several profiles to choose from; the picker
+// can also compare two profiles instead. Otherwise the database is resolved
+// as for RunAuditReport.
+func RunTUI(dbPath string, pick bool) int {
+	if dbPath == "" && (pick || configuredDatabase() == "") {
+		profiles, err := db.FindAllProfiles()
+		if err != nil {
+			PrintError(err)
+			return ExitError
+		}
+		if pick || len(profiles) > 1 {
+			chosen, err := pickProfile(profiles, os.Stdin, os.Stdout)
+			if err != nil {
+				PrintError(err)
+				return ExitError
+			}
+			switch len(chosen) {
+			case 0:
+				return ExitOK
+			case 2:
+				if err := CompareProfiles(chosen[0], chosen[1]); err != nil {
+					PrintError(err)
+					return ExitError
+				}
+				return ExitOK
+			}
+			dbPath = chosen[0].Path
+		}
+	}
+
+	dbPath, err := resolveDatabasePath(dbPath)
+	if err != nil {
+		PrintError(err)
+		return ExitError
+	}
+
+	conn, err := OpenDatabase(dbPath, os.Stdin, os.Stdout)
+	if err != nil {
+		PrintError(err)
+		return ExitError
+	}
+
+	program := tea.NewProgram(ui.NewLoadingModel(conn, dbPath), tea.WithAltScreen(), tea.WithMouseCellMotion())
+	if _, err := program.Run(); err != nil {
+		PrintError(err)
+		return ExitError
+	}
+	return ExitOK
+}
+
+// ListProfiles prints every browser profile with a places.sqlite, marking
+// the default one, and returns the process exit code.
+func ListProfiles() int {
+	profiles, err := db.FindAllProfiles()
+	if err != nil {
+		PrintError(err)
+		return ExitError
+	}
+
+	for _, profile := range profiles {
+		marker := " "
+		if profile.Default {
+			marker = "*"
+		}
+		fmt.Printf("%s %s\n    %s\n", marker, profile.Name, profile.Path)
+	}
+	fmt.Println("\n* default profile")
+	return ExitOK
+}
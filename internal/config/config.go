@@ -8,7 +8,29 @@ import (
 )
 
 type Config struct {
-	DatabasePath string `json:"database_path"`
+	DatabasePath        string `json:"database_path"`
+	ShowInspector       bool   `json:"show_inspector"`
+	AuditWorkers        int    `json:"audit_workers,omitempty"`
+	AuditTimeoutSeconds int    `json:"audit_timeout_seconds,omitempty"`
+	AuditHostLimit      int    `json:"audit_host_limit,omitempty"`
+	AuditHostDelayMs    int    `json:"audit_host_delay_ms,omitempty"`
+	AuditDetectParked   bool   `json:"audit_detect_parked,omitempty"`
+	AuditUserAgent      string `json:"audit_user_agent,omitempty"`
+	AuditCacheDays      int    `json:"audit_cache_days,omitempty"`
+	Theme               string `json:"theme,omitempty"`
+	SortFolders         bool   `json:"sort_folders,omitempty"`
+	CommitConfirmed     bool   `json:"commit_confirmed,omitempty"`
+	ChangeLog           string `json:"change_log,omitempty"`
+	StagingDir          string `json:"staging_dir,omitempty"`
+	HistoryMinVisits    int    `json:"history_min_visits,omitempty"`
+
+	// AuditHeaders maps a host, or "*" for every host, to extra headers
+	// sent with audit requests to it.
+	AuditHeaders map[string]map[string]string `json:"audit_headers,omitempty"`
+
+	// BookmarksBarTitles are extra names for the toolbar folder, for
+	// databases where it can't be found by Firefox's toolbar guid.
+	BookmarksBarTitles []string `json:"bookmarks_bar_titles,omitempty"`
 }
 
 func configDir() (string, error) {
@@ -29,6 +51,25 @@ func configFile() (string, error) {
 	return filepath.Join(dir, "config.json"), nil
 }
 
+// AuditResultsFile is where the TUI keeps the last audit's results for
+// comparison with the next one.
+func AuditResultsFile() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "audit-results.json"), nil
+}
+
+// AuditCacheFile is where audits remember recently checked URLs.
+func AuditCacheFile() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "audit-cache.json"), nil
+}
+
 func Load() (*Config, error) {
 	path, err := configFile()
 	if err != nil {
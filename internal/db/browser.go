@@ -0,0 +1,50 @@
+package db
+
+import (
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+func IsBrowserRunning() (bool, string) {
+	processes := []string{"firefox", "librewolf", "firefox-bin", "librewolf-bin"}
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "linux", "darwin":
+		cmd = exec.Command("pgrep", "-i", strings.Join(processes, "|"))
+	case "windows":
+		cmd = exec.Command("tasklist")
+	default:
+		return false, ""
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		return false, ""
+	}
+
+	if runtime.GOOS == "linux" || runtime.GOOS == "darwin" {
+		return len(output) > 0, detectWhichBrowser(string(output))
+	}
+
+	outputStr := strings.ToLower(string(output))
+	for _, proc := range processes {
+		if strings.Contains(outputStr, proc) {
+			return true, proc
+		}
+	}
+
+	return false, ""
+}
+
+func detectWhichBrowser(output string) string {
+	output = strings.ToLower(output)
+	if strings.Contains(output, "librewolf") {
+		return "LibreWolf"
+	}
+	if strings.Contains(output, "firefox") {
+		return "Firefox"
+	}
+	return "Browser"
+}
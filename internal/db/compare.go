@@ -0,0 +1,85 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/levineuwirth/gophermark/internal/models"
+)
+
+// ProfileDiff holds the bookmarked URLs of two profiles, split by which
+// profile has them. Each list is sorted.
+type ProfileDiff struct {
+	OnlyA []string
+	OnlyB []string
+	Both  []string
+}
+
+// CompareProfiles loads the bookmarks of two places.sqlite files read-only
+// and diffs them by URL.
+func CompareProfiles(pathA, pathB string) (*ProfileDiff, error) {
+	urlsA, err := loadBookmarkURLs(pathA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", pathA, err)
+	}
+	urlsB, err := loadBookmarkURLs(pathB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", pathB, err)
+	}
+
+	diff := &ProfileDiff{}
+	for url := range urlsA {
+		if urlsB[url] {
+			diff.Both = append(diff.Both, url)
+		} else {
+			diff.OnlyA = append(diff.OnlyA, url)
+		}
+	}
+	for url := range urlsB {
+		if !urlsA[url] {
+			diff.OnlyB = append(diff.OnlyB, url)
+		}
+	}
+
+	sort.Strings(diff.OnlyA)
+	sort.Strings(diff.OnlyB)
+	sort.Strings(diff.Both)
+	return diff, nil
+}
+
+func loadBookmarkURLs(path string) (map[string]bool, error) {
+	conn, err := OpenReadOnly(path)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	bookmarks, err := conn.FetchAllBookmarks()
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := BuildTree(bookmarks)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make(map[string]bool)
+
+	var traverse func(*models.Bookmark)
+	traverse = func(node *models.Bookmark) {
+		// Entries under the tags root mirror real bookmarks.
+		if node.GUID == "tags________" {
+			return
+		}
+		if node.IsBookmark() && node.URL != "" {
+			urls[node.URL] = true
+		}
+		for _, child := range node.Children {
+			traverse(child)
+		}
+	}
+
+	traverse(root)
+	return urls, nil
+}
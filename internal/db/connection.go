@@ -3,14 +3,118 @@ package db
 import (
 	"database/sql"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"time"
 
 	_ "modernc.org/sqlite"
 )
 
 type DB struct {
-	conn *sql.DB
-	path string
+	conn        *sql.DB
+	path        string
+	snapshotDir string
+}
+
+// Open is OpenReadOnly, but refuses with ErrBrowserRunning while the browser
+// is running, since immutable reads can then see stale or half-written pages.
+// Callers can fall back to OpenReadOnly or OpenSnapshot.
+func Open(dbPath string) (*DB, error) {
+	if running, process := IsBrowserRunning(); running {
+		return nil, fmt.Errorf("%w: %s may be writing to the database", ErrBrowserRunning, process)
+	}
+	return OpenReadOnly(dbPath)
+}
+
+// OpenSnapshot copies the database and its write-ahead log to a temporary
+// directory and reads from the copy, which gives a consistent view even
+// while the browser is running. The copy is removed on Close.
+func OpenSnapshot(dbPath string) (*DB, error) {
+	snapshotDir, err := os.MkdirTemp("", "gophermark-snapshot-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	snapshotPath := filepath.Join(snapshotDir, filepath.Base(dbPath))
+	if err := copyFile(dbPath, snapshotPath); err != nil {
+		os.RemoveAll(snapshotDir)
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%s: %w", dbPath, ErrDatabaseNotFound)
+		}
+		return nil, fmt.Errorf("failed to copy database: %w", err)
+	}
+	if fileExists(dbPath + "-wal") {
+		if err := copyFile(dbPath+"-wal", snapshotPath+"-wal"); err != nil {
+			os.RemoveAll(snapshotDir)
+			return nil, fmt.Errorf("failed to copy write-ahead log: %w", err)
+		}
+	}
+
+	conn, err := sql.Open("sqlite", fmt.Sprintf("file:%s?_pragma=query_only(1)&_timeout=5000", snapshotPath))
+	if err != nil {
+		os.RemoveAll(snapshotDir)
+		return nil, fmt.Errorf("failed to open snapshot: %w", err)
+	}
+
+	conn.SetMaxOpenConns(1)
+	conn.SetMaxIdleConns(1)
+
+	if err := conn.Ping(); err != nil {
+		conn.Close()
+		os.RemoveAll(snapshotDir)
+		return nil, fmt.Errorf("failed to ping snapshot: %w", Classify(err))
+	}
+
+	return &DB{
+		conn:        conn,
+		path:        dbPath,
+		snapshotDir: snapshotDir,
+	}, nil
+}
+
+// sqliteHeader starts every SQLite database file.
+const sqliteHeader = "SQLite format 3\x00"
+
+// CheckPlacesFile makes sure path is a SQLite database with a bookmarks
+// table before it's opened, so a mistyped path or the wrong file is refused
+// up front rather than failing halfway through loading.
+func CheckPlacesFile(path string) error {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%s: %w", path, ErrDatabaseNotFound)
+	}
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s: %w (it is a directory)", path, ErrNotPlacesDatabase)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	header := make([]byte, len(sqliteHeader))
+	_, err = io.ReadFull(file, header)
+	file.Close()
+	if err != nil || string(header) != sqliteHeader {
+		return fmt.Errorf("%s: %w (not a SQLite file)", path, ErrNotPlacesDatabase)
+	}
+
+	conn, err := OpenReadOnly(path)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	exists, err := conn.tableExists("moz_bookmarks")
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%s: %w (no moz_bookmarks table)", path, ErrNotPlacesDatabase)
+	}
+	return nil
 }
 
 func OpenReadOnly(dbPath string) (*DB, error) {
@@ -27,7 +131,7 @@ func OpenReadOnly(dbPath string) (*DB, error) {
 
 	if err := conn.Ping(); err != nil {
 		conn.Close()
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+		return nil, fmt.Errorf("failed to ping database: %w", Classify(err))
 	}
 
 	return &DB{
@@ -37,12 +141,36 @@ func OpenReadOnly(dbPath string) (*DB, error) {
 }
 
 func (db *DB) Close() error {
+	var err error
 	if db.conn != nil {
-		return db.conn.Close()
+		err = db.conn.Close()
 	}
-	return nil
+	if db.snapshotDir != "" {
+		os.RemoveAll(db.snapshotDir)
+	}
+	return err
 }
 
 func (db *DB) Conn() *sql.DB {
 	return db.conn
 }
+
+func copyFile(src, dst string) error {
+	sourceFile, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer sourceFile.Close()
+
+	destFile, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer destFile.Close()
+
+	if _, err := io.Copy(destFile, sourceFile); err != nil {
+		return err
+	}
+
+	return destFile.Sync()
+}
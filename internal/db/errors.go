@@ -0,0 +1,85 @@
+package db
+
+import (
+	"errors"
+	"strings"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+var (
+	// ErrBrowserRunning is returned when Firefox or LibreWolf may be writing
+	// to places.sqlite while we want to read or replace it.
+	ErrBrowserRunning = errors.New("browser is running")
+
+	// ErrProfileNotFound is returned when no browser profile with a
+	// places.sqlite can be found on disk.
+	ErrProfileNotFound = errors.New("no browser profile found")
+
+	// ErrDatabaseNotFound is returned when the database file doesn't exist.
+	ErrDatabaseNotFound = errors.New("database not found")
+
+	// ErrNotPlacesDatabase is returned by CheckPlacesFile for a file that
+	// isn't a browser's places.sqlite.
+	ErrNotPlacesDatabase = errors.New("not a places.sqlite database")
+
+	// ErrDatabaseLocked is returned when SQLite reports the database busy
+	// or locked by another connection, usually the browser's.
+	ErrDatabaseLocked = errors.New("database is locked")
+
+	// ErrCorrupt is returned when the database is damaged or its schema
+	// isn't what a places database should have.
+	ErrCorrupt = errors.New("database is corrupt")
+)
+
+// sqliteError keeps SQLite's own message while letting errors.Is match the
+// sentinel for its result code.
+type sqliteError struct {
+	kind error
+	err  error
+}
+
+func (e *sqliteError) Error() string   { return e.err.Error() }
+func (e *sqliteError) Unwrap() []error { return []error{e.kind, e.err} }
+
+// Classify tags a SQLite error with ErrDatabaseLocked or ErrCorrupt when
+// its result code says which it is. Other errors are returned unchanged.
+func Classify(err error) error {
+	var sqlErr *sqlite.Error
+	if !errors.As(err, &sqlErr) {
+		return err
+	}
+
+	switch sqlErr.Code() & 0xff {
+	case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED:
+		return &sqliteError{kind: ErrDatabaseLocked, err: err}
+	case sqlite3.SQLITE_CORRUPT, sqlite3.SQLITE_NOTADB:
+		return &sqliteError{kind: ErrCorrupt, err: err}
+	case sqlite3.SQLITE_ERROR:
+		// A missing table or column means the schema isn't Firefox's.
+		msg := sqlErr.Error()
+		if strings.Contains(msg, "no such table") || strings.Contains(msg, "no such column") {
+			return &sqliteError{kind: ErrCorrupt, err: err}
+		}
+	}
+	return err
+}
+
+// Advice suggests what to do about err, for showing under the error
+// itself. It returns "" when err isn't one of the errors above.
+func Advice(err error) string {
+	switch {
+	case errors.Is(err, ErrBrowserRunning), errors.Is(err, ErrDatabaseLocked):
+		return "Close Firefox or LibreWolf and retry"
+	case errors.Is(err, ErrProfileNotFound):
+		return "Start the browser once to create a profile, or pass -db with the path to places.sqlite"
+	case errors.Is(err, ErrDatabaseNotFound):
+		return "Check the path, or leave out -db to pick a profile"
+	case errors.Is(err, ErrNotPlacesDatabase):
+		return "Point -db at the places.sqlite in a browser profile"
+	case errors.Is(err, ErrCorrupt):
+		return "Restore a backup with -restore, or let the browser rebuild places.sqlite"
+	}
+	return ""
+}
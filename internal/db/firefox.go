@@ -9,8 +9,20 @@ import (
 )
 
 type ProfileInfo struct {
-	Name string
-	Path string
+	Name    string
+	Path    string
+	Default bool // the profile the browser opens by default
+}
+
+// DefaultProfile returns the profile marked as the default, or the first
+// one if none is. profiles must not be empty.
+func DefaultProfile(profiles []ProfileInfo) ProfileInfo {
+	for _, profile := range profiles {
+		if profile.Default {
+			return profile
+		}
+	}
+	return profiles[0]
 }
 
 func FindAllProfiles() ([]ProfileInfo, error) {
@@ -20,39 +32,30 @@ func FindAllProfiles() ([]ProfileInfo, error) {
 	}
 
 	var firefoxDir string
-	var browserName string
 
 	// Linux paths
 	switch {
 	case fileExists(filepath.Join(homeDir, ".librewolf")):
 		firefoxDir = filepath.Join(homeDir, ".librewolf")
-		browserName = "LibreWolf"
 	case fileExists(filepath.Join(homeDir, ".mozilla", "firefox")):
 		firefoxDir = filepath.Join(homeDir, ".mozilla", "firefox")
-		browserName = "Firefox"
 	// macOS paths
 	case fileExists(filepath.Join(homeDir, "Library", "Application Support", "LibreWolf")):
 		firefoxDir = filepath.Join(homeDir, "Library", "Application Support", "LibreWolf")
-		browserName = "LibreWolf"
 	case fileExists(filepath.Join(homeDir, "Library", "Application Support", "Firefox")):
 		firefoxDir = filepath.Join(homeDir, "Library", "Application Support", "Firefox")
-		browserName = "Firefox"
 	// Windows paths
 	case fileExists(filepath.Join(homeDir, "AppData", "Roaming", "LibreWolf")):
 		firefoxDir = filepath.Join(homeDir, "AppData", "Roaming", "LibreWolf")
-		browserName = "LibreWolf"
 	case fileExists(filepath.Join(homeDir, "AppData", "Roaming", "Mozilla", "Firefox")):
 		firefoxDir = filepath.Join(homeDir, "AppData", "Roaming", "Mozilla", "Firefox")
-		browserName = "Firefox"
 	default:
-		return nil, fmt.Errorf("firefox/librewolf profile directory not found")
+		return nil, fmt.Errorf("%w: no firefox/librewolf profile directory", ErrProfileNotFound)
 	}
 
-	fmt.Printf("Found %s profile directory: %s\n\n", browserName, firefoxDir)
-
 	profilesIni := filepath.Join(firefoxDir, "profiles.ini")
 	if !fileExists(profilesIni) {
-		return nil, fmt.Errorf("profiles.ini not found at %s", profilesIni)
+		return nil, fmt.Errorf("%w: profiles.ini not found at %s", ErrProfileNotFound, profilesIni)
 	}
 
 	profiles, err := parseAllProfiles(profilesIni, firefoxDir)
@@ -65,19 +68,24 @@ func FindAllProfiles() ([]ProfileInfo, error) {
 		placesDB := filepath.Join(firefoxDir, profile.Path, "places.sqlite")
 		if fileExists(placesDB) {
 			validProfiles = append(validProfiles, ProfileInfo{
-				Name: profile.Name,
-				Path: placesDB,
+				Name:    profile.Name,
+				Path:    placesDB,
+				Default: profile.Default,
 			})
 		}
 	}
 
 	if len(validProfiles) == 0 {
-		return nil, fmt.Errorf("no profiles with places.sqlite found")
+		return nil, fmt.Errorf("%w: no profiles with places.sqlite", ErrProfileNotFound)
 	}
 
 	return validProfiles, nil
 }
 
+// parseAllProfiles reads the profiles listed in profiles.ini. The default
+// profile is the one named by the first [Install...] section, which is how
+// current Firefox releases record it, falling back to the older Default=1
+// key on a [Profile...] section.
 func parseAllProfiles(path string, baseDir string) ([]ProfileInfo, error) {
 	file, err := os.Open(path)
 	if err != nil {
@@ -87,7 +95,9 @@ func parseAllProfiles(path string, baseDir string) ([]ProfileInfo, error) {
 
 	scanner := bufio.NewScanner(file)
 	var profiles []ProfileInfo
-	var currentName string
+	var section, currentName, installDefault string
+	var currentDefault bool
+	current := -1
 
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -96,8 +106,11 @@ func parseAllProfiles(path string, baseDir string) ([]ProfileInfo, error) {
 			continue
 		}
 
-		if strings.HasPrefix(line, "[Profile") && strings.HasSuffix(line, "]") {
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = line[1 : len(line)-1]
 			currentName = ""
+			currentDefault = false
+			current = -1
 			continue
 		}
 
@@ -109,15 +122,29 @@ func parseAllProfiles(path string, baseDir string) ([]ProfileInfo, error) {
 		key := strings.TrimSpace(parts[0])
 		value := strings.TrimSpace(parts[1])
 
-		if key == "Name" {
-			currentName = value
-		}
-
-		if key == "Path" && currentName != "" {
-			profiles = append(profiles, ProfileInfo{
-				Name: currentName,
-				Path: value,
-			})
+		switch {
+		case strings.HasPrefix(section, "Install"):
+			if key == "Default" && installDefault == "" {
+				installDefault = value
+			}
+
+		case strings.HasPrefix(section, "Profile"):
+			switch {
+			case key == "Name":
+				currentName = value
+			case key == "Path" && currentName != "":
+				profiles = append(profiles, ProfileInfo{
+					Name:    currentName,
+					Path:    value,
+					Default: currentDefault,
+				})
+				current = len(profiles) - 1
+			case key == "Default":
+				currentDefault = value == "1"
+				if current >= 0 {
+					profiles[current].Default = currentDefault
+				}
+			}
 		}
 	}
 
@@ -125,6 +152,12 @@ func parseAllProfiles(path string, baseDir string) ([]ProfileInfo, error) {
 		return nil, err
 	}
 
+	if installDefault != "" {
+		for i := range profiles {
+			profiles[i].Default = profiles[i].Path == installDefault
+		}
+	}
+
 	return profiles, nil
 }
 
@@ -0,0 +1,60 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// HistoryPlace is a page from the browser history.
+type HistoryPlace struct {
+	ID         int64
+	URL        string
+	Title      string
+	VisitCount int
+	LastVisit  time.Time
+}
+
+// FetchUnbookmarkedPlaces lists the web pages visited at least minVisits
+// times that no bookmark points at, most visited first, up to limit. Hidden
+// places, such as redirect sources and embedded frames, are left out.
+func (db *DB) FetchUnbookmarkedPlaces(ctx context.Context, minVisits, limit int) ([]HistoryPlace, error) {
+	query := `
+		SELECT p.id, p.url, p.title, p.visit_count, p.last_visit_date
+		FROM moz_places p
+		WHERE p.visit_count >= ?
+			AND p.hidden = 0
+			AND (p.url LIKE 'http://%' OR p.url LIKE 'https://%')
+			AND NOT EXISTS (SELECT 1 FROM moz_bookmarks b WHERE b.fk = p.id)
+		ORDER BY p.visit_count DESC, p.last_visit_date DESC
+		LIMIT ?
+	`
+
+	rows, err := db.conn.QueryContext(ctx, query, minVisits, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query history: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	var places []HistoryPlace
+	for rows.Next() {
+		var place HistoryPlace
+		var title sql.NullString
+		var lastVisit sql.NullInt64
+		if err := rows.Scan(&place.ID, &place.URL, &title, &place.VisitCount, &lastVisit); err != nil {
+			return nil, fmt.Errorf("failed to scan place: %w", Classify(err))
+		}
+		place.Title = title.String
+		if lastVisit.Valid {
+			place.LastVisit = time.Unix(0, lastVisit.Int64*1000)
+		}
+		places = append(places, place)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating history: %w", Classify(err))
+	}
+
+	return places, nil
+}
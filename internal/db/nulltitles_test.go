@@ -0,0 +1,106 @@
+package db_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/levineuwirth/gophermark/internal/db"
+	"github.com/levineuwirth/gophermark/internal/dedup"
+	"github.com/levineuwirth/gophermark/internal/export"
+	"github.com/levineuwirth/gophermark/internal/placestest"
+)
+
+// TestNullTitles follows bookmarks and a folder that Firefox stored with
+// NULL titles through loading, duplicate detection and export.
+func TestNullTitles(t *testing.T) {
+	path := placestest.New(t,
+		`INSERT INTO moz_places (id, url, title, frecency) VALUES
+			(1, 'https://untitled.example/', NULL, 10),
+			(2, 'https://named.example/', 'Named', 5)`,
+		`INSERT INTO moz_bookmarks (id, type, fk, parent, position, title, dateAdded, lastModified, guid) VALUES
+			(10, 1, 1, 3, 0, NULL, 0, 0, 'bookmark0010'),
+			(11, 1, 1, 3, 1, NULL, 0, 0, 'bookmark0011'),
+			(20, 2, NULL, 2, 0, NULL, 0, 0, 'folder000020'),
+			(12, 1, 2, 20, 0, 'Named', 0, 0, 'bookmark0012')`,
+	)
+
+	conn, err := db.OpenReadOnly(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	bookmarks, err := conn.FetchAllBookmarks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range bookmarks {
+		if (b.ID == 10 || b.ID == 11 || b.ID == 20) && b.Title != "" {
+			t.Errorf("bookmark %d title = %q, want empty", b.ID, b.Title)
+		}
+	}
+
+	groups, err := dedup.FindDuplicates(conn.Conn())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 1 || groups[0].URL != "https://untitled.example/" || len(groups[0].Bookmarks) != 2 {
+		t.Fatalf("duplicate groups = %+v, want the two untitled bookmarks", groups)
+	}
+	for _, b := range groups[0].Bookmarks {
+		if b.Title != "" {
+			t.Errorf("duplicate %d title = %q, want empty", b.ID, b.Title)
+		}
+	}
+	if _, err := dedup.FindSimilarTitles(conn.Conn(), 2); err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := db.BuildTree(bookmarks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var html bytes.Buffer
+	if err := export.WriteHTML(&html, root); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`HREF="https://untitled.example/" ADD_DATE="0">(untitled)</A>`,
+		`>(untitled)</H3>`,
+	} {
+		if !strings.Contains(html.String(), want) {
+			t.Errorf("HTML export is missing %q:\n%s", want, html.String())
+		}
+	}
+
+	var markdown bytes.Buffer
+	if err := export.WriteMarkdown(&markdown, root); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"[(untitled)](https://untitled.example/)",
+		"- (untitled)",
+	} {
+		if !strings.Contains(markdown.String(), want) {
+			t.Errorf("Markdown export is missing %q:\n%s", want, markdown.String())
+		}
+	}
+
+	var opml bytes.Buffer
+	if err := export.WriteOPML(&opml, root); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(opml.String(), `text="(untitled)"`); n != 3 {
+		t.Errorf("OPML export has %d untitled outlines, want 3:\n%s", n, opml.String())
+	}
+
+	var json bytes.Buffer
+	if err := export.WriteJSON(&json, root); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(json.String(), `"title": null`) {
+		t.Errorf("JSON export has a null title:\n%s", json.String())
+	}
+}
@@ -1,14 +1,22 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/levineuwirth/gophermark/internal/models"
 )
 
 func (db *DB) FetchAllBookmarks() ([]*models.Bookmark, error) {
+	return db.FetchAllBookmarksContext(context.Background())
+}
+
+// FetchAllBookmarksContext is FetchAllBookmarks, giving up with ctx's error
+// once ctx is done. Large profiles can take seconds to read.
+func (db *DB) FetchAllBookmarksContext(ctx context.Context) ([]*models.Bookmark, error) {
 	query := `
 		SELECT
 			b.id,
@@ -21,15 +29,18 @@ func (db *DB) FetchAllBookmarks() ([]*models.Bookmark, error) {
 			b.lastModified,
 			b.guid,
 			COALESCE(p.url, '') as url,
-			COALESCE(p.visit_count, 0) as visit_count
+			COALESCE(p.visit_count, 0) as visit_count,
+			COALESCE(p.frecency, 0) as frecency,
+			COALESCE(p.hidden, 0) as hidden,
+			COALESCE(p.typed, 0) as typed
 		FROM moz_bookmarks b
 		LEFT JOIN moz_places p ON b.fk = p.id
 		ORDER BY b.parent, b.position
 	`
 
-	rows, err := db.conn.Query(query)
+	rows, err := db.conn.QueryContext(ctx, query)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query bookmarks: %w", err)
+		return nil, fmt.Errorf("failed to query bookmarks: %w", Classify(err))
 	}
 	defer rows.Close()
 
@@ -41,6 +52,7 @@ func (db *DB) FetchAllBookmarks() ([]*models.Bookmark, error) {
 		var title sql.NullString
 		var url sql.NullString
 		var dateAdded, lastModified int64
+		var hidden, typed int
 
 		err := rows.Scan(
 			&b.ID,
@@ -54,9 +66,12 @@ func (db *DB) FetchAllBookmarks() ([]*models.Bookmark, error) {
 			&b.GUID,
 			&url,
 			&b.VisitCount,
+			&b.Frecency,
+			&hidden,
+			&typed,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan bookmark: %w", err)
+			return nil, fmt.Errorf("failed to scan bookmark: %w", Classify(err))
 		}
 
 		if fk.Valid {
@@ -68,6 +83,8 @@ func (db *DB) FetchAllBookmarks() ([]*models.Bookmark, error) {
 		if url.Valid {
 			b.URL = url.String
 		}
+		b.Hidden = hidden != 0
+		b.Typed = typed != 0
 
 		b.DateAdded = time.Unix(0, dateAdded*1000)
 		b.LastModified = time.Unix(0, lastModified*1000)
@@ -78,39 +95,228 @@ func (db *DB) FetchAllBookmarks() ([]*models.Bookmark, error) {
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating bookmarks: %w", err)
+		return nil, fmt.Errorf("error iterating bookmarks: %w", Classify(err))
+	}
+
+	keywords, err := db.fetchKeywords(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := db.fetchTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	descriptions, err := db.fetchDescriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range bookmarks {
+		b.Description = descriptions[b.ID]
+		if b.FK == nil {
+			continue
+		}
+		b.Keywords = keywords[*b.FK]
+		b.Tags = tags[*b.FK]
 	}
 
 	return bookmarks, nil
 }
 
+// fetchKeywords maps place IDs to their keyword shortcuts. Profiles that
+// predate moz_keywords simply have no keywords.
+func (db *DB) fetchKeywords(ctx context.Context) (map[int64][]string, error) {
+	keywords := make(map[int64][]string)
+
+	exists, err := db.tableExists("moz_keywords")
+	if err != nil || !exists {
+		return keywords, err
+	}
+
+	rows, err := db.conn.QueryContext(ctx, "SELECT place_id, keyword FROM moz_keywords WHERE place_id IS NOT NULL ORDER BY keyword")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query keywords: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var placeID int64
+		var keyword string
+		if err := rows.Scan(&placeID, &keyword); err != nil {
+			return nil, fmt.Errorf("failed to scan keyword: %w", Classify(err))
+		}
+		keywords[placeID] = append(keywords[placeID], keyword)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating keywords: %w", Classify(err))
+	}
+
+	return keywords, nil
+}
+
+// fetchTags maps place IDs to tag names. Firefox stores a tag as a folder
+// under the tags root whose children are bookmarks pointing at tagged places.
+func (db *DB) fetchTags(ctx context.Context) (map[int64][]string, error) {
+	query := `
+		SELECT b.fk, t.title
+		FROM moz_bookmarks b
+		INNER JOIN moz_bookmarks t ON b.parent = t.id
+		INNER JOIN moz_bookmarks r ON t.parent = r.id
+		WHERE r.guid = 'tags________'
+			AND b.fk IS NOT NULL
+			AND t.title IS NOT NULL
+		ORDER BY t.title
+	`
+
+	rows, err := db.conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	tags := make(map[int64][]string)
+	for rows.Next() {
+		var placeID int64
+		var tag string
+		if err := rows.Scan(&placeID, &tag); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", Classify(err))
+		}
+		tags[placeID] = append(tags[placeID], tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tags: %w", Classify(err))
+	}
+
+	return tags, nil
+}
+
+// fetchDescriptions maps bookmark IDs to the descriptions Firefox kept as
+// item annotations. Newer profiles dropped the annotation tables, in which
+// case every description is empty.
+func (db *DB) fetchDescriptions(ctx context.Context) (map[int64]string, error) {
+	descriptions := make(map[int64]string)
+
+	for _, table := range []string{"moz_items_annos", "moz_anno_attributes"} {
+		exists, err := db.tableExists(table)
+		if err != nil || !exists {
+			return descriptions, err
+		}
+	}
+
+	query := `
+		SELECT a.item_id, a.content
+		FROM moz_items_annos a
+		INNER JOIN moz_anno_attributes n ON a.anno_attribute_id = n.id
+		WHERE n.name = 'bookmarkProperties/description'
+			AND a.content IS NOT NULL
+	`
+
+	rows, err := db.conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query descriptions: %w", Classify(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var itemID int64
+		var description string
+		if err := rows.Scan(&itemID, &description); err != nil {
+			return nil, fmt.Errorf("failed to scan description: %w", Classify(err))
+		}
+		descriptions[itemID] = description
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating descriptions: %w", Classify(err))
+	}
+
+	return descriptions, nil
+}
+
+func (db *DB) tableExists(name string) (bool, error) {
+	var count int
+	err := db.conn.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", name).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check for table %s: %w", name, Classify(err))
+	}
+	return count > 0, nil
+}
+
+// OrphanedFolderGUID and OrphanedFolderTitle identify the folder
+// BuildTreeWithOrphans makes for bookmarks whose parent is missing. It exists
+// only in the tree: it has no row in moz_bookmarks and a negative ID.
+const (
+	OrphanedFolderGUID  = "orphaned____"
+	OrphanedFolderTitle = "⚠ Orphaned"
+)
+
 func BuildTree(bookmarks []*models.Bookmark) (*models.Bookmark, error) {
+	root, _, err := BuildTreeWithOrphans(bookmarks)
+	return root, err
+}
+
+// BuildTreeWithOrphans is BuildTree that also reports how many bookmarks
+// pointed at a parent that doesn't exist, as happens in corrupted or
+// partially synced databases. Rather than being dropped, they're put in a
+// folder at the end of the root's children (see OrphanedFolderGUID), from
+// where they can be moved back into real folders.
+func BuildTreeWithOrphans(bookmarks []*models.Bookmark) (*models.Bookmark, int, error) {
 	bookmarkMap := make(map[int64]*models.Bookmark)
 	for _, b := range bookmarks {
 		bookmarkMap[b.ID] = b
 	}
 
-	var root *models.Bookmark
+	root := findRoot(bookmarks)
+	if root == nil {
+		return nil, 0, fmt.Errorf("%w: no root bookmark found", ErrCorrupt)
+	}
+
+	var orphans []*models.Bookmark
 	for _, b := range bookmarks {
-		if b.Parent == 0 {
-			root = b
-			break
+		if b == root {
+			continue
+		}
+		if parent, exists := bookmarkMap[b.Parent]; exists {
+			parent.Children = append(parent.Children, b)
+		} else {
+			orphans = append(orphans, b)
 		}
 	}
 
-	if root == nil {
-		return nil, fmt.Errorf("no root bookmark found")
+	if len(orphans) > 0 {
+		root.Children = append(root.Children, &models.Bookmark{
+			ID:       -1,
+			Type:     models.TypeFolder,
+			Parent:   root.ID,
+			Position: len(root.Children),
+			Title:    OrphanedFolderTitle,
+			GUID:     OrphanedFolderGUID,
+			Children: orphans,
+		})
 	}
 
+	return root, len(orphans), nil
+}
+
+// findRoot prefers Firefox's places root by its fixed guid. Older or
+// unusual databases fall back to a row with no parent, then to the row with
+// the smallest parent ID.
+func findRoot(bookmarks []*models.Bookmark) *models.Bookmark {
+	var fallback *models.Bookmark
 	for _, b := range bookmarks {
-		if b.Parent != 0 {
-			if parent, exists := bookmarkMap[b.Parent]; exists {
-				parent.Children = append(parent.Children, b)
-			}
+		if b.GUID == "root________" {
+			return b
+		}
+		if fallback == nil || b.Parent < fallback.Parent ||
+			(b.Parent == fallback.Parent && b.ID < fallback.ID) {
+			fallback = b
 		}
 	}
-
-	return root, nil
+	return fallback
 }
 
 func GetFolders(root *models.Bookmark) []*models.Bookmark {
@@ -130,6 +336,27 @@ func GetFolders(root *models.Bookmark) []*models.Bookmark {
 	return folders
 }
 
+// FolderPaths maps every node ID under root to the " / "-joined titles of
+// the folders containing it. Untitled folders such as the root are skipped.
+func FolderPaths(root *models.Bookmark) map[int64]string {
+	paths := make(map[int64]string)
+
+	var traverse func(*models.Bookmark, []string)
+	traverse = func(node *models.Bookmark, path []string) {
+		paths[node.ID] = strings.Join(path, " / ")
+
+		if node.IsFolder() && node.Title != "" {
+			path = append(path[:len(path):len(path)], node.Title)
+		}
+		for _, child := range node.Children {
+			traverse(child, path)
+		}
+	}
+
+	traverse(root, nil)
+	return paths
+}
+
 func GetBookmarksInFolder(folder *models.Bookmark) []*models.Bookmark {
 	var bookmarks []*models.Bookmark
 
@@ -0,0 +1,125 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/levineuwirth/gophermark/internal/models"
+	"github.com/levineuwirth/gophermark/internal/placestest"
+)
+
+func loadTree(t *testing.T, path string) (*models.Bookmark, int) {
+	t.Helper()
+
+	conn, err := OpenReadOnly(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	bookmarks, err := conn.FetchAllBookmarks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, orphans, err := BuildTreeWithOrphans(bookmarks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root, orphans
+}
+
+func TestBuildTreeFirefoxRoot(t *testing.T) {
+	path := placestest.New(t,
+		`INSERT INTO moz_places (id, url, title) VALUES (1, 'https://example.com/', 'Example')`,
+		`INSERT INTO moz_bookmarks (id, type, fk, parent, position, title, dateAdded, lastModified, guid)
+			VALUES (10, 1, 1, 3, 0, 'Example', 0, 0, 'bookmark0001')`,
+	)
+
+	root, _ := loadTree(t, path)
+	if root.ID != placestest.RootID || root.GUID != "root________" {
+		t.Fatalf("root = %d %q, want %d root________", root.ID, root.GUID, placestest.RootID)
+	}
+
+	var guids []string
+	for _, child := range root.Children {
+		guids = append(guids, child.GUID)
+	}
+	want := []string{"menu________", "toolbar_____", "tags________", "unfiled_____", "mobile______"}
+	if len(guids) != len(want) {
+		t.Fatalf("root children = %v, want %v", guids, want)
+	}
+	for i := range want {
+		if guids[i] != want[i] {
+			t.Fatalf("root children = %v, want %v", guids, want)
+		}
+	}
+
+	toolbar := root.Children[1]
+	if len(toolbar.Children) != 1 || toolbar.Children[0].URL != "https://example.com/" {
+		t.Fatalf("toolbar children = %v, want the example bookmark", toolbar.Children)
+	}
+}
+
+func TestFindRoot(t *testing.T) {
+	tests := []struct {
+		name      string
+		bookmarks []*models.Bookmark
+		want      int64
+	}{
+		{
+			name: "places root guid, listed after its children",
+			bookmarks: []*models.Bookmark{
+				{ID: 2, Parent: 1, GUID: "menu________"},
+				{ID: 7, Parent: 0, GUID: "somethingelse"},
+				{ID: 1, Parent: 0, GUID: "root________"},
+			},
+			want: 1,
+		},
+		{
+			name: "no guid, row without a parent",
+			bookmarks: []*models.Bookmark{
+				{ID: 5, Parent: 3},
+				{ID: 3, Parent: 0},
+			},
+			want: 3,
+		},
+		{
+			name: "no row without a parent",
+			bookmarks: []*models.Bookmark{
+				{ID: 12, Parent: 11},
+				{ID: 11, Parent: 9},
+				{ID: 10, Parent: 9},
+			},
+			want: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := findRoot(tt.bookmarks)
+			if root == nil || root.ID != tt.want {
+				t.Fatalf("findRoot = %v, want id %d", root, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildTreeWithOrphans(t *testing.T) {
+	path := placestest.New(t,
+		`INSERT INTO moz_places (id, url, title) VALUES (1, 'https://lost.example/', 'Lost')`,
+		`INSERT INTO moz_bookmarks (id, type, fk, parent, position, title, dateAdded, lastModified, guid)
+			VALUES (10, 1, 1, 999, 0, 'Lost', 0, 0, 'bookmark0010')`,
+	)
+
+	root, orphans := loadTree(t, path)
+	if orphans != 1 {
+		t.Fatalf("orphans = %d, want 1", orphans)
+	}
+
+	folder := root.Children[len(root.Children)-1]
+	if folder.GUID != OrphanedFolderGUID || folder.Title != OrphanedFolderTitle {
+		t.Fatalf("last root child = %q %q, want the orphaned folder", folder.GUID, folder.Title)
+	}
+	if len(folder.Children) != 1 || folder.Children[0].ID != 10 {
+		t.Fatalf("orphaned folder holds %v, want bookmark 10", folder.Children)
+	}
+}
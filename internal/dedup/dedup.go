@@ -4,36 +4,115 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"sort"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/levineuwirth/gophermark/internal/fuzzy"
 	"github.com/levineuwirth/gophermark/internal/models"
 )
 
 var debugLog *log.Logger
 
+// SetDebugLog directs debug output to w. Logging is off by default; pass
+// nil to turn it back off.
+func SetDebugLog(w io.Writer) {
+	if w == nil {
+		debugLog = nil
+		return
+	}
+	debugLog = log.New(w, "", log.Ltime|log.Lmicroseconds|log.Lshortfile)
+}
+
 func init() {
-	f, err := os.OpenFile("/tmp/gophermark-debug.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
-	if err == nil {
-		debugLog = log.New(f, "", log.Ltime|log.Lmicroseconds|log.Lshortfile)
+	if path := os.Getenv("GOPHERMARK_DEBUG"); path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err == nil {
+			SetDebugLog(f)
+		}
 	}
 }
 
+const DefaultTimeout = 30 * time.Second
+
+// progressInterval is how many rows are scanned between progress reports.
+const progressInterval = 500
+
+// maxTitleComparisons caps the pairwise work done by FindSimilarTitles so
+// very large collections still finish in reasonable time.
+const maxTitleComparisons = 250000
+
+// minSimilarTitleLength skips short titles like "Home" that would match
+// each other without meaning the pages are the same.
+const minSimilarTitleLength = 5
+
 type DuplicateGroup struct {
 	URL       string
+	Title     string // set for groups found by title similarity
 	Bookmarks []*models.Bookmark
+	Frecency  map[int64]int // moz_places.frecency by bookmark ID
+
+	// AcrossFolders is set when the bookmarks are filed in more than one
+	// folder, which may be deliberate. Copies within one folder are
+	// plainly redundant.
+	AcrossFolders bool
+}
+
+// KeepIndex suggests which bookmark to keep: the one with the highest
+// frecency, which accounts for how recently a page was used and not just
+// how often. Ties go to the earliest bookmark.
+func (g DuplicateGroup) KeepIndex() int {
+	best := 0
+	for i, b := range g.Bookmarks {
+		if g.Frecency[b.ID] > g.Frecency[g.Bookmarks[best].ID] {
+			best = i
+		}
+	}
+	return best
+}
+
+func newGroup(url, title string, bookmarks []*models.Bookmark, frecency map[int64]int) DuplicateGroup {
+	group := DuplicateGroup{
+		URL:       url,
+		Title:     title,
+		Bookmarks: bookmarks,
+		Frecency:  make(map[int64]int, len(bookmarks)),
+	}
+	for _, b := range bookmarks {
+		group.Frecency[b.ID] = frecency[b.ID]
+		if b.Parent != bookmarks[0].Parent {
+			group.AcrossFolders = true
+		}
+	}
+	return group
+}
+
+type ScanOptions struct {
+	Timeout  time.Duration         // defaults to DefaultTimeout
+	Progress func(rowsScanned int) // called periodically from the scanning goroutine
 }
 
 func FindDuplicates(db *sql.DB) ([]DuplicateGroup, error) {
+	return FindDuplicatesWithOptions(db, ScanOptions{})
+}
+
+func FindDuplicatesWithOptions(db *sql.DB, opts ScanOptions) ([]DuplicateGroup, error) {
 	if debugLog != nil {
 		debugLog.Println("FindDuplicates: entering function")
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	if debugLog != nil {
-		debugLog.Println("FindDuplicates: context created with 30s timeout")
+		debugLog.Printf("FindDuplicates: context created with %s timeout", timeout)
 	}
 
 	query := `
@@ -56,7 +135,8 @@ func FindDuplicates(db *sql.DB) ([]DuplicateGroup, error) {
 			b.dateAdded,
 			b.lastModified,
 			b.guid,
-			COALESCE(p.visit_count, 0)
+			COALESCE(p.visit_count, 0),
+			COALESCE(p.frecency, 0)
 		FROM moz_places p
 		INNER JOIN moz_bookmarks b ON b.fk = p.id
 		INNER JOIN duplicate_urls d ON d.url = p.url
@@ -79,35 +159,22 @@ func FindDuplicates(db *sql.DB) ([]DuplicateGroup, error) {
 	}
 
 	urlMap := make(map[string][]*models.Bookmark)
+	frecency := make(map[int64]int)
+	rowsScanned := 0
 
 	for rows.Next() {
-		var url string
-		var b models.Bookmark
-		var fk sql.NullInt64
-
-		err := rows.Scan(
-			&url,
-			&b.ID,
-			&b.Type,
-			&fk,
-			&b.Parent,
-			&b.Position,
-			&b.Title,
-			&b.DateAdded,
-			&b.LastModified,
-			&b.GUID,
-			&b.VisitCount,
-		)
+		b, score, err := scanBookmark(rows)
 		if err != nil {
-			return nil, fmt.Errorf("scan failed: %w", err)
+			return nil, err
 		}
+		frecency[b.ID] = score
 
-		if fk.Valid {
-			b.FK = &fk.Int64
-		}
-		b.URL = url
+		urlMap[b.URL] = append(urlMap[b.URL], b)
 
-		urlMap[url] = append(urlMap[url], &b)
+		rowsScanned++
+		if opts.Progress != nil && rowsScanned%progressInterval == 0 {
+			opts.Progress(rowsScanned)
+		}
 	}
 
 	if err := rows.Err(); err != nil {
@@ -117,6 +184,10 @@ func FindDuplicates(db *sql.DB) ([]DuplicateGroup, error) {
 		return nil, fmt.Errorf("rows error: %w", err)
 	}
 
+	if opts.Progress != nil {
+		opts.Progress(rowsScanned)
+	}
+
 	if debugLog != nil {
 		debugLog.Printf("FindDuplicates: finished processing rows, found %d URLs", len(urlMap))
 	}
@@ -124,10 +195,7 @@ func FindDuplicates(db *sql.DB) ([]DuplicateGroup, error) {
 	var groups []DuplicateGroup
 	for url, bookmarks := range urlMap {
 		if len(bookmarks) > 1 {
-			groups = append(groups, DuplicateGroup{
-				URL:       url,
-				Bookmarks: bookmarks,
-			})
+			groups = append(groups, newGroup(url, "", bookmarks, frecency))
 		}
 	}
 
@@ -137,3 +205,168 @@ func FindDuplicates(db *sql.DB) ([]DuplicateGroup, error) {
 
 	return groups, nil
 }
+
+// FindSimilarTitles groups bookmarks with different URLs whose titles are
+// within threshold edits of each other. Comparisons stop once
+// maxTitleComparisons is reached, so results on huge databases may be partial.
+func FindSimilarTitles(db *sql.DB, threshold int) ([]DuplicateGroup, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+
+	query := `
+		SELECT
+			p.url,
+			b.id,
+			b.type,
+			b.fk,
+			b.parent,
+			b.position,
+			COALESCE(b.title, ''),
+			b.dateAdded,
+			b.lastModified,
+			b.guid,
+			COALESCE(p.visit_count, 0),
+			COALESCE(p.frecency, 0)
+		FROM moz_bookmarks b
+		INNER JOIN moz_places p ON b.fk = p.id
+		WHERE b.type = 1 AND b.title IS NOT NULL AND length(trim(b.title)) >= ?
+		ORDER BY length(trim(b.title)), b.id
+	`
+
+	rows, err := db.QueryContext(ctx, query, minSimilarTitleLength)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var bookmarks []*models.Bookmark
+	frecency := make(map[int64]int)
+	for rows.Next() {
+		b, score, err := scanBookmark(rows)
+		if err != nil {
+			return nil, err
+		}
+		bookmarks = append(bookmarks, b)
+		frecency[b.ID] = score
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	// Titles are compared trimmed and lower-cased. SQLite's trim only strips
+	// spaces, so sort again by the length of the compared form; the cutoff
+	// below relies on this order.
+	normalize := func(b *models.Bookmark) string {
+		return strings.ToLower(strings.TrimSpace(b.Title))
+	}
+	sort.SliceStable(bookmarks, func(i, j int) bool {
+		return utf8.RuneCountInString(normalize(bookmarks[i])) < utf8.RuneCountInString(normalize(bookmarks[j]))
+	})
+
+	titles := make([]string, len(bookmarks))
+	lengths := make([]int, len(bookmarks))
+	for i, b := range bookmarks {
+		titles[i] = normalize(b)
+		lengths[i] = utf8.RuneCountInString(titles[i])
+	}
+
+	// Union-find over bookmark indices so chains of similar titles end up
+	// in a single group.
+	parent := make([]int, len(bookmarks))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	comparisons := 0
+compare:
+	for i := range bookmarks {
+		// Rows are ordered by length, so once the length gap exceeds the
+		// threshold no later title can be close enough.
+		for j := i + 1; j < len(bookmarks); j++ {
+			if lengths[j]-lengths[i] > threshold {
+				break
+			}
+			if comparisons >= maxTitleComparisons {
+				break compare
+			}
+			comparisons++
+
+			if titles[i] == titles[j] || fuzzy.LevenshteinDistance(titles[i], titles[j]) <= threshold {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	members := make(map[int][]*models.Bookmark)
+	var roots []int
+	for i, b := range bookmarks {
+		root := find(i)
+		if _, ok := members[root]; !ok {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], b)
+	}
+
+	var groups []DuplicateGroup
+	for _, root := range roots {
+		group := members[root]
+		if len(group) < 2 || sameURL(group) {
+			continue
+		}
+		groups = append(groups, newGroup("", group[0].Title, group, frecency))
+	}
+
+	return groups, nil
+}
+
+// sameURL reports whether every bookmark shares one URL; those groups are
+// already reported by FindDuplicates.
+func sameURL(bookmarks []*models.Bookmark) bool {
+	for _, b := range bookmarks[1:] {
+		if b.URL != bookmarks[0].URL {
+			return false
+		}
+	}
+	return true
+}
+
+func scanBookmark(rows *sql.Rows) (*models.Bookmark, int, error) {
+	var b models.Bookmark
+	var fk sql.NullInt64
+	var dateAdded, lastModified int64
+	var frecency int
+
+	err := rows.Scan(
+		&b.URL,
+		&b.ID,
+		&b.Type,
+		&fk,
+		&b.Parent,
+		&b.Position,
+		&b.Title,
+		&dateAdded,
+		&lastModified,
+		&b.GUID,
+		&b.VisitCount,
+		&frecency,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("scan failed: %w", err)
+	}
+
+	if fk.Valid {
+		b.FK = &fk.Int64
+	}
+	b.DateAdded = time.Unix(0, dateAdded*1000)
+	b.LastModified = time.Unix(0, lastModified*1000)
+
+	return &b, frecency, nil
+}
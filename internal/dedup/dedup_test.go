@@ -0,0 +1,50 @@
+package dedup
+
+import (
+	"testing"
+
+	"github.com/levineuwirth/gophermark/internal/db"
+	"github.com/levineuwirth/gophermark/internal/placestest"
+)
+
+func TestFindSimilarTitles(t *testing.T) {
+	path := placestest.New(t,
+		`INSERT INTO moz_places (id, url, title) VALUES
+			(1, 'https://a.example/', 'Go Blog'),
+			(2, 'https://b.example/', 'Go Blog'),
+			(3, 'https://c.example/', 'ニュース'),
+			(4, 'https://d.example/', 'ニュース'),
+			(5, 'https://e.example/', 'Something else entirely')`,
+		`INSERT INTO moz_bookmarks (id, type, fk, parent, position, title, dateAdded, lastModified, guid) VALUES
+			(10, 1, 1, 3, 0, 'Go Blog', 0, 0, 'bookmark0010'),
+			(11, 1, 2, 3, 1, '   Go Blog     ', 0, 0, 'bookmark0011'),
+			(12, 1, 3, 3, 2, '日本語のニュース', 0, 0, 'bookmark0012'),
+			(13, 1, 4, 3, 3, '日本語のニュー', 0, 0, 'bookmark0013'),
+			(14, 1, 5, 3, 4, 'Something else entirely', 0, 0, 'bookmark0014')`,
+	)
+
+	conn, err := db.OpenReadOnly(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	groups, err := FindSimilarTitles(conn.Conn(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[int64]int64{10: 11, 11: 10, 12: 13, 13: 12}
+	if len(groups) != 2 {
+		t.Fatalf("got %d groups, want 2: %+v", len(groups), groups)
+	}
+	for _, group := range groups {
+		if len(group.Bookmarks) != 2 {
+			t.Fatalf("group has %d bookmarks, want 2", len(group.Bookmarks))
+		}
+		a, b := group.Bookmarks[0].ID, group.Bookmarks[1].ID
+		if want[a] != b {
+			t.Errorf("bookmarks %d and %d grouped together", a, b)
+		}
+	}
+}
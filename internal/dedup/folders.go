@@ -0,0 +1,93 @@
+package dedup
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/levineuwirth/gophermark/internal/models"
+)
+
+// FolderGroup is a set of folders with the same title, as left behind by a
+// sync that created a folder twice.
+type FolderGroup struct {
+	Title   string
+	Folders []*models.Bookmark // the suggested folder to keep first
+}
+
+// FindDuplicateFolders groups the folders under root whose titles match,
+// ignoring case and surrounding spaces. Only folders sharing a parent are
+// grouped unless anywhere is set. Untitled folders, Firefox's built-in
+// containers and the tags subtree are skipped.
+//
+// Within a group the folder holding the most bookmarks comes first, the
+// earliest created on a tie; it's the natural one to merge the others into.
+func FindDuplicateFolders(root *models.Bookmark, anywhere bool) []FolderGroup {
+	type key struct {
+		parent int64
+		title  string
+	}
+	byKey := make(map[key][]*models.Bookmark)
+	var order []key
+
+	var visit func(*models.Bookmark)
+	visit = func(node *models.Bookmark) {
+		if node.GUID == "tags________" {
+			return
+		}
+		if node.IsFolder() && !node.IsBuiltinFolder() && strings.TrimSpace(node.Title) != "" {
+			k := key{title: strings.ToLower(strings.TrimSpace(node.Title))}
+			if !anywhere {
+				k.parent = node.Parent
+			}
+			if _, seen := byKey[k]; !seen {
+				order = append(order, k)
+			}
+			byKey[k] = append(byKey[k], node)
+		}
+		for _, child := range node.Children {
+			if child.IsFolder() {
+				visit(child)
+			}
+		}
+	}
+	visit(root)
+
+	var groups []FolderGroup
+	for _, k := range order {
+		folders := byKey[k]
+		if len(folders) < 2 {
+			continue
+		}
+		sort.SliceStable(folders, func(i, j int) bool {
+			ci, cj := countBookmarks(folders[i]), countBookmarks(folders[j])
+			if ci != cj {
+				return ci > cj
+			}
+			return folders[i].DateAdded.Before(folders[j].DateAdded)
+		})
+		groups = append(groups, FolderGroup{Title: strings.TrimSpace(folders[0].Title), Folders: folders})
+	}
+	return groups
+}
+
+// IsDescendant reports whether node lies somewhere inside folder.
+func IsDescendant(folder, node *models.Bookmark) bool {
+	for _, child := range folder.Children {
+		if child == node || (child.IsFolder() && IsDescendant(child, node)) {
+			return true
+		}
+	}
+	return false
+}
+
+func countBookmarks(folder *models.Bookmark) int {
+	count := 0
+	for _, child := range folder.Children {
+		if child.IsFolder() {
+			count += countBookmarks(child)
+		} else if child.IsBookmark() {
+			count++
+		}
+	}
+	return count
+}
@@ -1,9 +1,12 @@
 package export
 
 import (
+	"bufio"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"html"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -11,40 +14,123 @@ import (
 	"github.com/levineuwirth/gophermark/internal/models"
 )
 
+// BookmarkExport is one node of a JSON export. GUID and ID identify the
+// row in places.sqlite so a restore can update existing bookmarks instead of
+// duplicating them; nodes that don't come from the database leave them out.
 type BookmarkExport struct {
-	Title     string           `json:"title"`
-	URL       string           `json:"url,omitempty"`
-	Type      string           `json:"type"`
-	Children  []BookmarkExport `json:"children,omitempty"`
-	DateAdded string           `json:"dateAdded,omitempty"`
+	GUID        string           `json:"guid,omitempty"`
+	ID          int64            `json:"id,omitempty"`
+	Title       string           `json:"title"`
+	URL         string           `json:"url,omitempty"`
+	Description string           `json:"description,omitempty"`
+	Type        string           `json:"type"`
+	Children    []BookmarkExport `json:"children,omitempty"`
+	DateAdded   string           `json:"dateAdded,omitempty"`
 }
 
-func ExportJSON(root *models.Bookmark, outputPath string) error {
-	exported := convertToExport(root)
+// BookmarkLine is one line of a JSON Lines export.
+type BookmarkLine struct {
+	Title       string   `json:"title"`
+	URL         string   `json:"url"`
+	FolderPath  string   `json:"folderPath"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Keywords    []string `json:"keywords,omitempty"`
+	DateAdded   string   `json:"dateAdded,omitempty"`
+}
 
-	file, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
-	}
-	defer file.Close()
+type opmlDocument struct {
+	XMLName xml.Name    `xml:"opml"`
+	Version string      `xml:"version,attr"`
+	Head    opmlHead    `xml:"head"`
+	Body    opmlOutline `xml:"body"`
+}
+
+type opmlHead struct {
+	Title       string `xml:"title"`
+	DateCreated string `xml:"dateCreated"`
+}
 
-	encoder := json.NewEncoder(file)
+type opmlOutline struct {
+	Text     string        `xml:"text,attr,omitempty"`
+	Title    string        `xml:"title,attr,omitempty"`
+	Type     string        `xml:"type,attr,omitempty"`
+	XMLURL   string        `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string        `xml:"htmlUrl,attr,omitempty"`
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+func ExportJSON(root *models.Bookmark, outputPath string) error {
+	return writeFile(outputPath, func(w io.Writer) error { return WriteJSON(w, root) })
+}
+
+// WriteJSON is ExportJSON writing to w.
+func WriteJSON(w io.Writer, root *models.Bookmark) error {
+	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(exported); err != nil {
+	if err := encoder.Encode(convertToExport(root)); err != nil {
 		return fmt.Errorf("failed to encode JSON: %w", err)
 	}
+	return nil
+}
+
+// ExportJSONL writes one compact JSON object per bookmark, each carrying
+// its folder path, encoding as it walks the tree so memory use doesn't grow
+// with the collection. Folders and separators are implied by the paths and
+// left out, as are the tag folders, which only mirror real bookmarks.
+func ExportJSONL(root *models.Bookmark, outputPath string) error {
+	return writeFile(outputPath, func(w io.Writer) error { return WriteJSONL(w, root) })
+}
 
+// WriteJSONL is ExportJSONL writing to w.
+func WriteJSONL(w io.Writer, root *models.Bookmark) error {
+	encoder := json.NewEncoder(w)
+
+	var walk func(*models.Bookmark, []string) error
+	walk = func(b *models.Bookmark, path []string) error {
+		if b.GUID == "tags________" {
+			return nil
+		}
+
+		if b.IsBookmark() {
+			line := BookmarkLine{
+				Title:       b.Title,
+				URL:         b.URL,
+				FolderPath:  strings.Join(path, " / "),
+				Description: b.Description,
+				Tags:        b.Tags,
+				Keywords:    b.Keywords,
+			}
+			if !b.DateAdded.IsZero() {
+				line.DateAdded = b.DateAdded.Format(time.RFC3339)
+			}
+			return encoder.Encode(line)
+		}
+
+		if b.IsFolder() && b.Title != "" {
+			path = append(path[:len(path):len(path)], b.Title)
+		}
+		for _, child := range b.Children {
+			if err := walk(child, path); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := walk(root, nil); err != nil {
+		return fmt.Errorf("failed to encode JSON Lines: %w", err)
+	}
 	return nil
 }
 
 func ExportHTML(root *models.Bookmark, outputPath string) error {
-	file, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
-	}
-	defer file.Close()
+	return writeFile(outputPath, func(w io.Writer) error { return WriteHTML(w, root) })
+}
 
-	fmt.Fprintf(file, `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+// WriteHTML is ExportHTML writing to w.
+func WriteHTML(w io.Writer, root *models.Bookmark) error {
+	fmt.Fprintf(w, `<!DOCTYPE NETSCAPE-Bookmark-file-1>
 <!-- This is an automatically generated file.
      It will be read and overwritten.
      DO NOT EDIT! -->
@@ -54,18 +140,84 @@ func ExportHTML(root *models.Bookmark, outputPath string) error {
 <DL><p>
 `)
 
-	writeHTMLBookmarks(file, root, 1)
+	writeHTMLBookmarks(w, root, 1)
+
+	fmt.Fprintf(w, "</DL><p>\n")
 
-	fmt.Fprintf(file, "</DL><p>\n")
+	return nil
+}
+
+func ExportMarkdown(root *models.Bookmark, outputPath string) error {
+	return writeFile(outputPath, func(w io.Writer) error { return WriteMarkdown(w, root) })
+}
+
+// WriteMarkdown is ExportMarkdown writing to w.
+func WriteMarkdown(w io.Writer, root *models.Bookmark) error {
+	fmt.Fprintf(w, "# Bookmarks\n\n")
+
+	writeMarkdownBookmarks(w, root, 0)
+
+	return nil
+}
+
+func ExportOPML(root *models.Bookmark, outputPath string) error {
+	return writeFile(outputPath, func(w io.Writer) error { return WriteOPML(w, root) })
+}
+
+// WriteOPML is ExportOPML writing to w.
+func WriteOPML(w io.Writer, root *models.Bookmark) error {
+	doc := opmlDocument{
+		Version: "2.0",
+		Head: opmlHead{
+			Title:       "Bookmarks",
+			DateCreated: time.Now().Format(time.RFC1123Z),
+		},
+		Body: convertToOPML(root),
+	}
+	// The root is the body itself, which has no title.
+	doc.Body.Text, doc.Body.Title = "", ""
+
+	fmt.Fprint(w, xml.Header)
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode OPML: %w", err)
+	}
+
+	fmt.Fprintln(w)
+
+	return nil
+}
+
+// writeFile creates outputPath and hands write a buffered writer for it.
+func writeFile(outputPath string, write func(io.Writer) error) error {
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	if err := write(writer); err != nil {
+		return err
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
 
 	return nil
 }
 
 func convertToExport(b *models.Bookmark) BookmarkExport {
 	export := BookmarkExport{
-		Title:     b.Title,
-		URL:       b.URL,
-		DateAdded: b.DateAdded.Format(time.RFC3339),
+		GUID:        b.GUID,
+		ID:          b.ID,
+		Title:       b.Title,
+		URL:         b.URL,
+		Description: b.Description,
+		DateAdded:   b.DateAdded.Format(time.RFC3339),
 	}
 
 	if b.IsFolder() {
@@ -74,6 +226,8 @@ func convertToExport(b *models.Bookmark) BookmarkExport {
 		for _, child := range b.Children {
 			export.Children = append(export.Children, convertToExport(child))
 		}
+	} else if b.IsSeparator() {
+		export.Type = "separator"
 	} else {
 		export.Type = "bookmark"
 	}
@@ -81,29 +235,115 @@ func convertToExport(b *models.Bookmark) BookmarkExport {
 	return export
 }
 
-func writeHTMLBookmarks(file *os.File, b *models.Bookmark, depth int) {
+func writeHTMLBookmarks(w io.Writer, b *models.Bookmark, depth int) {
 	indent := strings.Repeat("    ", depth)
 
 	if b.IsFolder() {
-		if b.Title != "" {
+		// The root has no title of its own; its children go straight into
+		// the document's top-level list.
+		isRoot := depth == 1 && b.Title == ""
+		if !isRoot {
 			addDate := b.DateAdded.Unix()
-			fmt.Fprintf(file, "%s<DT><H3 ADD_DATE=\"%d\">%s</H3>\n", indent, addDate, html.EscapeString(b.Title))
-			fmt.Fprintf(file, "%s<DL><p>\n", indent)
+			fmt.Fprintf(w, "%s<DT><H3 ADD_DATE=\"%d\">%s</H3>\n", indent, addDate, html.EscapeString(b.DisplayTitle()))
+			fmt.Fprintf(w, "%s<DL><p>\n", indent)
 		}
 
 		for _, child := range b.Children {
-			writeHTMLBookmarks(file, child, depth+1)
+			writeHTMLBookmarks(w, child, depth+1)
 		}
 
-		if b.Title != "" {
-			fmt.Fprintf(file, "%s</DL><p>\n", indent)
+		if !isRoot {
+			fmt.Fprintf(w, "%s</DL><p>\n", indent)
 		}
+	} else if b.IsSeparator() {
+		fmt.Fprintf(w, "%s<HR>\n", indent)
 	} else {
+		title := b.DisplayTitle()
 		addDate := b.DateAdded.Unix()
-		fmt.Fprintf(file, "%s<DT><A HREF=\"%s\" ADD_DATE=\"%d\">%s</A>\n",
+		fmt.Fprintf(w, "%s<DT><A HREF=\"%s\" ADD_DATE=\"%d\">%s</A>\n",
 			indent,
 			html.EscapeString(b.URL),
 			addDate,
-			html.EscapeString(b.Title))
+			html.EscapeString(title))
+		if b.Description != "" {
+			fmt.Fprintf(w, "%s<DD>%s\n", indent, html.EscapeString(b.Description))
+		}
+	}
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"[", `\[`,
+	"]", `\]`,
+	"<", `\<`,
+	">", `\>`,
+	"|", `\|`,
+)
+
+var markdownURLEscaper = strings.NewReplacer(
+	" ", "%20",
+	"(", "%28",
+	")", "%29",
+)
+
+// writeMarkdownBookmarks renders top-level folders as headings and anything
+// deeper as nested bullets, indenting two spaces per level below the heading.
+func writeMarkdownBookmarks(w io.Writer, b *models.Bookmark, depth int) {
+	if b.IsFolder() {
+		if depth == 1 {
+			fmt.Fprintf(w, "## %s\n\n", markdownEscaper.Replace(b.DisplayTitle()))
+		} else if depth > 1 {
+			indent := strings.Repeat("  ", depth-2)
+			fmt.Fprintf(w, "%s- %s\n", indent, markdownEscaper.Replace(b.DisplayTitle()))
+		}
+
+		for _, child := range b.Children {
+			writeMarkdownBookmarks(w, child, depth+1)
+		}
+
+		if depth == 1 && len(b.Children) > 0 {
+			fmt.Fprintf(w, "\n")
+		}
+		return
+	}
+
+	if !b.IsBookmark() {
+		return
+	}
+
+	indent := ""
+	if depth > 2 {
+		indent = strings.Repeat("  ", depth-2)
+	}
+
+	title := b.DisplayTitle()
+
+	fmt.Fprintf(w, "%s- [%s](%s)\n", indent, markdownEscaper.Replace(title), markdownURLEscaper.Replace(b.URL))
+}
+
+// convertToOPML maps folders to titled outlines and bookmarks to feed
+// outlines. The feed URL is unknown, so xmlUrl repeats the page URL.
+func convertToOPML(b *models.Bookmark) opmlOutline {
+	if b.IsFolder() {
+		outline := opmlOutline{
+			Text:  b.DisplayTitle(),
+			Title: b.DisplayTitle(),
+		}
+		for _, child := range b.Children {
+			if child.IsFolder() || child.IsBookmark() {
+				outline.Outlines = append(outline.Outlines, convertToOPML(child))
+			}
+		}
+		return outline
+	}
+
+	return opmlOutline{
+		Text:    b.DisplayTitle(),
+		Type:    "rss",
+		XMLURL:  b.URL,
+		HTMLURL: b.URL,
 	}
 }
@@ -0,0 +1,148 @@
+package export
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/levineuwirth/gophermark/internal/models"
+)
+
+// viewerHead is the top of the page written by ExportHTMLViewer. The styles
+// are inline so the page works offline with nothing else beside it.
+const viewerHead = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Bookmarks</title>
+<style>
+body { font: 15px/1.5 system-ui, sans-serif; max-width: 60em; margin: 2em auto; padding: 0 1em; color: #222; background: #fff; }
+@media (prefers-color-scheme: dark) { body { color: #ddd; background: #1e1e1e; } a { color: #8ab4f8; } }
+header { position: sticky; top: 0; padding: .5em 0; background: inherit; }
+#filter { width: 100%; box-sizing: border-box; padding: .4em .6em; font: inherit; }
+#count { font-size: .85em; opacity: .7; }
+ul { list-style: none; margin: 0; padding-left: 1.2em; }
+body > ul { padding-left: 0; }
+summary { cursor: pointer; font-weight: 600; }
+.url { font-size: .8em; opacity: .6; margin-left: .5em; word-break: break-all; }
+.description { font-size: .85em; opacity: .8; }
+hr { border: 0; border-top: 1px solid #8884; margin: .3em 0; }
+.hidden { display: none; }
+</style>
+</head>
+<body>
+<header>
+<h1>Bookmarks</h1>
+<input id="filter" type="search" placeholder="Filter by title, URL or tag..." autofocus>
+<div id="count"></div>
+</header>
+`
+
+// viewerScript hides bookmarks that don't match every word typed into the
+// filter, along with folders left with nothing to show, and opens the
+// folders that do match. Clearing the filter restores the folders' state.
+const viewerScript = `<script>
+(function () {
+  var input = document.getElementById("filter");
+  var count = document.getElementById("count");
+  var folders = Array.prototype.slice.call(document.querySelectorAll("details"));
+  var saved = folders.map(function (d) { return d.open; });
+  var items = Array.prototype.slice.call(document.querySelectorAll("li.bookmark"));
+
+  function update() {
+    var words = input.value.toLowerCase().split(/\s+/).filter(Boolean);
+    var shown = 0;
+    items.forEach(function (li) {
+      var text = li.getAttribute("data-search");
+      var match = words.every(function (w) { return text.indexOf(w) !== -1; });
+      li.classList.toggle("hidden", !match);
+      if (match) shown++;
+    });
+    document.querySelectorAll("li.separator").forEach(function (li) {
+      li.classList.toggle("hidden", words.length > 0);
+    });
+    for (var i = folders.length - 1; i >= 0; i--) {
+      var li = folders[i].parentNode;
+      var any = li.querySelector("li.bookmark:not(.hidden)") !== null;
+      li.classList.toggle("hidden", words.length > 0 && !any);
+      folders[i].open = words.length > 0 ? any : saved[i];
+    }
+    count.textContent = words.length > 0 ? shown + " of " + items.length + " bookmarks" : items.length + " bookmarks";
+  }
+
+  input.addEventListener("input", update);
+  update();
+})();
+</script>
+`
+
+// ExportHTMLViewer writes a standalone page for browsing bookmarks: folders
+// as collapsible lists and a search box that filters them as you type. Unlike
+// ExportHTML it isn't meant to be imported back into a browser.
+func ExportHTMLViewer(root *models.Bookmark, outputPath string) error {
+	return writeFile(outputPath, func(w io.Writer) error { return WriteHTMLViewer(w, root) })
+}
+
+// WriteHTMLViewer is ExportHTMLViewer writing to w.
+func WriteHTMLViewer(w io.Writer, root *models.Bookmark) error {
+	fmt.Fprint(w, viewerHead)
+	fmt.Fprintln(w, "<ul>")
+	// The root has no title of its own; its children are the top level.
+	// The tags folder only repeats bookmarks found elsewhere, so it's left
+	// out; tags are matched by the filter instead.
+	for _, child := range root.Children {
+		if child.GUID == "tags________" {
+			continue
+		}
+		writeViewerBookmarks(w, child, 1)
+	}
+	fmt.Fprintln(w, "</ul>")
+	fmt.Fprintf(w, "<footer><p><small>Exported %s</small></p></footer>\n",
+		html.EscapeString(time.Now().Format("2006-01-02 15:04")))
+	fmt.Fprint(w, viewerScript)
+	fmt.Fprintln(w, "</body>\n</html>")
+
+	return nil
+}
+
+// writeViewerBookmarks writes b as a list item. Top-level folders start
+// open and deeper ones closed.
+func writeViewerBookmarks(w io.Writer, b *models.Bookmark, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	if b.IsFolder() {
+		open := ""
+		if depth == 1 {
+			open = " open"
+		}
+		fmt.Fprintf(w, "%s<li class=\"folder\"><details%s><summary>%s</summary>\n", indent, open, html.EscapeString(b.DisplayTitle()))
+		fmt.Fprintf(w, "%s<ul>\n", indent)
+		for _, child := range b.Children {
+			writeViewerBookmarks(w, child, depth+1)
+		}
+		fmt.Fprintf(w, "%s</ul></details></li>\n", indent)
+		return
+	}
+
+	if b.IsSeparator() {
+		fmt.Fprintf(w, "%s<li class=\"separator\"><hr></li>\n", indent)
+		return
+	}
+
+	title := b.DisplayTitle()
+	search := strings.ToLower(strings.Join([]string{title, b.URL, b.Description, strings.Join(b.Tags, " ")}, " "))
+
+	fmt.Fprintf(w, "%s<li class=\"bookmark\" data-search=\"%s\"><a href=\"%s\">%s</a><span class=\"url\">%s</span>",
+		indent,
+		html.EscapeString(search),
+		html.EscapeString(b.URL),
+		html.EscapeString(title),
+		html.EscapeString(b.URL))
+	if b.Description != "" {
+		fmt.Fprintf(w, "<div class=\"description\">%s</div>", html.EscapeString(b.Description))
+	}
+	fmt.Fprintln(w, "</li>")
+}
@@ -0,0 +1,74 @@
+package fuzzy
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// LevenshteinDistance returns the case-insensitive edit distance between
+// two strings, counted in runes so a CJK character or an emoji is one edit.
+func LevenshteinDistance(s1, s2 string) int {
+	r1 := []rune(strings.ToLower(s1))
+	r2 := []rune(strings.ToLower(s2))
+
+	if len(r1) == 0 {
+		return len(r2)
+	}
+	if len(r2) == 0 {
+		return len(r1)
+	}
+
+	matrix := make([][]int, len(r1)+1)
+	for i := range matrix {
+		matrix[i] = make([]int, len(r2)+1)
+	}
+
+	for i := 0; i <= len(r1); i++ {
+		matrix[i][0] = i
+	}
+	for j := 0; j <= len(r2); j++ {
+		matrix[0][j] = j
+	}
+
+	for i := 1; i <= len(r1); i++ {
+		for j := 1; j <= len(r2); j++ {
+			cost := 1
+			if r1[i-1] == r2[j-1] {
+				cost = 0
+			}
+
+			matrix[i][j] = min(
+				matrix[i-1][j]+1,      // deletion
+				matrix[i][j-1]+1,      // insertion
+				matrix[i-1][j-1]+cost, // substitution
+			)
+		}
+	}
+
+	return matrix[len(r1)][len(r2)]
+}
+
+// Match scores how well query matches text, ignoring case: 0 when text
+// contains query, otherwise the edit distance between them if it's small
+// for query's length, or -1 when they don't match.
+func Match(query, text string) int {
+	query = strings.ToLower(query)
+	text = strings.ToLower(text)
+
+	if strings.Contains(text, query) {
+		return 0
+	}
+
+	distance := LevenshteinDistance(query, text)
+
+	threshold := utf8.RuneCountInString(query) / 2
+	if threshold < 2 {
+		threshold = 2
+	}
+
+	if distance <= threshold {
+		return distance
+	}
+
+	return -1
+}
@@ -0,0 +1,25 @@
+package fuzzy
+
+import "testing"
+
+func TestLevenshteinDistance(t *testing.T) {
+	tests := []struct {
+		s1, s2 string
+		want   int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"Go Blog", "go blog", 0},
+		{"日本語", "日本人", 1},
+		{"日本語", "日本", 1},
+		{"party 🎉", "party 🎈", 1},
+		{"café", "cafe", 1},
+	}
+
+	for _, tt := range tests {
+		if got := LevenshteinDistance(tt.s1, tt.s2); got != tt.want {
+			t.Errorf("LevenshteinDistance(%q, %q) = %d, want %d", tt.s1, tt.s2, got, tt.want)
+		}
+	}
+}
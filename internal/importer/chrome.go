@@ -0,0 +1,97 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/levineuwirth/gophermark/internal/models"
+)
+
+// webkitEpochOffset is the number of seconds between 1601-01-01, the epoch
+// Chrome counts from, and the Unix epoch.
+const webkitEpochOffset = 11644473600
+
+type chromeFile struct {
+	Roots map[string]chromeNode `json:"roots"`
+}
+
+type chromeNode struct {
+	Type      string       `json:"type"`
+	Name      string       `json:"name"`
+	URL       string       `json:"url"`
+	DateAdded string       `json:"date_added"`
+	Children  []chromeNode `json:"children"`
+}
+
+// chromeRoots lists the top-level folders in the order Chrome shows them.
+var chromeRoots = []string{"bookmark_bar", "other", "synced"}
+
+// ImportChromeJSON reads a Chrome/Chromium "Bookmarks" file and returns a
+// folder titled "Chrome" holding one subfolder per non-empty Chrome root.
+// IDs are left zero; they are assigned when the tree is written.
+func ImportChromeJSON(path string) (*models.Bookmark, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var file chromeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse Chrome bookmarks: %w", err)
+	}
+	if len(file.Roots) == 0 {
+		return nil, fmt.Errorf("no bookmark roots found in %s", path)
+	}
+
+	now := time.Now()
+	root := &models.Bookmark{
+		Type:         models.TypeFolder,
+		Title:        "Chrome",
+		DateAdded:    now,
+		LastModified: now,
+	}
+
+	for _, name := range chromeRoots {
+		node, ok := file.Roots[name]
+		if !ok || len(node.Children) == 0 {
+			continue
+		}
+		root.Children = append(root.Children, convertChromeNode(node))
+	}
+
+	return root, nil
+}
+
+func convertChromeNode(node chromeNode) *models.Bookmark {
+	dateAdded := webkitTime(node.DateAdded)
+	b := &models.Bookmark{
+		Title:        node.Name,
+		DateAdded:    dateAdded,
+		LastModified: dateAdded,
+	}
+
+	if node.Type == "folder" {
+		b.Type = models.TypeFolder
+		for _, child := range node.Children {
+			b.Children = append(b.Children, convertChromeNode(child))
+		}
+	} else {
+		b.Type = models.TypeBookmark
+		b.URL = node.URL
+	}
+
+	return b
+}
+
+// webkitTime converts Chrome's microseconds-since-1601 timestamps. Missing
+// or malformed values fall back to the current time.
+func webkitTime(value string) time.Time {
+	micros, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || micros <= 0 {
+		return time.Now()
+	}
+	return time.UnixMicro(micros - webkitEpochOffset*1_000_000)
+}
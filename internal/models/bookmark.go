@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type BookmarkType int
 
@@ -21,13 +24,31 @@ type Bookmark struct {
 	LastModified time.Time
 	GUID         string
 
-	URL        string
-	VisitCount int
+	URL         string
+	VisitCount  int
+	Frecency    int  // moz_places ranking used by the address bar
+	Hidden      bool // moz_places marks the page hidden, e.g. a redirect source
+	Typed       bool // the URL was once typed into the address bar
+	Keywords    []string
+	Tags        []string
+	Description string
 
 	Children []*Bookmark
 	Expanded bool
 }
 
+// UntitledLabel stands in for empty titles. Firefox stores NULL titles for
+// some bookmarks, which are loaded as "".
+const UntitledLabel = "(untitled)"
+
+// DisplayTitle returns the title to show for b, which is never empty.
+func (b *Bookmark) DisplayTitle() string {
+	if strings.TrimSpace(b.Title) == "" {
+		return UntitledLabel
+	}
+	return b.Title
+}
+
 func (b *Bookmark) IsFolder() bool {
 	return b.Type == TypeFolder
 }
@@ -35,3 +56,24 @@ func (b *Bookmark) IsFolder() bool {
 func (b *Bookmark) IsBookmark() bool {
 	return b.Type == TypeBookmark
 }
+
+func (b *Bookmark) IsSeparator() bool {
+	return b.Type == TypeSeparator
+}
+
+// builtinFolderGUIDs are Firefox's fixed containers: the places root and the
+// folders directly under it.
+var builtinFolderGUIDs = map[string]bool{
+	"root________": true,
+	"menu________": true,
+	"toolbar_____": true,
+	"unfiled_____": true,
+	"mobile______": true,
+	"tags________": true,
+}
+
+// IsBuiltinFolder reports whether b is one of Firefox's fixed containers,
+// which every profile has and nothing may rename, merge or delete.
+func (b *Bookmark) IsBuiltinFolder() bool {
+	return builtinFolderGUIDs[b.GUID]
+}
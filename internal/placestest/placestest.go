@@ -0,0 +1,91 @@
+// Package placestest builds small places.sqlite databases for tests, with
+// Firefox's schema and the folders every profile starts with.
+package placestest
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+// IDs of the built-in folders in a database made by New. Firefox's root has
+// parent 0 and the containers sit directly under it.
+const (
+	RootID    = 1
+	MenuID    = 2
+	ToolbarID = 3
+	TagsID    = 4
+	UnfiledID = 5
+	MobileID  = 6
+)
+
+// schema is the subset of Firefox's places schema GopherMark reads and
+// writes, with the column definitions Firefox uses.
+const schema = `
+CREATE TABLE moz_origins (
+	id INTEGER PRIMARY KEY, prefix TEXT NOT NULL, host TEXT NOT NULL,
+	frecency INTEGER NOT NULL, recalc_frecency INTEGER NOT NULL DEFAULT 0,
+	alt_frecency INTEGER, recalc_alt_frecency INTEGER NOT NULL DEFAULT 0,
+	UNIQUE (prefix, host));
+CREATE TABLE moz_places (
+	id INTEGER PRIMARY KEY, url LONGVARCHAR, title LONGVARCHAR, rev_host LONGVARCHAR,
+	visit_count INTEGER DEFAULT 0, hidden INTEGER DEFAULT 0 NOT NULL,
+	typed INTEGER DEFAULT 0 NOT NULL, frecency INTEGER DEFAULT -1 NOT NULL,
+	last_visit_date INTEGER, guid TEXT, foreign_count INTEGER DEFAULT 0 NOT NULL,
+	url_hash INTEGER DEFAULT 0 NOT NULL, description TEXT, preview_image_url TEXT,
+	site_name TEXT, origin_id INTEGER REFERENCES moz_origins(id),
+	recalc_frecency INTEGER NOT NULL DEFAULT 0, alt_frecency INTEGER,
+	recalc_alt_frecency INTEGER NOT NULL DEFAULT 0);
+CREATE TABLE moz_historyvisits (
+	id INTEGER PRIMARY KEY, from_visit INTEGER, place_id INTEGER, visit_date INTEGER,
+	visit_type INTEGER, session INTEGER, source INTEGER DEFAULT 0 NOT NULL,
+	triggeringPlaceId INTEGER);
+CREATE TABLE moz_bookmarks (
+	id INTEGER PRIMARY KEY, type INTEGER, fk INTEGER DEFAULT NULL, parent INTEGER,
+	position INTEGER, title LONGVARCHAR, keyword_id INTEGER, folder_type TEXT,
+	dateAdded INTEGER, lastModified INTEGER, guid TEXT,
+	syncStatus INTEGER NOT NULL DEFAULT 0, syncChangeCounter INTEGER NOT NULL DEFAULT 1);
+CREATE TABLE moz_keywords (
+	id INTEGER PRIMARY KEY AUTOINCREMENT, keyword TEXT UNIQUE, place_id INTEGER,
+	post_data TEXT);
+CREATE TABLE moz_anno_attributes (id INTEGER PRIMARY KEY, name VARCHAR(32) UNIQUE NOT NULL);
+CREATE TABLE moz_items_annos (
+	id INTEGER PRIMARY KEY, item_id INTEGER NOT NULL, anno_attribute_id INTEGER,
+	content LONGVARCHAR, flags INTEGER DEFAULT 0, expiration INTEGER DEFAULT 0,
+	type INTEGER DEFAULT 0, dateAdded INTEGER DEFAULT 0, lastModified INTEGER DEFAULT 0);
+CREATE UNIQUE INDEX moz_places_guid_uniqueindex ON moz_places (guid);
+CREATE INDEX moz_places_url_hashindex ON moz_places (url_hash);
+CREATE UNIQUE INDEX moz_bookmarks_guid_uniqueindex ON moz_bookmarks (guid);
+CREATE INDEX moz_bookmarks_itemindex ON moz_bookmarks (fk, type);
+CREATE INDEX moz_bookmarks_parentindex ON moz_bookmarks (parent, position);
+
+INSERT INTO moz_bookmarks (id, type, parent, position, title, dateAdded, lastModified, guid) VALUES
+	(1, 2, 0, 0, '', 1700000000000000, 1700000000000000, 'root________'),
+	(2, 2, 1, 0, 'menu', 1700000000000000, 1700000000000000, 'menu________'),
+	(3, 2, 1, 1, 'toolbar', 1700000000000000, 1700000000000000, 'toolbar_____'),
+	(4, 2, 1, 2, 'tags', 1700000000000000, 1700000000000000, 'tags________'),
+	(5, 2, 1, 3, 'unfiled', 1700000000000000, 1700000000000000, 'unfiled_____'),
+	(6, 2, 1, 4, 'mobile', 1700000000000000, 1700000000000000, 'mobile______');
+`
+
+// New creates places.sqlite in a temporary directory, runs stmts against it
+// after the schema and built-in folders are in place, and returns its path.
+func New(t testing.TB, stmts ...string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "places.sqlite")
+	conn, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("failed to create %s: %v", path, err)
+	}
+	defer conn.Close()
+
+	for _, stmt := range append([]string{schema}, stmts...) {
+		if _, err := conn.Exec(stmt); err != nil {
+			t.Fatalf("failed to set up places database: %v\n%s", err, stmt)
+		}
+	}
+	return path
+}
@@ -0,0 +1,150 @@
+// Package search finds bookmarks by title, description, tags and URL,
+// tolerating typos.
+package search
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/levineuwirth/gophermark/internal/fuzzy"
+	"github.com/levineuwirth/gophermark/internal/models"
+)
+
+type Result struct {
+	Bookmark   *models.Bookmark
+	FolderPath string
+
+	score int
+	field matchField
+}
+
+// matchField is the best field a search matched on, which breaks ties
+// between equal scores.
+type matchField int
+
+const (
+	matchTitle matchField = iota
+	matchNotes            // description or tags
+	matchURL
+)
+
+// splitTagFilters pulls "tag:name" terms out of query, returning the
+// lowercased tag names and the rest of the query.
+func splitTagFilters(query string) ([]string, string) {
+	var tags, rest []string
+	for _, term := range strings.Fields(query) {
+		if len(term) > len("tag:") && strings.EqualFold(term[:len("tag:")], "tag:") {
+			tags = append(tags, strings.ToLower(term[len("tag:"):]))
+			continue
+		}
+		rest = append(rest, term)
+	}
+	return tags, strings.Join(rest, " ")
+}
+
+// hasTags reports whether bookmark carries every tag in tags, ignoring case.
+func hasTags(bookmark *models.Bookmark, tags []string) bool {
+	for _, want := range tags {
+		found := false
+		for _, tag := range bookmark.Tags {
+			if strings.ToLower(tag) == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// matchBookmark scores query against bookmark's fields, returning -1 when
+// none match.
+func matchBookmark(query string, bookmark *models.Bookmark) (int, matchField) {
+	best, field := -1, matchTitle
+	try := func(score int, f matchField) {
+		if score >= 0 && (best < 0 || score < best) {
+			best, field = score, f
+		}
+	}
+
+	try(fuzzy.Match(query, bookmark.Title), matchTitle)
+	if bookmark.Description != "" {
+		try(fuzzy.Match(query, bookmark.Description), matchNotes)
+	}
+	for _, tag := range bookmark.Tags {
+		try(fuzzy.Match(query, tag), matchNotes)
+	}
+	try(fuzzy.Match(query, bookmark.URL), matchURL)
+	return best, field
+}
+
+// Bookmarks is BookmarksWithPaths without the paths.
+func Bookmarks(root *models.Bookmark, query string) []*models.Bookmark {
+	var bookmarks []*models.Bookmark
+	for _, result := range BookmarksWithPaths(root, query) {
+		bookmarks = append(bookmarks, result.Bookmark)
+	}
+	return bookmarks
+}
+
+// BookmarksWithPaths finds the bookmarks under root matching query and
+// annotates each match with the titles of its
+// ancestor folders, since models.Bookmark only records its parent's ID.
+// Titles, descriptions, tags and URLs are all searched. Results are ranked
+// best first: substring matches, then by edit distance, with title matches
+// ahead of description or tag matches, and those ahead of URL-only ones.
+// Ties keep tree order.
+//
+// A "tag:name" term keeps only bookmarks with that tag; on its own it lists
+// every one of them.
+func BookmarksWithPaths(root *models.Bookmark, query string) []Result {
+	tags, query := splitTagFilters(query)
+	if query == "" && len(tags) == 0 {
+		return nil
+	}
+
+	var results []Result
+
+	var search func(*models.Bookmark, []string)
+	search = func(node *models.Bookmark, path []string) {
+		// Entries under the tags root point at places filed elsewhere and
+		// carry the same tags, so they'd only repeat those matches.
+		if node.GUID == "tags________" {
+			return
+		}
+		if node.IsBookmark() && hasTags(node, tags) {
+			score, field := 0, matchTitle
+			if query != "" {
+				score, field = matchBookmark(query, node)
+			}
+			if score >= 0 {
+				results = append(results, Result{
+					Bookmark:   node,
+					FolderPath: strings.Join(path, " / "),
+					score:      score,
+					field:      field,
+				})
+			}
+		}
+
+		if node.IsFolder() && node.Title != "" {
+			path = append(path[:len(path):len(path)], node.Title)
+		}
+
+		for _, child := range node.Children {
+			search(child, path)
+		}
+	}
+
+	search(root, nil)
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].score != results[j].score {
+			return results[i].score < results[j].score
+		}
+		return results[i].field < results[j].field
+	})
+	return results
+}
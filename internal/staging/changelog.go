@@ -0,0 +1,105 @@
+package staging
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrChangeLog is returned by Commit when the changes were committed but
+// couldn't be appended to the change log.
+var ErrChangeLog = errors.New("failed to write change log")
+
+// Change is one operation made on the staging copy, kept so Commit can
+// record what it wrote to the real database. ID is the bookmark's, except
+// for operations on moz_places rows, where it's the place's.
+type Change struct {
+	Time      time.Time
+	Operation string
+	ID        int64
+	Before    string
+	After     string
+}
+
+// querier is satisfied by both *sql.DB and *sql.Tx.
+type querier interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// SetChangeLog makes Commit append the staged changes to path. An empty path
+// turns the log off.
+func (s *StagingDB) SetChangeLog(path string) {
+	s.changeLogPath = path
+}
+
+// Changes returns the operations staged so far, oldest first.
+func (s *StagingDB) Changes() []Change {
+	return s.changes
+}
+
+func (s *StagingDB) record(operation string, id int64, before, after string) {
+	s.changes = append(s.changes, Change{
+		Time:      time.Now(),
+		Operation: operation,
+		ID:        id,
+		Before:    before,
+		After:     after,
+	})
+}
+
+// describeItem summarizes a moz_bookmarks row for the change log: its title,
+// with the URL for bookmarks. A missing row describes as "".
+func describeItem(q querier, id int64) string {
+	var title, url sql.NullString
+	err := q.QueryRow(`
+		SELECT b.title, p.url FROM moz_bookmarks b
+		LEFT JOIN moz_places p ON p.id = b.fk
+		WHERE b.id = ?
+	`, id).Scan(&title, &url)
+	if err != nil {
+		return ""
+	}
+	if url.Valid {
+		return fmt.Sprintf("%s <%s>", title.String, url.String)
+	}
+	return title.String
+}
+
+// lookup returns a single column as text, or "" if the row is missing.
+func lookup(q querier, query string, args ...any) string {
+	var value sql.NullString
+	if err := q.QueryRow(query, args...).Scan(&value); err != nil {
+		return ""
+	}
+	return value.String
+}
+
+// appendChangeLog writes one header line for the commit and one line per
+// change to path, creating it if needed. The file is only ever appended to.
+func appendChangeLog(path, database string, changes []Change) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s committed %d changes to %s\n", time.Now().Format(time.RFC3339), len(changes), database)
+	for _, change := range changes {
+		fmt.Fprintf(&b, "%s\t%s\tid=%d\tbefore=%q\tafter=%q\n",
+			change.Time.Format(time.RFC3339), change.Operation, change.ID, change.Before, change.After)
+	}
+
+	if _, err := file.WriteString(b.String()); err != nil {
+		return err
+	}
+	return file.Sync()
+}
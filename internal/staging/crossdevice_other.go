@@ -0,0 +1,8 @@
+//go:build !unix && !windows
+
+package staging
+
+// isCrossDevice can't tell on this platform, so renames aren't retried.
+func isCrossDevice(err error) bool {
+	return false
+}
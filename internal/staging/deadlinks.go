@@ -0,0 +1,31 @@
+package staging
+
+// DeadLinksFolderTitle names the folder ArchiveDeadLink moves bookmarks
+// into. Like the trash it sits in the bookmarks menu and is found by its
+// guid, but it's an ordinary folder once committed: nothing in it is ever
+// deleted.
+const (
+	DeadLinksFolderTitle = "☠ Dead Links"
+	DeadLinksFolderGUID  = "gmdeadlinks_"
+)
+
+func (s *StagingDB) FindOrCreateDeadLinksFolder() (int64, error) {
+	return s.findOrCreateMenuFolder(DeadLinksFolderTitle, DeadLinksFolderGUID)
+}
+
+// ArchiveDeadLink moves a bookmark to the end of the dead links folder,
+// creating the folder if needed, and remembers where it came from for
+// UnarchiveDeadLink.
+func (s *StagingDB) ArchiveDeadLink(bookmarkID int64) error {
+	archiveID, err := s.FindOrCreateDeadLinksFolder()
+	if err != nil {
+		return err
+	}
+	return s.moveWithOrigin(bookmarkID, archiveID, s.archiveOrigins, "archive")
+}
+
+// UnarchiveDeadLink moves a bookmark archived this session back to the end
+// of the folder it came from and returns that folder's ID.
+func (s *StagingDB) UnarchiveDeadLink(bookmarkID int64) (int64, error) {
+	return s.moveToOrigin(bookmarkID, s.archiveOrigins, "archived", "unarchive")
+}
@@ -0,0 +1,51 @@
+package staging
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrNoSpace is returned by CreateStaging when the staging directory's
+// filesystem can't hold a copy of the database.
+var ErrNoSpace = errors.New("not enough free space for the staging copy")
+
+// checkFreeSpace makes sure dir has room for a copy of the database at
+// path, plus a tenth again for the WAL that edits write next to it. Small
+// tmpfs mounts otherwise fail partway through the copy, or fill up memory.
+// Platforms where free space can't be read skip the check.
+func checkFreeSpace(path, dir string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to read database: %w", err)
+	}
+
+	free, ok, err := freeSpace(dir)
+	if err != nil {
+		return fmt.Errorf("failed to check free space in %s: %w", dir, err)
+	}
+	if !ok {
+		return nil
+	}
+
+	need := uint64(info.Size()) + uint64(info.Size())/10
+	if free < need {
+		return fmt.Errorf("%w in %s: need %s, %s free", ErrNoSpace, dir, formatSize(need), formatSize(free))
+	}
+	return nil
+}
+
+func formatSize(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	value := float64(bytes) / unit
+	for _, suffix := range []string{"KB", "MB", "GB"} {
+		if value < unit {
+			return fmt.Sprintf("%.1f %s", value, suffix)
+		}
+		value /= unit
+	}
+	return fmt.Sprintf("%.1f TB", value)
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin && !freebsd && !windows
+
+package staging
+
+// freeSpace can't tell on this platform, so the check is skipped.
+func freeSpace(dir string) (uint64, bool, error) {
+	return 0, false, nil
+}
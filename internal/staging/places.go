@@ -0,0 +1,55 @@
+package staging
+
+import (
+	"database/sql"
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// findOrInsertPlace returns the moz_places row for url, adding one if there
+// isn't one yet. The title is stored as given, so a NULL title stays NULL.
+func findOrInsertPlace(tx *sql.Tx, url string, title any) (int64, error) {
+	var placeID int64
+	err := tx.QueryRow("SELECT id FROM moz_places WHERE url = ?", url).Scan(&placeID)
+	if err == nil {
+		return placeID, nil
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO moz_places (url, title, rev_host, hidden, typed, frecency, last_visit_date, guid, url_hash)
+		VALUES (?, ?, '', 0, 0, -1, NULL, lower(hex(randomblob(16))), ?)
+	`, url, title, urlHash(url))
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert place: %w", err)
+	}
+	placeID, err = result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get place ID: %w", err)
+	}
+	return placeID, nil
+}
+
+// urlHash computes moz_places.url_hash the way Firefox's hash() SQL function
+// does, which the staging copy doesn't have: the low 16 bits of the scheme's
+// hash over the hash of the whole URL. Firefox looks places up by url_hash,
+// so a row without it is invisible to the browser.
+func urlHash(url string) int64 {
+	// Firefox only looks for the scheme in the first 50 bytes.
+	head := url[:min(len(url), 50)]
+	i := strings.IndexByte(head, ':')
+	if i < 0 {
+		return int64(hashString(url))
+	}
+	return int64(hashString(head[:i])&0xFFFF)<<32 + int64(hashString(url))
+}
+
+// hashString is mozilla::HashString over the bytes of s.
+func hashString(s string) uint32 {
+	const goldenRatio = 0x9E3779B9
+	var hash uint32
+	for i := 0; i < len(s); i++ {
+		hash = goldenRatio * (bits.RotateLeft32(hash, 5) ^ uint32(s[i]))
+	}
+	return hash
+}
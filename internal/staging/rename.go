@@ -0,0 +1,61 @@
+package staging
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// replaceFile moves src over dst. A plain rename can't cross filesystems,
+// which is the usual case when the staging directory is on another volume
+// than the profile, so src is then copied next to dst first and renamed from
+// there. Either way dst is only ever replaced by a rename, never left
+// half-written.
+func replaceFile(src, dst string) error {
+	err := os.Rename(src, dst)
+	if err == nil || !isCrossDevice(err) {
+		return err
+	}
+
+	tmpPath := dst + ".committing"
+	if err := copyFile(src, tmpPath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to copy across volumes: %w", err)
+	}
+	if err := os.Rename(tmpPath, dst); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	os.Remove(src)
+	return nil
+}
+
+// removeSidecars deletes the write-ahead log and shared-memory index SQLite
+// keeps next to path. Once the database itself has been swapped they belong
+// to the old file, and Firefox would replay the stale log over the new one.
+func removeSidecars(path string) error {
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if err := os.Remove(path + suffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyWithLog copies a database and its write-ahead log, if it has one, so
+// the copy keeps changes that were never checkpointed. A log or index left
+// at dst by an earlier copy is removed, since it belongs to another file.
+func copyWithLog(src, dst string) error {
+	if err := copyFile(src, dst); err != nil {
+		return err
+	}
+	if err := removeSidecars(dst); err != nil {
+		return err
+	}
+	if _, err := os.Stat(src + "-wal"); err == nil {
+		if err := copyFile(src+"-wal", dst+"-wal"); err != nil {
+			return fmt.Errorf("failed to copy write-ahead log: %w", err)
+		}
+	}
+	return nil
+}
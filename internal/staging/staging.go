@@ -2,48 +2,99 @@ package staging
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/levineuwirth/gophermark/internal/db"
+	"github.com/levineuwirth/gophermark/internal/models"
 	_ "modernc.org/sqlite"
 )
 
+// ErrIntegrity is returned by CheckIntegrity, and by Commit and
+// RestoreBackup in its place, when SQLite reports problems with a database.
+var ErrIntegrity = errors.New("database failed integrity check")
+
+// maxIntegrityProblems caps how many problems CheckIntegrity lists; a badly
+// damaged file can report thousands.
+const maxIntegrityProblems = 10
+
 type StagingDB struct {
 	originalPath string
 	stagingPath  string
 	conn         *sql.DB
+
+	changes       []Change
+	changeLogPath string
+
+	// trashOrigins maps trashed bookmarks to the folder they came from.
+	trashOrigins map[int64]int64
+	// archiveOrigins does the same for bookmarks moved to the dead links
+	// folder.
+	archiveOrigins map[int64]int64
+}
+
+// Options tunes CreateStagingWithOptions.
+type Options struct {
+	StagingDir string // where the staging copy goes; defaults to os.TempDir()
 }
 
 func CreateStaging(originalPath string) (*StagingDB, error) {
-	tempDir := os.TempDir()
+	return CreateStagingWithOptions(originalPath, Options{})
+}
+
+func CreateStagingWithOptions(originalPath string, opts Options) (*StagingDB, error) {
+	tempDir := opts.StagingDir
+	if tempDir == "" {
+		tempDir = os.TempDir()
+	} else if err := os.MkdirAll(tempDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
 	stagingPath := filepath.Join(tempDir, fmt.Sprintf("gophermark-staging-%d.sqlite", os.Getpid()))
 
+	if err := checkFreeSpace(originalPath, tempDir); err != nil {
+		return nil, err
+	}
+
 	if err := copyFile(originalPath, stagingPath); err != nil {
+		os.Remove(stagingPath)
 		return nil, fmt.Errorf("failed to create staging copy: %w", err)
 	}
+	// Pages the browser hadn't checkpointed yet live only in its write-ahead
+	// log, so it's copied too and folded in when the staging copy is opened.
+	if _, err := os.Stat(originalPath + "-wal"); err == nil {
+		if err := copyFile(originalPath+"-wal", stagingPath+"-wal"); err != nil {
+			os.Remove(stagingPath)
+			removeSidecars(stagingPath)
+			return nil, fmt.Errorf("failed to copy write-ahead log: %w", err)
+		}
+	}
 
 	conn, err := sql.Open("sqlite", stagingPath)
 	if err != nil {
 		os.Remove(stagingPath)
+		removeSidecars(stagingPath)
 		return nil, fmt.Errorf("failed to open staging database: %w", err)
 	}
 
 	if _, err := conn.Exec("PRAGMA journal_mode=WAL"); err != nil {
 		conn.Close()
 		os.Remove(stagingPath)
-		return nil, fmt.Errorf("failed to set WAL mode: %w", err)
+		removeSidecars(stagingPath)
+		return nil, fmt.Errorf("failed to set WAL mode: %w", db.Classify(err))
 	}
 
 	return &StagingDB{
-		originalPath: originalPath,
-		stagingPath:  stagingPath,
-		conn:         conn,
+		originalPath:   originalPath,
+		stagingPath:    stagingPath,
+		conn:           conn,
+		trashOrigins:   make(map[int64]int64),
+		archiveOrigins: make(map[int64]int64),
 	}, nil
 }
 
@@ -52,25 +103,166 @@ func (s *StagingDB) Conn() *sql.DB {
 }
 
 func (s *StagingDB) Commit() error {
-	if running, process := isBrowserRunning(); running {
-		return fmt.Errorf("cannot commit: %s is still running (close it first)", process)
+	if running, process := db.IsBrowserRunning(); running {
+		return fmt.Errorf("cannot commit: %w (close %s first)", db.ErrBrowserRunning, process)
+	}
+
+	if err := s.CheckIntegrity(); err != nil {
+		return err
 	}
 
+	// Everything staged is still in the staging copy's write-ahead log, and
+	// only the main file is swapped in.
+	if _, err := s.conn.Exec("PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		return fmt.Errorf("failed to checkpoint staging database: %w", db.Classify(err))
+	}
 	if err := s.conn.Close(); err != nil {
 		return fmt.Errorf("failed to close staging connection: %w", err)
 	}
+	removeSidecars(s.stagingPath)
 
-	backupPath := s.originalPath + ".backup"
-	if err := copyFile(s.originalPath, backupPath); err != nil {
+	// The original's write-ahead log is removed after the swap, so the
+	// backup keeps a copy for RestoreBackup to put back.
+	backupPath := s.BackupPath()
+	if err := copyWithLog(s.originalPath, backupPath); err != nil {
 		return fmt.Errorf("failed to create backup: %w", err)
 	}
 
-	if err := os.Rename(s.stagingPath, s.originalPath); err != nil {
+	if err := replaceFile(s.stagingPath, s.originalPath); err != nil {
 		os.Rename(backupPath, s.originalPath)
 		return fmt.Errorf("failed to swap databases: %w", err)
 	}
+	if err := removeSidecars(s.originalPath); err != nil {
+		return fmt.Errorf("changes committed, but failed to remove the old write-ahead log: %w", err)
+	}
+	// The backup of the pre-commit database is kept until the next commit
+	// replaces it.
 	// TODO: configure so we can allow user to save the backup elsewhere
-	os.Remove(backupPath)
+
+	if s.changeLogPath != "" && len(s.changes) > 0 {
+		if err := appendChangeLog(s.changeLogPath, s.originalPath, s.changes); err != nil {
+			return fmt.Errorf("changes committed, but %w: %v", ErrChangeLog, err)
+		}
+	}
+
+	return nil
+}
+
+// CheckIntegrity runs SQLite's integrity and foreign key checks on the
+// staging copy. Any problems found are listed in the returned error, which
+// wraps ErrIntegrity.
+func (s *StagingDB) CheckIntegrity() error {
+	return checkIntegrity(s.conn)
+}
+
+func checkIntegrity(conn *sql.DB) error {
+	var problems []string
+
+	rows, err := conn.Query("PRAGMA integrity_check")
+	if err != nil {
+		return fmt.Errorf("failed to run integrity check: %w", db.Classify(err))
+	}
+	for rows.Next() {
+		var result string
+		if err := rows.Scan(&result); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to read integrity check: %w", db.Classify(err))
+		}
+		if result != "ok" {
+			problems = append(problems, result)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read integrity check: %w", db.Classify(err))
+	}
+
+	rows, err = conn.Query("PRAGMA foreign_key_check")
+	if err != nil {
+		return fmt.Errorf("failed to run foreign key check: %w", db.Classify(err))
+	}
+	for rows.Next() {
+		var table, parent string
+		var rowID sql.NullInt64
+		var fkID int64
+		if err := rows.Scan(&table, &rowID, &parent, &fkID); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to read foreign key check: %w", db.Classify(err))
+		}
+		problems = append(problems, fmt.Sprintf("%s row %d references a missing %s row", table, rowID.Int64, parent))
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read foreign key check: %w", db.Classify(err))
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	if len(problems) > maxIntegrityProblems {
+		more := len(problems) - maxIntegrityProblems
+		problems = append(problems[:maxIntegrityProblems], fmt.Sprintf("and %d more", more))
+	}
+	return fmt.Errorf("%w: %s", ErrIntegrity, strings.Join(problems, "; "))
+}
+
+// BackupPath is where Commit copies the real database before replacing it.
+func (s *StagingDB) BackupPath() string {
+	return s.originalPath + ".backup"
+}
+
+// PreRestorePath is where RestoreBackup keeps the database it replaces.
+func PreRestorePath(originalPath string) string {
+	return originalPath + ".before-restore"
+}
+
+// RestoreBackup copies backupPath back over originalPath, undoing a commit.
+// The backup is integrity-checked first, and the database being replaced is
+// kept at PreRestorePath, write-ahead log and all, in case the wrong backup
+// was picked.
+func RestoreBackup(originalPath, backupPath string) error {
+	if running, process := db.IsBrowserRunning(); running {
+		return fmt.Errorf("cannot restore: %w (close %s first)", db.ErrBrowserRunning, process)
+	}
+
+	// A read-only open would ignore the backup's write-ahead log, so the
+	// check runs on a snapshot copy that has the log folded in, which is
+	// the database that ends up restored.
+	backup, err := db.OpenSnapshot(backupPath)
+	if err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+	err = checkIntegrity(backup.Conn())
+	backup.Close()
+	if err != nil {
+		return fmt.Errorf("refusing to restore %s: %w", backupPath, err)
+	}
+
+	if _, err := os.Stat(originalPath); err == nil {
+		if err := copyWithLog(originalPath, PreRestorePath(originalPath)); err != nil {
+			return fmt.Errorf("failed to save current database: %w", err)
+		}
+	}
+
+	// Copy next to the database first so the swap itself is a rename and
+	// the backup stays available.
+	restoringPath := originalPath + ".restoring"
+	if err := copyFile(backupPath, restoringPath); err != nil {
+		os.Remove(restoringPath)
+		return fmt.Errorf("failed to copy backup: %w", err)
+	}
+	if err := os.Rename(restoringPath, originalPath); err != nil {
+		os.Remove(restoringPath)
+		return fmt.Errorf("failed to swap databases: %w", err)
+	}
+	if err := removeSidecars(originalPath); err != nil {
+		return fmt.Errorf("backup restored, but failed to remove the old write-ahead log: %w", err)
+	}
+	if _, err := os.Stat(backupPath + "-wal"); err == nil {
+		if err := copyFile(backupPath+"-wal", originalPath+"-wal"); err != nil {
+			return fmt.Errorf("backup restored, but failed to restore its write-ahead log: %w", err)
+		}
+	}
 
 	return nil
 }
@@ -79,6 +271,7 @@ func (s *StagingDB) Rollback() error {
 	if s.conn != nil {
 		s.conn.Close()
 	}
+	removeSidecars(s.stagingPath)
 	return os.Remove(s.stagingPath)
 }
 
@@ -88,6 +281,7 @@ func (s *StagingDB) Close() error {
 			return err
 		}
 	}
+	removeSidecars(s.stagingPath)
 	if _, err := os.Stat(s.stagingPath); err == nil {
 		return os.Remove(s.stagingPath)
 	}
@@ -115,69 +309,178 @@ func copyFile(src, dst string) error {
 	return destFile.Sync()
 }
 
-func isBrowserRunning() (bool, string) {
-	processes := []string{"firefox", "librewolf", "firefox-bin", "librewolf-bin"}
-
-	var cmd *exec.Cmd
-	switch runtime.GOOS {
-	case "linux", "darwin":
-		cmd = exec.Command("pgrep", "-i", strings.Join(processes, "|"))
-	case "windows":
-		cmd = exec.Command("tasklist")
-	default:
-		return false, ""
+func (s *StagingDB) UpdateBookmarkTitle(bookmarkID int64, newTitle string) error {
+	before := lookup(s.conn, "SELECT title FROM moz_bookmarks WHERE id = ?", bookmarkID)
+	_, err := s.conn.Exec("UPDATE moz_bookmarks SET title = ?, lastModified = ? WHERE id = ?",
+		newTitle, currentMicroseconds(), bookmarkID)
+	if err == nil {
+		s.record("update-title", bookmarkID, before, newTitle)
 	}
+	return err
+}
 
-	output, err := cmd.Output()
+// RepointBookmarkURL gives one bookmark a new URL without touching other
+// bookmarks that share its moz_places row: the bookmark's fk is moved to the
+// place for newURL, which is created if it doesn't exist yet.
+func (s *StagingDB) RepointBookmarkURL(bookmarkID int64, newURL string) error {
+	tx, err := s.conn.Begin()
 	if err != nil {
-		return false, ""
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	if runtime.GOOS == "linux" || runtime.GOOS == "darwin" {
-		return len(output) > 0, detectWhichBrowser(string(output))
+	var title sql.NullString
+	if err := tx.QueryRow("SELECT title FROM moz_bookmarks WHERE id = ?", bookmarkID).Scan(&title); err != nil {
+		return fmt.Errorf("failed to find bookmark: %w", err)
 	}
+	before := lookup(tx, "SELECT p.url FROM moz_bookmarks b JOIN moz_places p ON p.id = b.fk WHERE b.id = ?", bookmarkID)
 
-	outputStr := strings.ToLower(string(output))
-	for _, proc := range processes {
-		if strings.Contains(outputStr, proc) {
-			return true, proc
-		}
+	placeID, err := findOrInsertPlace(tx, newURL, title)
+	if err != nil {
+		return err
 	}
 
-	return false, ""
-}
-
-func detectWhichBrowser(output string) string {
-	output = strings.ToLower(output)
-	if strings.Contains(output, "librewolf") {
-		return "LibreWolf"
+	_, err = tx.Exec("UPDATE moz_bookmarks SET fk = ?, lastModified = ? WHERE id = ?",
+		placeID, currentMicroseconds(), bookmarkID)
+	if err != nil {
+		return fmt.Errorf("failed to repoint bookmark: %w", err)
 	}
-	if strings.Contains(output, "firefox") {
-		return "Firefox"
+
+	if err := tx.Commit(); err != nil {
+		return err
 	}
-	return "Browser"
+	s.record("update-url", bookmarkID, before, newURL)
+	return nil
 }
 
-func (s *StagingDB) UpdateBookmarkTitle(bookmarkID int64, newTitle string) error {
-	_, err := s.conn.Exec("UPDATE moz_bookmarks SET title = ?, lastModified = ? WHERE id = ?",
-		newTitle, currentMicroseconds(), bookmarkID)
+// BookmarkPlaceID returns the moz_places row a bookmark currently points at.
+func (s *StagingDB) BookmarkPlaceID(bookmarkID int64) (int64, error) {
+	var placeID int64
+	err := s.conn.QueryRow("SELECT fk FROM moz_bookmarks WHERE id = ?", bookmarkID).Scan(&placeID)
+	return placeID, err
+}
+
+func (s *StagingDB) UpdateBookmarkVisitCount(placeID int64, count int) error {
+	before := lookup(s.conn, "SELECT visit_count FROM moz_places WHERE id = ?", placeID)
+	_, err := s.conn.Exec("UPDATE moz_places SET visit_count = ? WHERE id = ?", count, placeID)
+	if err == nil {
+		s.record("update-visit-count", placeID, before, fmt.Sprint(count))
+	}
 	return err
 }
 
-func (s *StagingDB) UpdateBookmarkURL(placeID int64, newURL string) error {
-	_, err := s.conn.Exec("UPDATE moz_places SET url = ?, last_visit_date = ? WHERE id = ?",
-		newURL, currentMicroseconds(), placeID)
+func (s *StagingDB) UpdateBookmarkDateAdded(bookmarkID int64, dateAdded time.Time) error {
+	var before string
+	if micros, err := strconv.ParseInt(lookup(s.conn, "SELECT dateAdded FROM moz_bookmarks WHERE id = ?", bookmarkID), 10, 64); err == nil {
+		before = time.UnixMicro(micros).Format(time.RFC3339)
+	}
+	_, err := s.conn.Exec("UPDATE moz_bookmarks SET dateAdded = ?, lastModified = ? WHERE id = ?",
+		dateAdded.UnixNano()/1000, currentMicroseconds(), bookmarkID)
+	if err == nil {
+		s.record("update-date-added", bookmarkID, before, dateAdded.Format(time.RFC3339))
+	}
 	return err
 }
 
 func (s *StagingDB) DeleteBookmark(bookmarkID int64) error {
+	before := describeItem(s.conn, bookmarkID)
 	_, err := s.conn.Exec("DELETE FROM moz_bookmarks WHERE id = ?", bookmarkID)
+	if err == nil {
+		s.record("delete", bookmarkID, before, "")
+	}
 	return err
 }
 
+// DeleteFolder removes an empty folder. It refuses folders that still have
+// children, so nested empty folders must be deleted bottom-up.
+func (s *StagingDB) DeleteFolder(folderID int64) error {
+	before := describeItem(s.conn, folderID)
+	result, err := s.conn.Exec(`DELETE FROM moz_bookmarks
+		WHERE id = ? AND type = 2 AND NOT EXISTS (SELECT 1 FROM moz_bookmarks WHERE parent = ?)`,
+		folderID, folderID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("folder %d is not an empty folder", folderID)
+	}
+	s.record("delete-folder", folderID, before, "")
+	return nil
+}
+
+// DeleteFolderRecursive deletes a folder along with every bookmark,
+// separator and folder beneath it, deepest entries first, in one transaction.
+func (s *StagingDB) DeleteFolderRecursive(folderID int64) error {
+	tx, err := s.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var itemType int
+	if err := tx.QueryRow("SELECT type FROM moz_bookmarks WHERE id = ?", folderID).Scan(&itemType); err != nil {
+		return fmt.Errorf("failed to find folder: %w", err)
+	}
+	if itemType != 2 {
+		return fmt.Errorf("item %d is not a folder", folderID)
+	}
+
+	rows, err := tx.Query(`
+		WITH RECURSIVE subtree(id, depth) AS (
+			SELECT id, 0 FROM moz_bookmarks WHERE id = ?
+			UNION ALL
+			SELECT b.id, subtree.depth + 1 FROM moz_bookmarks b
+			JOIN subtree ON b.parent = subtree.id
+		)
+		SELECT id FROM subtree ORDER BY depth DESC
+	`, folderID)
+	if err != nil {
+		return fmt.Errorf("failed to collect folder contents: %w", err)
+	}
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan item: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to collect folder contents: %w", err)
+	}
+
+	descriptions := make([]string, len(ids))
+	for i, id := range ids {
+		descriptions[i] = describeItem(tx, id)
+		if _, err := tx.Exec("DELETE FROM moz_bookmarks WHERE id = ?", id); err != nil {
+			return fmt.Errorf("failed to delete item %d: %w", id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	for i, id := range ids {
+		s.record("delete", id, descriptions[i], "")
+	}
+	return nil
+}
+
 func (s *StagingDB) MoveBookmark(bookmarkID, newParentID int64, newPosition int) error {
+	before := lookup(s.conn, "SELECT 'parent=' || parent || ' position=' || position FROM moz_bookmarks WHERE id = ?", bookmarkID)
 	_, err := s.conn.Exec("UPDATE moz_bookmarks SET parent = ?, position = ?, lastModified = ? WHERE id = ?",
 		newParentID, newPosition, currentMicroseconds(), bookmarkID)
+	if err == nil {
+		s.record("move", bookmarkID, before, fmt.Sprintf("parent=%d position=%d", newParentID, newPosition))
+	}
 	return err
 }
 
@@ -188,20 +491,9 @@ func (s *StagingDB) AddBookmark(parentID int64, title, url string) error {
 	}
 	defer tx.Rollback()
 
-	var placeID int64
-	err = tx.QueryRow("SELECT id FROM moz_places WHERE url = ?", url).Scan(&placeID)
+	placeID, err := findOrInsertPlace(tx, url, title)
 	if err != nil {
-		result, err := tx.Exec(`
-			INSERT INTO moz_places (url, title, rev_host, hidden, typed, frecency, last_visit_date, guid)
-			VALUES (?, ?, '', 0, 0, -1, ?, lower(hex(randomblob(16))))
-		`, url, title, currentMicroseconds())
-		if err != nil {
-			return fmt.Errorf("failed to insert place: %w", err)
-		}
-		placeID, err = result.LastInsertId()
-		if err != nil {
-			return fmt.Errorf("failed to get place ID: %w", err)
-		}
+		return err
 	}
 
 	var maxPosition int
@@ -210,15 +502,216 @@ func (s *StagingDB) AddBookmark(parentID int64, title, url string) error {
 		return fmt.Errorf("failed to get max position: %w", err)
 	}
 
-	_, err = tx.Exec(`
+	result, err := tx.Exec(`
 		INSERT INTO moz_bookmarks (type, fk, parent, position, title, dateAdded, lastModified, guid)
 		VALUES (1, ?, ?, ?, ?, ?, ?, lower(hex(randomblob(16))))
 	`, placeID, parentID, maxPosition+1, title, currentMicroseconds(), currentMicroseconds())
 	if err != nil {
 		return fmt.Errorf("failed to insert bookmark: %w", err)
 	}
+	bookmarkID, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get bookmark ID: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	s.record("add", bookmarkID, "", fmt.Sprintf("%s <%s> parent=%d", title, url, parentID))
+	return nil
+}
+
+// CloneBookmark files bookmarkID a second time, as the last item in
+// parentID. The copy shares the original's moz_places row, the way Firefox
+// itself stores a link filed in several folders, so the history, tags and
+// keyword stay shared. It returns the new bookmark's ID.
+func (s *StagingDB) CloneBookmark(bookmarkID, parentID int64) (int64, error) {
+	tx, err := s.conn.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var maxPosition int
+	err = tx.QueryRow("SELECT COALESCE(MAX(position), -1) FROM moz_bookmarks WHERE parent = ?", parentID).Scan(&maxPosition)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get max position: %w", err)
+	}
+
+	now := currentMicroseconds()
+	result, err := tx.Exec(`
+		INSERT INTO moz_bookmarks (type, fk, parent, position, title, dateAdded, lastModified, guid)
+		SELECT type, fk, ?, ?, title, ?, ?, lower(hex(randomblob(16)))
+		FROM moz_bookmarks WHERE id = ? AND type = 1
+	`, parentID, maxPosition+1, now, now, bookmarkID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert bookmark: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return 0, fmt.Errorf("bookmark %d not found", bookmarkID)
+	}
+	cloneID, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get bookmark ID: %w", err)
+	}
+
+	after := describeItem(tx, cloneID)
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	s.record("clone", cloneID, "", fmt.Sprintf("%s parent=%d (copy of %d)", after, parentID, bookmarkID))
+	return cloneID, nil
+}
+
+// ImportTree writes root and everything below it as a new last child of
+// parentID in one transaction. Bookmarks reuse an existing moz_places row
+// for their URL when there is one. The assigned ID, Parent and Position are
+// written back onto each node so the tree can be attached in memory.
+func (s *StagingDB) ImportTree(parentID int64, root *models.Bookmark) error {
+	tx, err := s.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var maxPosition int
+	err = tx.QueryRow("SELECT COALESCE(MAX(position), -1) FROM moz_bookmarks WHERE parent = ?", parentID).Scan(&maxPosition)
+	if err != nil {
+		return fmt.Errorf("failed to get max position: %w", err)
+	}
+
+	if err := importNode(tx, parentID, maxPosition+1, root); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	s.recordImport(root)
+	return nil
+}
+
+func (s *StagingDB) recordImport(node *models.Bookmark) {
+	after := fmt.Sprintf("%s parent=%d", node.Title, node.Parent)
+	if node.IsBookmark() {
+		after = fmt.Sprintf("%s <%s> parent=%d", node.Title, node.URL, node.Parent)
+	}
+	s.record("import", node.ID, "", after)
+	for _, child := range node.Children {
+		s.recordImport(child)
+	}
+}
+
+func importNode(tx *sql.Tx, parentID int64, position int, node *models.Bookmark) error {
+	var fk sql.NullInt64
+	if node.IsBookmark() {
+		placeID, err := findOrInsertPlace(tx, node.URL, node.Title)
+		if err != nil {
+			return err
+		}
+		fk = sql.NullInt64{Int64: placeID, Valid: true}
+		node.FK = &placeID
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO moz_bookmarks (type, fk, parent, position, title, dateAdded, lastModified, guid)
+		VALUES (?, ?, ?, ?, ?, ?, ?, lower(hex(randomblob(16))))
+	`, node.Type, fk, parentID, position, node.Title, node.DateAdded.UnixMicro(), node.LastModified.UnixMicro())
+	if err != nil {
+		return fmt.Errorf("failed to insert %q: %w", node.Title, err)
+	}
+
+	node.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get bookmark ID: %w", err)
+	}
+	node.Parent = parentID
+	node.Position = position
+
+	for i, child := range node.Children {
+		if err := importNode(tx, node.ID, i, child); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *StagingDB) AddSeparator(parentID int64, position int) error {
+	tx, err := s.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("UPDATE moz_bookmarks SET position = position + 1 WHERE parent = ? AND position >= ?", parentID, position)
+	if err != nil {
+		return fmt.Errorf("failed to shift positions: %w", err)
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO moz_bookmarks (type, fk, parent, position, title, dateAdded, lastModified, guid)
+		VALUES (3, NULL, ?, ?, NULL, ?, ?, lower(hex(randomblob(16))))
+	`, parentID, position, currentMicroseconds(), currentMicroseconds())
+	if err != nil {
+		return fmt.Errorf("failed to insert separator: %w", err)
+	}
+	separatorID, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get separator ID: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	s.record("add-separator", separatorID, "", fmt.Sprintf("parent=%d position=%d", parentID, position))
+	return nil
+}
+
+// SwapPositions exchanges two children of a folder. All children are
+// renumbered 0..n-1 in their current order, so first and second are child
+// indexes and the folder never ends up with gaps or duplicate positions.
+func (s *StagingDB) SwapPositions(parentID int64, first, second int) error {
+	tx, err := s.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query("SELECT id FROM moz_bookmarks WHERE parent = ? ORDER BY position, id", parentID)
+	if err != nil {
+		return fmt.Errorf("failed to query children: %w", err)
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan child: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	if first < 0 || second < 0 || first >= len(ids) || second >= len(ids) {
+		return fmt.Errorf("position out of range")
+	}
+	before := fmt.Sprint(ids)
+	ids[first], ids[second] = ids[second], ids[first]
+
+	for position, id := range ids {
+		_, err := tx.Exec("UPDATE moz_bookmarks SET position = ?, lastModified = ? WHERE id = ?",
+			position, currentMicroseconds(), id)
+		if err != nil {
+			return fmt.Errorf("failed to update position: %w", err)
+		}
+	}
 
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	s.record("reorder", parentID, before, fmt.Sprint(ids))
+	return nil
 }
 
 func currentMicroseconds() int64 {
@@ -226,14 +719,27 @@ func currentMicroseconds() int64 {
 }
 
 func (s *StagingDB) FindOrCreateScratchFolder() (int64, error) {
+	return s.findOrCreateMenuFolder("Scratch", "")
+}
+
+// findOrCreateMenuFolder returns the folder with guid, or titled title when
+// guid is empty, adding it to the end of the bookmarks menu if needed.
+// Folders GopherMark owns get a fixed guid so a user's folder that happens
+// to share the title is never mistaken for them.
+func (s *StagingDB) findOrCreateMenuFolder(title, guid string) (int64, error) {
+	query, arg := "SELECT id FROM moz_bookmarks WHERE type = 2 AND title = ?", title
+	if guid != "" {
+		query, arg = "SELECT id FROM moz_bookmarks WHERE type = 2 AND guid = ?", guid
+	}
+
 	var folderID int64
-	err := s.conn.QueryRow("SELECT id FROM moz_bookmarks WHERE type = 2 AND title = 'Scratch'").Scan(&folderID)
+	err := s.conn.QueryRow(query, arg).Scan(&folderID)
 	if err == nil {
 		return folderID, nil
 	}
 
 	if err != sql.ErrNoRows {
-		return 0, fmt.Errorf("failed to query scratch folder: %w", err)
+		return 0, fmt.Errorf("failed to query %s folder: %w", title, err)
 	}
 
 	var menuID int64
@@ -250,16 +756,17 @@ func (s *StagingDB) FindOrCreateScratchFolder() (int64, error) {
 
 	result, err := s.conn.Exec(`
 		INSERT INTO moz_bookmarks (type, fk, parent, position, title, dateAdded, lastModified, guid)
-		VALUES (2, NULL, ?, ?, 'Scratch', ?, ?, lower(hex(randomblob(16))))
-	`, menuID, maxPosition+1, currentMicroseconds(), currentMicroseconds())
+		VALUES (2, NULL, ?, ?, ?, ?, ?, COALESCE(?, lower(hex(randomblob(16)))))
+	`, menuID, maxPosition+1, title, currentMicroseconds(), currentMicroseconds(), sql.NullString{String: guid, Valid: guid != ""})
 	if err != nil {
-		return 0, fmt.Errorf("failed to create scratch folder: %w", err)
+		return 0, fmt.Errorf("failed to create %s folder: %w", title, err)
 	}
 
 	folderID, err = result.LastInsertId()
 	if err != nil {
 		return 0, fmt.Errorf("failed to get folder ID: %w", err)
 	}
+	s.record("add-folder", folderID, "", fmt.Sprintf("%s parent=%d", title, menuID))
 
 	return folderID, nil
 }
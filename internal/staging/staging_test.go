@@ -0,0 +1,309 @@
+package staging
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/levineuwirth/gophermark/internal/db"
+	"github.com/levineuwirth/gophermark/internal/models"
+	"github.com/levineuwirth/gophermark/internal/placestest"
+)
+
+func newTestStaging(t *testing.T, stmts ...string) *StagingDB {
+	t.Helper()
+
+	s, err := CreateStagingWithOptions(placestest.New(t, stmts...), Options{StagingDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func bookmarkURL(t *testing.T, s *StagingDB, bookmarkID int64) string {
+	t.Helper()
+
+	var url string
+	err := s.Conn().QueryRow("SELECT p.url FROM moz_bookmarks b JOIN moz_places p ON p.id = b.fk WHERE b.id = ?", bookmarkID).Scan(&url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return url
+}
+
+func TestRepointBookmarkURLSharedPlace(t *testing.T) {
+	s := newTestStaging(t,
+		`INSERT INTO moz_places (id, url, title, guid) VALUES
+			(1, 'https://old.example/', 'Old', 'place0000001'),
+			(2, 'https://existing.example/', 'Existing', 'place0000002')`,
+		`INSERT INTO moz_bookmarks (id, type, fk, parent, position, title, dateAdded, lastModified, guid) VALUES
+			(10, 1, 1, 3, 0, 'First', 0, 0, 'bookmark0010'),
+			(11, 1, 1, 2, 0, 'Second', 0, 0, 'bookmark0011')`,
+	)
+
+	if err := s.RepointBookmarkURL(10, "https://new.example/"); err != nil {
+		t.Fatal(err)
+	}
+	if got := bookmarkURL(t, s, 10); got != "https://new.example/" {
+		t.Errorf("edited bookmark URL = %q, want https://new.example/", got)
+	}
+	if got := bookmarkURL(t, s, 11); got != "https://old.example/" {
+		t.Errorf("other bookmark URL = %q, want it unchanged", got)
+	}
+
+	// A URL that already has a place reuses it rather than adding a row.
+	if err := s.RepointBookmarkURL(11, "https://existing.example/"); err != nil {
+		t.Fatal(err)
+	}
+	placeID, err := s.BookmarkPlaceID(11)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if placeID != 2 {
+		t.Errorf("bookmark 11 points at place %d, want the existing place 2", placeID)
+	}
+
+	var places int
+	if err := s.Conn().QueryRow("SELECT COUNT(*) FROM moz_places").Scan(&places); err != nil {
+		t.Fatal(err)
+	}
+	if places != 3 {
+		t.Errorf("moz_places has %d rows, want 3", places)
+	}
+}
+
+func TestURLHash(t *testing.T) {
+	tests := []struct {
+		url  string
+		want int64
+	}{
+		{"http://www.mozilla.org/", 125511243481084},
+		{"https://example.com/", 47357371248711},
+		{"place:type=6", 268507306401933},
+		{"日本語", 775154993}, // no scheme, so just the 32-bit hash
+	}
+	for _, tt := range tests {
+		if got := urlHash(tt.url); got != tt.want {
+			t.Errorf("urlHash(%q) = %d, want %d", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestNewPlacesHaveURLHash(t *testing.T) {
+	s := newTestStaging(t,
+		`INSERT INTO moz_places (id, url, title, guid) VALUES (1, 'https://old.example/', 'Old', 'place0000001')`,
+		`INSERT INTO moz_bookmarks (id, type, fk, parent, position, title, dateAdded, lastModified, guid) VALUES
+			(10, 1, 1, 3, 0, 'Old', 0, 0, 'bookmark0010')`,
+	)
+
+	if err := s.RepointBookmarkURL(10, "https://repointed.example/"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddBookmark(3, "Added", "https://added.example/"); err != nil {
+		t.Fatal(err)
+	}
+	imported := &models.Bookmark{Type: models.TypeFolder, Title: "Imported", Children: []*models.Bookmark{
+		{Type: models.TypeBookmark, Title: "Imported", URL: "https://imported.example/"},
+	}}
+	if err := s.ImportTree(3, imported); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, url := range []string{"https://repointed.example/", "https://added.example/", "https://imported.example/"} {
+		var hash int64
+		if err := s.Conn().QueryRow("SELECT url_hash FROM moz_places WHERE url = ?", url).Scan(&hash); err != nil {
+			t.Fatal(err)
+		}
+		if hash != urlHash(url) {
+			t.Errorf("%s: url_hash = %d, want %d", url, hash, urlHash(url))
+		}
+	}
+}
+
+// leaveWAL runs stmt on the database at path in WAL mode and leaves the
+// change in an uncheckpointed -wal, as a browser that's still running or
+// was killed does.
+func leaveWAL(t *testing.T, path, stmt string) {
+	t.Helper()
+
+	conn, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"PRAGMA journal_mode=WAL", "PRAGMA wal_autocheckpoint=0", stmt} {
+		if _, err := conn.Exec(s); err != nil {
+			conn.Close()
+			t.Fatal(err)
+		}
+	}
+
+	// Closing the last connection checkpoints the log and deletes it, so
+	// keep copies taken while it's open.
+	saved := filepath.Join(t.TempDir(), "saved.sqlite")
+	for _, suffix := range []string{"", "-wal"} {
+		if err := copyFile(path+suffix, saved+suffix); err != nil {
+			conn.Close()
+			t.Fatal(err)
+		}
+	}
+	conn.Close()
+	for _, suffix := range []string{"", "-wal"} {
+		if err := copyFile(saved+suffix, path+suffix); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCommitRemovesSidecars(t *testing.T) {
+	path := placestest.New(t)
+	leaveWAL(t, path, `INSERT INTO moz_bookmarks (id, type, parent, position, title, dateAdded, lastModified, guid)
+		VALUES (20, 2, 2, 0, 'From WAL', 0, 0, 'folder000020')`)
+	if err := os.WriteFile(path+"-shm", make([]byte, 32768), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := CreateStagingWithOptions(path, Options{StagingDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if err := s.AddBookmark(placestest.ToolbarID, "Staged", "https://staged.example/"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, sidecar := range []string{path + "-wal", path + "-shm", s.stagingPath + "-wal", s.stagingPath + "-shm"} {
+		if _, err := os.Stat(sidecar); !os.IsNotExist(err) {
+			t.Errorf("%s still exists after commit", filepath.Base(sidecar))
+		}
+	}
+	if _, err := os.Stat(s.BackupPath() + "-wal"); err != nil {
+		t.Errorf("backup has no copy of the write-ahead log: %v", err)
+	}
+
+	conn, err := db.OpenReadOnly(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	for _, title := range []string{"From WAL", "Staged"} {
+		var count int
+		if err := conn.Conn().QueryRow("SELECT COUNT(*) FROM moz_bookmarks WHERE title = ?", title).Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+		if count != 1 {
+			t.Errorf("committed database has %d rows titled %q, want 1", count, title)
+		}
+	}
+}
+
+func TestTrashAndArchiveRoundTrip(t *testing.T) {
+	s := newTestStaging(t,
+		`INSERT INTO moz_places (id, url, title) VALUES (1, 'https://example.com/', 'Example')`,
+		`INSERT INTO moz_bookmarks (id, type, fk, parent, position, title, dateAdded, lastModified, guid) VALUES
+			(10, 1, 1, 3, 0, 'Trashed', 0, 0, 'bookmark0010'),
+			(11, 1, 1, 5, 0, 'Archived', 0, 0, 'bookmark0011')`,
+	)
+
+	tests := []struct {
+		name    string
+		id      int64
+		parent  int64
+		move    func(int64) error
+		restore func(int64) (int64, error)
+	}{
+		{"trash", 10, placestest.ToolbarID, s.TrashBookmark, s.RestoreFromTrash},
+		{"archive", 11, placestest.UnfiledID, s.ArchiveDeadLink, s.UnarchiveDeadLink},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.restore(tt.id); err == nil {
+				t.Fatal("restoring a bookmark that wasn't moved succeeded")
+			}
+			if err := tt.move(tt.id); err != nil {
+				t.Fatal(err)
+			}
+			parentID, err := tt.restore(tt.id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if parentID != tt.parent {
+				t.Errorf("restored to folder %d, want %d", parentID, tt.parent)
+			}
+
+			var parent int64
+			if err := s.Conn().QueryRow("SELECT parent FROM moz_bookmarks WHERE id = ?", tt.id).Scan(&parent); err != nil {
+				t.Fatal(err)
+			}
+			if parent != tt.parent {
+				t.Errorf("bookmark %d is in folder %d, want %d", tt.id, parent, tt.parent)
+			}
+			if _, err := tt.restore(tt.id); err == nil {
+				t.Error("restoring the same bookmark twice succeeded")
+			}
+		})
+	}
+}
+
+func TestTrashLeavesSameTitledFolder(t *testing.T) {
+	s := newTestStaging(t,
+		`INSERT INTO moz_places (id, url, title) VALUES (1, 'https://example.com/', 'Example')`,
+		`INSERT INTO moz_bookmarks (id, type, fk, parent, position, title, dateAdded, lastModified, guid) VALUES
+			(20, 2, NULL, 3, 0, 'GopherMark Trash', 0, 0, 'folder000020'),
+			(21, 2, NULL, 20, 0, '☠ Dead Links', 0, 0, 'folder000021'),
+			(10, 1, 1, 21, 0, 'Kept', 0, 0, 'bookmark0010'),
+			(11, 1, 1, 3, 1, 'Trashed', 0, 0, 'bookmark0011'),
+			(12, 1, 1, 3, 2, 'Archived', 0, 0, 'bookmark0012')`,
+	)
+
+	if trashID, err := s.FindTrashFolder(); err != nil || trashID != 0 {
+		t.Fatalf("FindTrashFolder = %d, %v; want no trash", trashID, err)
+	}
+	if err := s.TrashBookmark(11); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ArchiveDeadLink(12); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.EmptyTrash(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		id         int64
+		wantParent int64
+	}{
+		{20, placestest.ToolbarID},
+		{21, 20},
+		{10, 21},
+	} {
+		var parent int64
+		if err := s.Conn().QueryRow("SELECT parent FROM moz_bookmarks WHERE id = ?", tt.id).Scan(&parent); err != nil {
+			t.Fatalf("row %d: %v", tt.id, err)
+		}
+		if parent != tt.wantParent {
+			t.Errorf("row %d is in folder %d, want %d", tt.id, parent, tt.wantParent)
+		}
+	}
+
+	var archive string
+	err := s.Conn().QueryRow("SELECT f.guid FROM moz_bookmarks b JOIN moz_bookmarks f ON f.id = b.parent WHERE b.id = 12").Scan(&archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if archive != DeadLinksFolderGUID {
+		t.Errorf("archived into folder %q, want %q", archive, DeadLinksFolderGUID)
+	}
+	var trashed int
+	if err := s.Conn().QueryRow("SELECT COUNT(*) FROM moz_bookmarks WHERE id = 11").Scan(&trashed); err != nil {
+		t.Fatal(err)
+	}
+	if trashed != 0 {
+		t.Error("emptying the trash left the trashed bookmark")
+	}
+}
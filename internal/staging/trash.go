@@ -0,0 +1,127 @@
+package staging
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// TrashFolderTitle names the folder TrashBookmark moves bookmarks into. It
+// sits in the bookmarks menu like Scratch until EmptyTrash removes it, and
+// is found by TrashFolderGUID so a user folder with the same title is left
+// alone.
+const (
+	TrashFolderTitle = "GopherMark Trash"
+	TrashFolderGUID  = "gmtrash_____"
+)
+
+// FindTrashFolder returns the trash folder's ID, or 0 if there's no trash.
+func (s *StagingDB) FindTrashFolder() (int64, error) {
+	var folderID int64
+	err := s.conn.QueryRow("SELECT id FROM moz_bookmarks WHERE type = 2 AND guid = ?", TrashFolderGUID).Scan(&folderID)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to query trash folder: %w", err)
+	}
+	return folderID, nil
+}
+
+func (s *StagingDB) FindOrCreateTrashFolder() (int64, error) {
+	return s.findOrCreateMenuFolder(TrashFolderTitle, TrashFolderGUID)
+}
+
+// TrashBookmark moves a bookmark to the end of the trash folder, creating the
+// folder if needed, and remembers where it came from for RestoreFromTrash.
+// Nothing is deleted until EmptyTrash.
+func (s *StagingDB) TrashBookmark(bookmarkID int64) error {
+	trashID, err := s.FindOrCreateTrashFolder()
+	if err != nil {
+		return err
+	}
+	return s.moveWithOrigin(bookmarkID, trashID, s.trashOrigins, "trash")
+}
+
+// RestoreFromTrash moves a trashed bookmark back to the end of the folder it
+// was trashed from and returns that folder's ID.
+func (s *StagingDB) RestoreFromTrash(bookmarkID int64) (int64, error) {
+	return s.moveToOrigin(bookmarkID, s.trashOrigins, "trashed", "restore")
+}
+
+// EmptyTrash deletes the trash folder and everything in it. Without a trash
+// folder there's nothing to do.
+func (s *StagingDB) EmptyTrash() error {
+	trashID, err := s.FindTrashFolder()
+	if err != nil || trashID == 0 {
+		return err
+	}
+
+	if err := s.DeleteFolderRecursive(trashID); err != nil {
+		return fmt.Errorf("failed to empty trash: %w", err)
+	}
+	clear(s.trashOrigins)
+	return nil
+}
+
+func (s *StagingDB) moveToEnd(bookmarkID, parentID int64) error {
+	var maxPosition int
+	err := s.conn.QueryRow("SELECT COALESCE(MAX(position), -1) FROM moz_bookmarks WHERE parent = ?", parentID).Scan(&maxPosition)
+	if err != nil {
+		return fmt.Errorf("failed to get max position: %w", err)
+	}
+
+	_, err = s.conn.Exec("UPDATE moz_bookmarks SET parent = ?, position = ?, lastModified = ? WHERE id = ?",
+		parentID, maxPosition+1, currentMicroseconds(), bookmarkID)
+	if err != nil {
+		return fmt.Errorf("failed to move bookmark %d: %w", bookmarkID, err)
+	}
+	return nil
+}
+
+// moveWithOrigin moves a bookmark to the end of folderID and records the
+// folder it left in origins, so moveToOrigin can put it back. A bookmark
+// already in folderID stays where it is.
+func (s *StagingDB) moveWithOrigin(bookmarkID, folderID int64, origins map[int64]int64, action string) error {
+	var parentID int64
+	if err := s.conn.QueryRow("SELECT parent FROM moz_bookmarks WHERE id = ?", bookmarkID).Scan(&parentID); err != nil {
+		return fmt.Errorf("failed to find bookmark %d: %w", bookmarkID, err)
+	}
+	if parentID == folderID {
+		return nil
+	}
+
+	if err := s.moveToEnd(bookmarkID, folderID); err != nil {
+		return err
+	}
+
+	origins[bookmarkID] = parentID
+	s.record(action, bookmarkID, fmt.Sprintf("parent=%d", parentID), fmt.Sprintf("parent=%d", folderID))
+	return nil
+}
+
+// moveToOrigin moves a bookmark recorded in origins back to the end of the
+// folder it came from and returns that folder's ID. moved says how it got
+// away, for the error when it isn't in origins.
+func (s *StagingDB) moveToOrigin(bookmarkID int64, origins map[int64]int64, moved, action string) (int64, error) {
+	parentID, ok := origins[bookmarkID]
+	if !ok {
+		return 0, fmt.Errorf("bookmark %d wasn't %s in this session", bookmarkID, moved)
+	}
+
+	var exists int
+	err := s.conn.QueryRow("SELECT COUNT(*) FROM moz_bookmarks WHERE id = ? AND type = 2", parentID).Scan(&exists)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find original folder: %w", err)
+	}
+	if exists == 0 {
+		return 0, fmt.Errorf("original folder %d no longer exists", parentID)
+	}
+
+	if err := s.moveToEnd(bookmarkID, parentID); err != nil {
+		return 0, err
+	}
+
+	delete(origins, bookmarkID)
+	s.record(action, bookmarkID, "", fmt.Sprintf("parent=%d", parentID))
+	return parentID, nil
+}
@@ -1,34 +1,59 @@
 package ui
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/levineuwirth/gophermark/internal/audit"
+	"github.com/levineuwirth/gophermark/internal/config"
 	"github.com/levineuwirth/gophermark/internal/db"
 	"github.com/levineuwirth/gophermark/internal/dedup"
 	"github.com/levineuwirth/gophermark/internal/export"
+	"github.com/levineuwirth/gophermark/internal/importer"
 	"github.com/levineuwirth/gophermark/internal/models"
+	"github.com/levineuwirth/gophermark/internal/search"
 	"github.com/levineuwirth/gophermark/internal/staging"
 )
 
 var debugLog *log.Logger
 
+// SetDebugLog directs debug output to w. Logging is off by default; pass
+// nil to turn it back off.
+func SetDebugLog(w io.Writer) {
+	if w == nil {
+		debugLog = nil
+		return
+	}
+	debugLog = log.New(w, "", log.Ltime|log.Lmicroseconds|log.Lshortfile)
+}
+
 func init() {
-	f, err := os.OpenFile("/tmp/gophermark-debug.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
-	if err == nil {
-		debugLog = log.New(f, "", log.Ltime|log.Lmicroseconds|log.Lshortfile)
+	if path := os.Getenv("GOPHERMARK_DEBUG"); path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err == nil {
+			SetDebugLog(f)
+		}
 	}
 }
 
+// similarTitleThreshold is the edit distance under which two titles are
+// reported as probable duplicates.
+const similarTitleThreshold = 2
+
 type Pane int
 
 const (
@@ -51,6 +76,25 @@ const (
 	DedupMode
 	ScratchAdd
 	BulkMoveMode
+	ConfirmDelete
+	EmptyFoldersMode
+	RenameFolder
+	ImportMode
+	FolderSwitchMode
+	ReplaceMode
+	ReplacePreviewMode
+	ConfirmDeleteFolder
+	AuditDiffMode
+	ConfirmCommit
+	ConfirmOpen
+	ConfirmEmptyTrash
+	ConfirmHTTPSUpgrade
+	FolderDupsMode
+	StatsMode
+	HistoryMode
+	ConfirmQuit
+	ConfirmArchiveDead
+	ExportPreviewMode
 )
 
 type Model struct {
@@ -71,57 +115,143 @@ type Model struct {
 	ready      bool
 	err        error
 
+	loading       bool
+	loadConn      *db.DB
+	loadCancel    context.CancelFunc
+	loadCancelled bool
+
+	numPanes   int
+	paneWidth  int
+	paneHeight int
+
 	editMode      EditMode
 	titleInput    textinput.Model
 	urlInput      textinput.Model
 	searchInput   textinput.Model
 	scratchInput  textinput.Model
+	importInput   textinput.Model
+	folderInput   textinput.Model
+	findInput     textinput.Model
+	replaceInput  textinput.Model
 	statusMessage string
 
-	searchResults []*models.Bookmark
+	searchResults []search.Result
 	inSearchMode  bool
+	deadOnly      bool
 
 	dbPath            string
 	stagingDB         *staging.StagingDB
 	hasPendingChanges bool
+	config            *config.Config
+
+	exportSelectedOnly bool
+	exportStatus       exportStatus
+	exportPreview      exportPreview
+	commitBlockedBy    string
+	quitAfterCommit    bool
+
+	themeIndex  int
+	showColumns bool
+	sortFolders bool
+
+	showHelp   bool
+	helpScroll int
+
+	openURLs    []string
+	openSkipped int
+
+	pageTitle pageTitle
+
+	httpsUpgrades       []httpsUpgrade
+	httpsUpgradeSkipped int
+
+	archiveCandidates []*models.Bookmark // dead links waiting on ConfirmArchiveDead
+
+	folderBack        []*models.Bookmark
+	folderForward     []*models.Bookmark
+	navigatingHistory bool
 
 	showInspector    bool
 	auditResults     map[int64]string
+	auditDetails     map[int64]audit.LinkResult
+	auditResultChan  <-chan audit.LinkResult
+	auditCancel      context.CancelFunc
+	auditCancelled   bool
 	auditInProgress  bool
+	auditScope       *models.Bookmark // folder audited, or nil for everything
 	auditTotal       int
 	auditCompleted   int
-	dedupGroups      []string
+	auditCounts      map[audit.LinkStatus]int
+	auditDeadLinks   []*models.Bookmark
+	auditParkedLinks []*models.Bookmark
+	auditSelected    int
+	auditDiff        *audit.ResultsDiff
+	auditCache       *audit.Cache
+	auditCached      int // results this run reused from the cache
+	auditDiffSince   time.Time
+	auditDiffCursor  int
+	dedupResults     []dedup.DuplicateGroup // dedupAll, less any filtered out
+	dedupAll         []dedup.DuplicateGroup
+	dedupAllExact    int
+	dedupSameFolder  bool
+	dedupFolders     map[int64]bool
+	dedupExactCount  int
 	dedupSelected    int
+	dedupDetail      bool
+	dedupKeep        int
+	dedupPaths       map[int64]string
 	dedupScanning    bool
+	dedupScanned     *atomic.Int64
 	scanSpinner      int
 	viewCount        int
 
+	// Counts the folder tree shows, kept so drawing it doesn't walk every
+	// bookmark. refreshTreeCounts rebuilds them when the tree or the audit
+	// results change.
+	tagIndex       map[string][]*models.Bookmark
+	folderTotals   map[int64]int
+	deadLinkCounts map[int64]int
+
 	bulkMoveFolders  []*models.Bookmark
 	bulkMoveSelected int
-}
 
-func NewModel(root *models.Bookmark, folders []*models.Bookmark, dbPath string) *Model {
-	expandedFolders := make(map[int64]bool)
+	emptyFolders        []*models.Bookmark
+	emptyFolderPaths    map[int64]string
+	emptyFolderSelected int
 
-	bookmarksBar := FindBookmarksBar(root)
-	var currentFolder *models.Bookmark
-	if bookmarksBar != nil {
-		ExpandPath(root, bookmarksBar, expandedFolders)
-		currentFolder = bookmarksBar
-	} else if len(folders) > 0 {
-		currentFolder = folders[0]
-	}
+	folderDups         []dedup.FolderGroup
+	folderDupPaths     map[int64]string
+	folderDupSelected  int
+	folderDupsAnywhere bool
 
-	treeNodes := BuildFlatTree(root, expandedFolders)
+	stats collectionStats
 
-	treeCursor := 0
-	if bookmarksBar != nil {
-		idx := FindNodeIndex(treeNodes, bookmarksBar.ID)
-		if idx >= 0 {
-			treeCursor = idx
-		}
-	}
+	historyPlaces   []db.HistoryPlace
+	historySelected int
+	historyMarked   map[int64]bool
+	historyLoading  bool
+
+	renamingFolder *models.Bookmark
+	deletingFolder *models.Bookmark
+
+	folderMatches  []FolderMatch
+	folderSelected int
+	cloning        *models.Bookmark // set while the folder picker chooses a clone's destination
+
+	replaceRegex    bool
+	replacements    []URLReplacement
+	replaceSelected int
+}
+
+func NewModel(root *models.Bookmark, folders []*models.Bookmark, dbPath string) *Model {
+	m := newModel(dbPath)
+	m.setData(root, folders)
+	return m
+}
 
+// newModel sets up everything that doesn't depend on the bookmarks, which
+// setData fills in once they're loaded.
+func newModel(dbPath string) *Model {
 	titleInput := textinput.New()
 	titleInput.Placeholder = "Bookmark title"
 	titleInput.CharLimit = 256
@@ -138,38 +268,116 @@ func NewModel(root *models.Bookmark, folders []*models.Bookmark, dbPath string)
 	scratchInput.Placeholder = "https://example.com"
 	scratchInput.CharLimit = 2048
 
+	importInput := textinput.New()
+	importInput.Placeholder = "~/.config/google-chrome/Default/Bookmarks"
+	importInput.CharLimit = 4096
+
+	folderInput := textinput.New()
+	folderInput.Placeholder = "Jump to folder..."
+	folderInput.CharLimit = 256
+
+	findInput := textinput.New()
+	findInput.Placeholder = "http://nas.local:8080"
+	findInput.CharLimit = 2048
+
+	replaceInput := textinput.New()
+	replaceInput.Placeholder = "https://nas.home.arpa"
+	replaceInput.CharLimit = 2048
+
+	cfg, err := config.Load()
+	if err != nil {
+		if debugLog != nil {
+			debugLog.Printf("NewModel: ignoring unreadable config: %v", err)
+		}
+		cfg = &config.Config{}
+	}
+
+	themeIndex := findTheme(cfg.Theme)
+	applyTheme(themes[themeIndex])
+
 	return &Model{
-		root:              root,
-		treeNodes:         treeNodes,
-		currentFolder:     currentFolder,
-		bookmarks:         getBookmarksForFolder(currentFolder),
-		expandedFolders:   expandedFolders,
+		expandedFolders:   make(map[int64]bool),
 		selectedBookmarks: make(map[int64]bool),
 		activePane:        TreePane,
-		treeCursor:        treeCursor,
-		listCursor:        0,
 		dbPath:            dbPath,
 		titleInput:        titleInput,
 		urlInput:          urlInput,
 		searchInput:       searchInput,
 		scratchInput:      scratchInput,
+		importInput:       importInput,
+		folderInput:       folderInput,
+		findInput:         findInput,
+		replaceInput:      replaceInput,
 		editMode:          EditNone,
 		auditResults:      make(map[int64]string),
-		showInspector:     false,
+		auditDetails:      make(map[int64]audit.LinkResult),
+		showInspector:     cfg.ShowInspector,
+		sortFolders:       cfg.SortFolders,
+		config:            cfg,
+		themeIndex:        themeIndex,
+	}
+}
+
+// setData shows root, opening the Bookmarks Bar, or the first folder if
+// there isn't one.
+func (m *Model) setData(root *models.Bookmark, folders []*models.Bookmark) {
+	bookmarksBar := FindBookmarksBar(root, m.config.BookmarksBarTitles...)
+	var currentFolder *models.Bookmark
+	if bookmarksBar != nil {
+		ExpandPath(root, bookmarksBar, m.expandedFolders)
+		currentFolder = bookmarksBar
+	} else if len(folders) > 0 {
+		currentFolder = folders[0]
+	}
+
+	treeNodes := BuildFlatTree(root, m.expandedFolders, m.sortFolders)
+
+	treeCursor := 0
+	if bookmarksBar != nil {
+		idx := FindNodeIndex(treeNodes, bookmarksBar.ID)
+		if idx >= 0 {
+			treeCursor = idx
+		}
+	}
+
+	m.root = root
+	m.treeNodes = treeNodes
+	m.currentFolder = currentFolder
+	m.bookmarks = getBookmarksForFolder(currentFolder)
+	m.treeCursor = treeCursor
+	m.listCursor = 0
+	m.refreshTreeCounts()
+}
+
+// markChanged records a staged edit and brings the tree's counts up to
+// date with it.
+func (m *Model) markChanged() {
+	m.hasPendingChanges = true
+	m.refreshTreeCounts()
+}
+
+// refreshTreeCounts rebuilds the tag index and the bookmark and dead link
+// counts renderTree shows. Dead links are only counted once an audit has
+// finished.
+func (m *Model) refreshTreeCounts() {
+	m.tagIndex = buildTagIndex(m.root)
+	m.folderTotals = countFolderTotals(m.root)
+	m.deadLinkCounts = nil
+	if len(m.auditResults) > 0 && !m.auditInProgress {
+		m.deadLinkCounts = countDeadLinks(m.root, m.auditResults)
 	}
 }
 
 type auditProgressMsg struct {
-	total     int
-	completed int
-	result    audit.LinkResult
+	result audit.LinkResult
 }
 
 type auditCompleteMsg struct{}
 
 type dedupResultMsg struct {
-	groups []dedup.DuplicateGroup
-	err    error
+	groups  []dedup.DuplicateGroup
+	similar []dedup.DuplicateGroup
+	err     error
 }
 
 type dedupTickMsg struct{}
@@ -177,10 +385,239 @@ type dedupTickMsg struct{}
 type auditTickMsg struct{}
 
 func (m *Model) Init() tea.Cmd {
-	return nil
+	if m.loading {
+		return tea.Batch(tea.EnableMouseCellMotion, m.loadData(), m.tickLoad())
+	}
+	return tea.EnableMouseCellMotion
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// The folder can change in many places; recording the history here
+	// catches all of them.
+	previousFolder := m.currentFolder
+	defer func() {
+		if previousFolder != nil && m.currentFolder != previousFolder && !m.navigatingHistory {
+			m.pushFolderHistory(previousFolder)
+		}
+		m.navigatingHistory = false
+	}()
+
+	switch msg := msg.(type) {
+	case dataLoadedMsg:
+		m.loading = false
+		m.loadCancel()
+		if m.loadCancelled {
+			return m, tea.Quit
+		}
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.setData(msg.root, msg.folders)
+		if msg.orphans > 0 {
+			m.statusMessage = fmt.Sprintf("⚠ %d bookmarks have a missing parent folder; they're listed under %s (t or b moves them)",
+				msg.orphans, db.OrphanedFolderTitle)
+		}
+		return m, nil
+
+	case loadTickMsg:
+		if m.loading {
+			m.scanSpinner = (m.scanSpinner + 1) % 4
+			return m, m.tickLoad()
+		}
+		return m, nil
+
+	case auditProgressMsg:
+		m.auditCompleted++
+		if msg.result.Cached {
+			m.auditCached++
+		}
+		m.auditCounts[msg.result.Status]++
+		m.auditDetails[msg.result.Bookmark.ID] = msg.result
+		switch msg.result.Status {
+		case audit.StatusDead, audit.StatusTimeout:
+			m.auditResults[msg.result.Bookmark.ID] = "DEAD"
+		case audit.StatusRedirectHTTPS:
+			m.auditResults[msg.result.Bookmark.ID] = "HTTPS"
+		case audit.StatusSkipped:
+			m.auditResults[msg.result.Bookmark.ID] = "SKIPPED (non-web)"
+		case audit.StatusParked:
+			m.auditResults[msg.result.Bookmark.ID] = "PARKED"
+		default:
+			m.auditResults[msg.result.Bookmark.ID] = "OK"
+		}
+		return m, waitForAuditResult(m.auditResultChan)
+
+	case auditTickMsg:
+		if m.auditInProgress {
+			m.scanSpinner = (m.scanSpinner + 1) % 4
+			return m, m.tickAudit()
+		}
+		return m, nil
+
+	case auditCompleteMsg:
+		m.auditInProgress = false
+		m.refreshTreeCounts()
+		m.auditResultChan = nil
+		if m.auditCancel != nil {
+			m.auditCancel()
+			m.auditCancel = nil
+		}
+		m.saveAuditCache()
+		m.auditDeadLinks = nil
+		m.auditParkedLinks = nil
+		m.auditSelected = 0
+		for _, bookmark := range collectAllBookmarks(m.root) {
+			switch m.auditResults[bookmark.ID] {
+			case "DEAD":
+				m.auditDeadLinks = append(m.auditDeadLinks, bookmark)
+			case "PARKED":
+				m.auditParkedLinks = append(m.auditParkedLinks, bookmark)
+			}
+		}
+		if m.auditCancelled {
+			m.statusMessage = fmt.Sprintf("Audit cancelled: kept %d/%d results, %d dead links found",
+				m.auditCompleted, m.auditTotal, len(m.auditDeadLinks))
+		} else {
+			m.statusMessage = fmt.Sprintf("✓ Audit complete: %d dead links found", len(m.auditDeadLinks))
+			if len(m.auditParkedLinks) > 0 {
+				m.statusMessage += fmt.Sprintf(", %d parked domains", len(m.auditParkedLinks))
+			}
+			if m.auditCached > 0 {
+				m.statusMessage += fmt.Sprintf(" (%d checked recently, from cache)", m.auditCached)
+			}
+			// The saved results cover the whole database; a folder's
+			// results would report everything outside it as gone.
+			if m.auditScope == nil {
+				m.compareWithLastAudit()
+			} else {
+				m.auditDiff = nil
+			}
+		}
+		return m, nil
+
+	case dedupTickMsg:
+		if debugLog != nil {
+			debugLog.Printf("Update: received dedupTickMsg, scanning=%v", m.dedupScanning)
+		}
+		if m.dedupScanning {
+			m.scanSpinner = (m.scanSpinner + 1) % 4
+			return m, m.tickDedup()
+		}
+		return m, nil
+
+	case dedupResultMsg:
+		if debugLog != nil {
+			debugLog.Printf("Update: received dedupResultMsg with %d groups, err=%v", len(msg.groups), msg.err)
+		}
+		m.dedupScanning = false
+		if msg.err != nil {
+			m.statusMessage = "❌ Dedup failed: " + withAdvice(msg.err)
+			m.editMode = EditNone
+			if debugLog != nil {
+				debugLog.Println("Update: dedupResultMsg handling complete (error case)")
+			}
+			return m, nil
+		}
+
+		m.dedupAll = append(msg.groups, msg.similar...)
+		m.dedupAllExact = len(msg.groups)
+		m.dedupFolders = duplicateFolders(msg.groups)
+		m.dedupSelected = 0
+		m.dedupDetail = false
+		m.filterDedup()
+
+		if len(m.dedupAll) == 0 {
+			m.statusMessage = "✓ No duplicates found"
+		} else {
+			m.statusMessage = fmt.Sprintf("Found %d duplicate groups, %d probable", len(msg.groups), len(msg.similar))
+		}
+		if debugLog != nil {
+			debugLog.Println("Update: dedupResultMsg handling complete (success case)")
+		}
+		return m, nil
+
+	case urlsOpenedMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("❌ Opened %d links, then: %v", msg.opened, msg.err)
+		} else {
+			m.statusMessage = fmt.Sprintf("✓ Opened %d links", msg.opened)
+		}
+		if m.openSkipped > 0 {
+			m.statusMessage += fmt.Sprintf(" (skipped %d non-web)", m.openSkipped)
+		}
+		return m, nil
+
+	case historyLoadedMsg:
+		m.historyLoading = false
+		if m.editMode != HistoryMode {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.editMode = EditNone
+			m.statusMessage = "❌ Couldn't read history: " + withAdvice(msg.err)
+			return m, nil
+		}
+		m.setHistoryPlaces(msg.places)
+		m.statusMessage = fmt.Sprintf("Found %d pages visited %d+ times without a bookmark", len(m.historyPlaces), m.historyMinVisits())
+		return m, nil
+
+	case pageTitleMsg:
+		// A newer fetch for another bookmark replaces this one.
+		if msg.bookmarkID != m.pageTitle.bookmarkID {
+			return m, nil
+		}
+		m.pageTitle = pageTitle{bookmarkID: msg.bookmarkID, title: msg.title, err: msg.err}
+		if msg.err != nil {
+			m.statusMessage = "⚠ Couldn't fetch the page title: " + msg.err.Error()
+		} else {
+			m.statusMessage = fmt.Sprintf("Page title: %q (P: use it as the title)", msg.title)
+		}
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.ready = true
+		m.computeLayout()
+		return m, nil
+
+	case tea.MouseMsg:
+		if !m.showHelp && !m.loading {
+			m.handleMouse(msg)
+		}
+		return m, nil
+	}
+
+	if m.loading {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "ctrl+c", "q", "Q", "esc":
+				m.cancelLoad()
+			}
+		}
+		return m, nil
+	}
+
+	if m.showHelp {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "j", "down":
+				m.helpScroll++
+			case "k", "up":
+				if m.helpScroll > 0 {
+					m.helpScroll--
+				}
+			case "?", "esc":
+				m.showHelp = false
+			case "ctrl+c":
+				m.showHelp = false
+				return m.Update(msg)
+			}
+		}
+		return m, nil
+	}
+
 	if m.editMode == EditTitle {
 		var cmd tea.Cmd
 		m.titleInput, cmd = m.titleInput.Update(msg)
@@ -198,6 +635,25 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
+	if m.editMode == RenameFolder {
+		var cmd tea.Cmd
+		m.titleInput, cmd = m.titleInput.Update(msg)
+
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "enter":
+				m.saveFolderTitle()
+				return m, nil
+			case "esc":
+				m.editMode = EditNone
+				m.titleInput.Blur()
+				m.statusMessage = ""
+				return m, nil
+			}
+		}
+		return m, cmd
+	}
+
 	if m.editMode == EditURL {
 		var cmd tea.Cmd
 		m.urlInput, cmd = m.urlInput.Update(msg)
@@ -266,75 +722,350 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
-	if m.editMode == SearchMode {
+	if m.editMode == ImportMode {
 		var cmd tea.Cmd
-		m.searchInput, cmd = m.searchInput.Update(msg)
+		m.importInput, cmd = m.importInput.Update(msg)
 
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
 			switch keyMsg.String() {
-			case "enter", "esc":
-				m.exitSearchMode()
+			case "enter":
+				m.importChrome()
+				return m, nil
+			case "esc":
+				m.editMode = EditNone
+				m.importInput.Blur()
+				m.statusMessage = ""
 				return m, nil
-			default:
-				query := m.searchInput.Value()
-				if query == "" {
-					m.searchResults = nil
-					m.inSearchMode = false
-				} else {
-					m.searchResults = SearchBookmarks(m.root, query)
-					m.inSearchMode = true
-				}
-				m.listCursor = 0
 			}
 		}
 		return m, cmd
 	}
 
-	if m.editMode == ExportMode {
+	if m.editMode == ReplaceMode {
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
 			switch keyMsg.String() {
-			case "j":
-				m.exportJSON()
+			case "tab", "shift+tab":
+				if m.findInput.Focused() {
+					m.findInput.Blur()
+					m.replaceInput.Focus()
+				} else {
+					m.replaceInput.Blur()
+					m.findInput.Focus()
+				}
 				return m, nil
-			case "h":
-				m.exportHTML()
+			case "ctrl+r":
+				m.replaceRegex = !m.replaceRegex
+				return m, nil
+			case "enter":
+				m.previewReplacements()
 				return m, nil
 			case "esc":
 				m.editMode = EditNone
+				m.findInput.Blur()
+				m.replaceInput.Blur()
 				m.statusMessage = ""
 				return m, nil
 			}
 		}
-		return m, nil
-	}
 
-	if m.editMode == AuditMode {
-		if _, ok := msg.(tea.KeyMsg); ok {
-			if !m.auditInProgress {
-				m.editMode = EditNone
-				m.statusMessage = ""
-				return m, nil
-			}
+		var cmd tea.Cmd
+		if m.findInput.Focused() {
+			m.findInput, cmd = m.findInput.Update(msg)
+		} else {
+			m.replaceInput, cmd = m.replaceInput.Update(msg)
 		}
+		return m, cmd
 	}
 
-	if m.editMode == DedupMode {
+	if m.editMode == ReplacePreviewMode {
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
-			if m.dedupScanning {
+			switch keyMsg.String() {
+			case "j", "down":
+				if m.replaceSelected < len(m.replacements)-1 {
+					m.replaceSelected++
+				}
+			case "k", "up":
+				if m.replaceSelected > 0 {
+					m.replaceSelected--
+				}
+			case " ", "m":
+				if m.replaceSelected < len(m.replacements) {
+					r := &m.replacements[m.replaceSelected]
+					if r.Err == nil {
+						r.Selected = !r.Selected
+					}
+				}
+			case "enter":
+				m.applyReplacements()
+			case "esc":
+				m.editMode = ReplaceMode
+				m.statusMessage = ""
+			}
+		}
+		return m, nil
+	}
+
+	if m.editMode == FolderSwitchMode {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "down", "ctrl+n":
+				if m.folderSelected < len(m.folderMatches)-1 {
+					m.folderSelected++
+				}
+				return m, nil
+			case "up", "ctrl+p":
+				if m.folderSelected > 0 {
+					m.folderSelected--
+				}
+				return m, nil
+			case "enter":
+				m.editMode = EditNone
+				m.folderInput.Blur()
+				if m.folderSelected < len(m.folderMatches) {
+					if m.cloning != nil {
+						m.cloneBookmarkTo(m.folderMatches[m.folderSelected].Folder)
+					} else {
+						m.jumpToFolder(m.folderMatches[m.folderSelected].Folder)
+					}
+				}
+				m.cloning = nil
+				return m, nil
+			case "esc":
+				m.editMode = EditNone
+				m.folderInput.Blur()
+				m.cloning = nil
+				m.statusMessage = ""
+				return m, nil
+			}
+		}
+
+		var cmd tea.Cmd
+		m.folderInput, cmd = m.folderInput.Update(msg)
+		m.folderMatches = SearchFolders(m.root, m.folderInput.Value())
+		m.folderSelected = 0
+		return m, cmd
+	}
+
+	if m.editMode == SearchMode {
+		var cmd tea.Cmd
+		m.searchInput, cmd = m.searchInput.Update(msg)
+
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "enter":
+				m.showSearchResults()
+				return m, nil
+			case "esc":
+				m.exitSearchMode()
+				return m, nil
+			default:
+				query := m.searchInput.Value()
+				if query == "" {
+					m.searchResults = nil
+					m.inSearchMode = false
+				} else {
+					m.searchResults = search.BookmarksWithPaths(m.root, query)
+					m.inSearchMode = true
+				}
+				m.listCursor = 0
+			}
+		}
+		return m, cmd
+	}
+
+	if m.editMode == ExportMode {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "j":
+				m.exportJSON()
+				return m, nil
+			case "l":
+				m.exportJSONL()
+				return m, nil
+			case "h":
+				m.exportHTML()
+				return m, nil
+			case "v":
+				m.exportHTMLViewer()
+				return m, nil
+			case "m":
+				m.exportMarkdown()
+				return m, nil
+			case "o":
+				m.exportOPML()
+				return m, nil
+			case "s":
+				if len(m.selectedBookmarks) > 0 {
+					m.exportSelectedOnly = !m.exportSelectedOnly
+				}
+				return m, nil
+			case "f":
+				if len(m.auditResults) > 0 {
+					m.exportStatus = (m.exportStatus + 1) % numExportStatuses
+				}
+				return m, nil
+			case "esc":
+				m.editMode = EditNone
+				m.exportSelectedOnly = false
+				m.exportStatus = exportAll
+				m.statusMessage = ""
+				return m, nil
+			}
+		}
+		return m, nil
+	}
+
+	if m.editMode == ExportPreviewMode {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "y", "enter":
+				m.writeExport()
+				return m, nil
+			case "n", "esc":
+				m.exportPreview = exportPreview{}
+				m.editMode = ExportMode
+				m.statusMessage = "Export mode: choose format"
 				return m, nil
 			}
+		}
+		return m, nil
+	}
 
+	if m.editMode == AuditMode {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			if m.auditInProgress && keyMsg.String() == "esc" && !m.auditCancelled {
+				// Workers stop between links; results already in flight
+				// still arrive before auditCompleteMsg.
+				m.auditCancelled = true
+				m.auditCancel()
+				m.statusMessage = "Cancelling audit..."
+				return m, nil
+			}
+			if !m.auditInProgress {
+				switch keyMsg.String() {
+				case "j", "down":
+					if m.auditSelected < len(m.auditFlaggedLinks())-1 {
+						m.auditSelected++
+					}
+					return m, nil
+				case "k", "up":
+					if m.auditSelected > 0 {
+						m.auditSelected--
+					}
+					return m, nil
+				case "enter":
+					if flagged := m.auditFlaggedLinks(); len(flagged) > 0 {
+						m.editMode = EditNone
+						m.jumpToBookmark(flagged[m.auditSelected])
+						return m, nil
+					}
+				case "c":
+					if m.auditDiff != nil {
+						m.auditDiffCursor = 0
+						m.editMode = AuditDiffMode
+						return m, nil
+					}
+				case "w":
+					m.writeAuditReport()
+					return m, nil
+				case "H":
+					m.enterConfirmHTTPSUpgrade()
+					return m, nil
+				case "A":
+					m.enterConfirmArchiveDead()
+					return m, nil
+				}
+				m.editMode = EditNone
+				m.statusMessage = ""
+				return m, nil
+			}
+		}
+	}
+
+	if m.editMode == AuditDiffMode {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			changes := m.auditDiffTransitions()
 			switch keyMsg.String() {
 			case "j", "down":
-				if len(m.dedupGroups) > 0 && m.dedupSelected < len(m.dedupGroups)-1 {
+				if m.auditDiffCursor < len(changes)-1 {
+					m.auditDiffCursor++
+				}
+				return m, nil
+			case "k", "up":
+				if m.auditDiffCursor > 0 {
+					m.auditDiffCursor--
+				}
+				return m, nil
+			case "enter":
+				if m.auditDiffCursor < len(changes) {
+					m.editMode = EditNone
+					m.jumpToBookmark(changes[m.auditDiffCursor].After.Bookmark)
+					return m, nil
+				}
+			case "esc":
+				m.editMode = AuditMode
+				return m, nil
+			}
+			m.editMode = EditNone
+			m.statusMessage = ""
+		}
+		return m, nil
+	}
+
+	if m.editMode == DedupMode {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			if m.dedupScanning {
+				return m, nil
+			}
+
+			if m.dedupDetail {
+				group := m.dedupResults[m.dedupSelected]
+				switch keyMsg.String() {
+				case "j", "down":
+					if m.dedupKeep < len(group.Bookmarks)-1 {
+						m.dedupKeep++
+					}
+				case "k", "up":
+					if m.dedupKeep > 0 {
+						m.dedupKeep--
+					}
+				case "d":
+					m.resolveDuplicateGroup(false)
+				case "M":
+					m.resolveDuplicateGroup(true)
+				case "esc":
+					m.dedupDetail = false
+				}
+				return m, nil
+			}
+
+			switch keyMsg.String() {
+			case "j", "down":
+				if len(m.dedupResults) > 0 && m.dedupSelected < len(m.dedupResults)-1 {
 					m.dedupSelected++
 				}
 				return m, nil
 			case "k", "up":
-				if len(m.dedupGroups) > 0 && m.dedupSelected > 0 {
+				if len(m.dedupResults) > 0 && m.dedupSelected > 0 {
 					m.dedupSelected--
 				}
 				return m, nil
+			case "enter":
+				if len(m.dedupResults) > 0 {
+					m.dedupDetail = true
+					m.dedupKeep = m.dedupResults[m.dedupSelected].KeepIndex()
+					m.dedupPaths = db.FolderPaths(m.root)
+				}
+				return m, nil
+			case "s":
+				if len(m.dedupAll) > 0 {
+					m.dedupSameFolder = !m.dedupSameFolder
+					m.filterDedup()
+					return m, nil
+				}
+				m.editMode = EditNone
+				m.statusMessage = ""
+				return m, nil
 			default:
 				m.editMode = EditNone
 				m.statusMessage = ""
@@ -343,151 +1074,399 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
-	if m.editMode == BulkMoveMode {
+	if m.editMode == StatsMode {
+		if _, ok := msg.(tea.KeyMsg); ok {
+			m.editMode = EditNone
+		}
+		return m, nil
+	}
+
+	if m.editMode == HistoryMode {
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			if m.historyLoading {
+				return m, nil
+			}
 			switch keyMsg.String() {
 			case "j", "down":
-				if len(m.bulkMoveFolders) > 0 && m.bulkMoveSelected < len(m.bulkMoveFolders)-1 {
-					m.bulkMoveSelected++
+				if m.historySelected < len(m.historyPlaces)-1 {
+					m.historySelected++
 				}
 				return m, nil
 			case "k", "up":
-				if len(m.bulkMoveFolders) > 0 && m.bulkMoveSelected > 0 {
-					m.bulkMoveSelected--
+				if m.historySelected > 0 {
+					m.historySelected--
+				}
+				return m, nil
+			case " ", "m":
+				if len(m.historyPlaces) > 0 {
+					id := m.historyPlaces[m.historySelected].ID
+					if m.historyMarked[id] {
+						delete(m.historyMarked, id)
+					} else {
+						m.historyMarked[id] = true
+					}
 				}
 				return m, nil
 			case "enter":
-				return m.executeBulkMove(), nil
-			case "esc":
-				m.editMode = EditNone
-				m.statusMessage = ""
+				if len(m.historyPlaces) > 0 {
+					m.bookmarkHistoryPlaces()
+				}
 				return m, nil
 			}
+			m.editMode = EditNone
+			m.statusMessage = ""
 		}
 		return m, nil
 	}
 
-	switch msg := msg.(type) {
-	case auditProgressMsg:
-		m.auditTotal = msg.total
-		m.auditCompleted = msg.completed
-		if msg.result.Status == audit.StatusDead || msg.result.Status == audit.StatusTimeout {
-			m.auditResults[msg.result.Bookmark.ID] = "DEAD"
-		} else {
-			m.auditResults[msg.result.Bookmark.ID] = "OK"
+	if m.editMode == FolderDupsMode {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "j", "down":
+				if m.folderDupSelected < len(m.folderDups)-1 {
+					m.folderDupSelected++
+				}
+				return m, nil
+			case "k", "up":
+				if m.folderDupSelected > 0 {
+					m.folderDupSelected--
+				}
+				return m, nil
+			case "enter", "m":
+				if len(m.folderDups) > 0 {
+					m.mergeFolderGroup()
+				}
+				return m, nil
+			case "a":
+				m.folderDupsAnywhere = !m.folderDupsAnywhere
+				m.enterFolderDupsMode()
+				return m, nil
+			}
+			m.editMode = EditNone
+			m.statusMessage = ""
 		}
 		return m, nil
+	}
 
-	case auditTickMsg:
-		if m.auditInProgress {
-			m.scanSpinner = (m.scanSpinner + 1) % 4
-			return m, m.tickAudit()
+	if m.editMode == EmptyFoldersMode {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "j", "down":
+				if m.emptyFolderSelected < len(m.emptyFolders)-1 {
+					m.emptyFolderSelected++
+				}
+				return m, nil
+			case "k", "up":
+				if m.emptyFolderSelected > 0 {
+					m.emptyFolderSelected--
+				}
+				return m, nil
+			case "d":
+				if len(m.emptyFolders) > 0 {
+					m.deleteEmptyFolders()
+					return m, nil
+				}
+			}
+			m.editMode = EditNone
+			m.statusMessage = ""
 		}
 		return m, nil
+	}
 
-	case auditCompleteMsg:
-		m.auditInProgress = false
-		deadCount := 0
-		for _, status := range m.auditResults {
-			if status == "DEAD" {
-				deadCount++
+	if m.editMode == ConfirmDelete {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "y", "enter":
+				m.editMode = EditNone
+				m.deleteSelected()
+				return m, nil
+			case "n", "esc":
+				m.editMode = EditNone
+				m.statusMessage = "Delete cancelled"
+				return m, nil
 			}
 		}
-		m.statusMessage = fmt.Sprintf("✓ Audit complete: %d dead links found", deadCount)
 		return m, nil
+	}
 
-	case dedupTickMsg:
-		if debugLog != nil {
-			debugLog.Printf("Update: received dedupTickMsg, scanning=%v", m.dedupScanning)
-		}
-		if m.dedupScanning {
-			m.scanSpinner = (m.scanSpinner + 1) % 4
-			return m, m.tickDedup()
+	if m.editMode == ConfirmCommit {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "y":
+				m.editMode = EditNone
+				var trashed int
+				if trash := m.trashFolder(); trash != nil && len(trash.Children) > 0 {
+					trashed = countBookmarks(trash)
+					m.emptyTrash()
+					if m.trashFolder() != nil {
+						m.quitAfterCommit = false
+						return m, nil
+					}
+				}
+				m.commitChanges()
+				if !m.hasPendingChanges {
+					if trashed > 0 {
+						m.statusMessage += fmt.Sprintf(". Emptied the trash: %d bookmarks deleted", trashed)
+					}
+					m.config.CommitConfirmed = true
+					if err := m.config.Save(); err != nil && debugLog != nil {
+						debugLog.Printf("ConfirmCommit: %v", err)
+					}
+					if m.quitAfterCommit {
+						m.savePreferences()
+						return m, tea.Quit
+					}
+				}
+				m.quitAfterCommit = false
+				return m, nil
+			case "n", "esc":
+				m.editMode = EditNone
+				m.quitAfterCommit = false
+				m.statusMessage = "Commit cancelled; changes are still staged"
+				return m, nil
+			}
 		}
 		return m, nil
+	}
 
-	case dedupResultMsg:
-		if debugLog != nil {
-			debugLog.Printf("Update: received dedupResultMsg with %d groups, err=%v", len(msg.groups), msg.err)
+	if m.editMode == ConfirmQuit {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "c":
+				m.editMode = EditNone
+				if m.shouldConfirmCommit() {
+					// The first commit, or one that empties the trash, is
+					// still explained before it happens.
+					m.enterConfirmCommit()
+					m.quitAfterCommit = m.editMode == ConfirmCommit
+					return m, nil
+				}
+				m.commitChanges()
+				if m.hasPendingChanges {
+					return m, nil
+				}
+				m.savePreferences()
+				return m, tea.Quit
+			case "d":
+				if m.stagingDB != nil {
+					m.stagingDB.Rollback()
+				}
+				m.savePreferences()
+				return m, tea.Quit
+			case "esc", "n":
+				m.editMode = EditNone
+				m.statusMessage = "Changes are still staged (Ctrl+S to commit)"
+				return m, nil
+			}
 		}
-		m.dedupScanning = false
-		if msg.err != nil {
-			m.statusMessage = "❌ Dedup failed: " + msg.err.Error()
-			m.editMode = EditNone
-			if debugLog != nil {
-				debugLog.Println("Update: dedupResultMsg handling complete (error case)")
+		return m, nil
+	}
+
+	if m.editMode == ConfirmOpen {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "y", "enter":
+				m.editMode = EditNone
+				return m, m.openPendingURLs()
+			case "n", "esc":
+				m.editMode = EditNone
+				m.openURLs = nil
+				m.statusMessage = "Nothing opened"
+				return m, nil
 			}
-			return m, nil
 		}
+		return m, nil
+	}
 
-		if debugLog != nil {
-			debugLog.Println("Update: building group summaries")
+	if m.editMode == ConfirmEmptyTrash {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "y", "enter":
+				m.editMode = EditNone
+				m.emptyTrash()
+				return m, nil
+			case "n", "esc":
+				m.editMode = EditNone
+				m.statusMessage = "Trash left as it is"
+				return m, nil
+			}
 		}
-		var groupSummaries []string
-		for _, group := range msg.groups {
-			groupSummaries = append(groupSummaries, fmt.Sprintf("%s (%d duplicates)", group.URL, len(group.Bookmarks)))
+		return m, nil
+	}
+
+	if m.editMode == ConfirmHTTPSUpgrade {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "y", "enter":
+				m.editMode = AuditMode
+				m.applyHTTPSUpgrades()
+				return m, nil
+			case "n", "esc":
+				m.editMode = AuditMode
+				m.httpsUpgrades = nil
+				m.statusMessage = "No URLs changed"
+				return m, nil
+			}
 		}
-		m.dedupGroups = groupSummaries
-		m.dedupSelected = 0
+		return m, nil
+	}
 
-		if len(msg.groups) == 0 {
-			m.statusMessage = "✓ No duplicates found"
-		} else {
-			m.statusMessage = fmt.Sprintf("Found %d duplicate groups", len(msg.groups))
+	if m.editMode == ConfirmArchiveDead {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "y", "enter":
+				m.editMode = AuditMode
+				m.archiveDeadLinks()
+				return m, nil
+			case "n", "esc":
+				m.editMode = AuditMode
+				m.archiveCandidates = nil
+				m.statusMessage = "Nothing archived"
+				return m, nil
+			}
 		}
-		if debugLog != nil {
-			debugLog.Println("Update: dedupResultMsg handling complete (success case)")
+		return m, nil
+	}
+
+	if m.editMode == ConfirmDeleteFolder {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "Y":
+				m.editMode = EditNone
+				m.deleteFolderRecursive()
+				return m, nil
+			case "n", "esc":
+				m.editMode = EditNone
+				m.deletingFolder = nil
+				m.statusMessage = "Delete cancelled"
+				return m, nil
+			}
 		}
 		return m, nil
+	}
 
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		m.height = msg.Height
-		m.ready = true
+	if m.editMode == BulkMoveMode {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "j", "down":
+				if len(m.bulkMoveFolders) > 0 && m.bulkMoveSelected < len(m.bulkMoveFolders)-1 {
+					m.bulkMoveSelected++
+				}
+				return m, nil
+			case "k", "up":
+				if len(m.bulkMoveFolders) > 0 && m.bulkMoveSelected > 0 {
+					m.bulkMoveSelected--
+				}
+				return m, nil
+			case "enter":
+				return m.executeBulkMove(), nil
+			case "esc":
+				m.editMode = EditNone
+				m.statusMessage = ""
+				return m, nil
+			}
+		}
 		return m, nil
+	}
 
+	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "Q":
+		switch {
+		case key.Matches(msg, keys.ForceQuit):
 			if m.stagingDB != nil {
 				m.stagingDB.Close()
 			}
+			m.savePreferences()
 			return m, tea.Quit
 
-		case "q":
+		case key.Matches(msg, keys.Quit):
 			if m.hasPendingChanges {
-				m.statusMessage = "⚠ Unsaved changes! Press Ctrl+S to commit or Q (uppercase) to quit without saving"
+				m.editMode = ConfirmQuit
+				m.statusMessage = "⚠ Unsaved changes"
 				return m, nil
 			}
 			if m.stagingDB != nil {
 				m.stagingDB.Close()
 			}
+			m.savePreferences()
 			return m, tea.Quit
 
-		case "tab":
+		case key.Matches(msg, keys.SwitchPane):
 			m.togglePane()
 			return m, nil
 
-		case "j", "down":
+		case key.Matches(msg, keys.Down):
 			m.cursorDown()
 			return m, nil
 
-		case "k", "up":
+		case key.Matches(msg, keys.Up):
 			m.cursorUp()
 			return m, nil
 
-		case "enter", " ":
+		case key.Matches(msg, keys.Top):
+			m.cursorBy(-m.cursorCount())
+			return m, nil
+
+		case key.Matches(msg, keys.Bottom):
+			m.cursorBy(m.cursorCount())
+			return m, nil
+
+		case key.Matches(msg, keys.HalfPageDown):
+			m.cursorBy(m.pageRows() / 2)
+			return m, nil
+
+		case key.Matches(msg, keys.HalfPageUp):
+			m.cursorBy(-m.pageRows() / 2)
+			return m, nil
+
+		case key.Matches(msg, keys.PageDown):
+			m.cursorBy(m.pageRows())
+			return m, nil
+
+		case key.Matches(msg, keys.PageUp):
+			m.cursorBy(-m.pageRows())
+			return m, nil
+
+		case key.Matches(msg, keys.MoveDown):
+			if m.activePane == ListPane && m.editMode == EditNone {
+				m.moveListItem(1)
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.MoveUp):
+			if m.activePane == ListPane && m.editMode == EditNone {
+				m.moveListItem(-1)
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.Toggle):
 			if m.activePane == TreePane {
 				m.toggleOrSelectFolder()
 			}
 			return m, nil
 
-		case "esc", "escape":
+		case key.Matches(msg, keys.CollapseAll):
+			if m.editMode == EditNone {
+				m.collapseAll()
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.ExpandAll):
+			if m.editMode == EditNone {
+				m.expandAll()
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.Leave):
+			if m.inSearchMode {
+				m.exitSearchMode()
+				return m, nil
+			}
 			if m.currentFolder != nil && m.currentFolder.Title == "Scratch" {
-				bookmarksBar := FindBookmarksBar(m.root)
+				bookmarksBar := FindBookmarksBar(m.root, m.config.BookmarksBarTitles...)
 				if bookmarksBar != nil {
 					m.currentFolder = bookmarksBar
-					m.bookmarks = getBookmarksForFolder(m.currentFolder)
+					m.bookmarks = m.folderContents(m.currentFolder)
 					m.listCursor = 0
 					m.statusMessage = "Navigated to Bookmarks Bar"
 
@@ -499,67 +1478,253 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
-		case "e":
-			if m.activePane == ListPane && len(m.bookmarks) > 0 {
+		case key.Matches(msg, keys.Edit) && m.activePane == ListPane:
+			if m.selectedBookmark() != nil {
 				m.enterEditMode()
 			}
 			return m, nil
 
-		case "n":
+		case key.Matches(msg, keys.RenameFolder) && m.activePane == TreePane:
+			if m.treeCursor < len(m.treeNodes) {
+				m.enterRenameFolder(m.treeNodes[m.treeCursor].Folder)
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.New):
 			if m.activePane == ListPane && m.currentFolder != nil {
 				m.enterAddMode()
 			}
 			return m, nil
 
-		case "s":
+		case key.Matches(msg, keys.Separator):
+			if m.activePane == ListPane && m.currentFolder != nil && !m.inSearchMode {
+				m.insertSeparator()
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.Scratch):
 			if m.editMode == EditNone {
 				m.enterScratchMode()
 			}
 			return m, nil
 
-		case "S":
+		case key.Matches(msg, keys.JumpScratch):
 			if m.editMode == EditNone {
 				m.jumpToScratch()
 			}
 			return m, nil
 
-		case "b":
-			if m.editMode == EditNone && m.currentFolder != nil && m.currentFolder.Title == "Scratch" && len(m.selectedBookmarks) > 0 {
+		case key.Matches(msg, keys.BulkMove):
+			if m.editMode == EditNone && m.canBulkMove() && len(m.selectedBookmarks) > 0 {
 				m.enterBulkMoveMode()
 			}
 			return m, nil
 
-		case "m":
-			if m.activePane == ListPane && len(m.bookmarks) > 0 {
+		case key.Matches(msg, keys.Mark):
+			if m.activePane == ListPane && m.selectedBookmark() != nil {
 				m.toggleSelection()
 			}
 			return m, nil
 
-		case "d":
-			if m.activePane == ListPane && len(m.selectedBookmarks) > 0 {
-				m.deleteSelected()
+		case key.Matches(msg, keys.MarkAll):
+			if m.activePane == ListPane && m.editMode == EditNone {
+				m.selectAllVisible()
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.InvertMarks):
+			if m.activePane == ListPane && m.editMode == EditNone {
+				m.invertSelection()
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.Open):
+			if m.activePane == ListPane && m.editMode == EditNone {
+				return m, m.startOpenURLs()
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.FetchTitle):
+			if m.activePane == ListPane && m.editMode == EditNone {
+				return m, m.fetchPageTitle()
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.UseTitle):
+			if m.activePane == ListPane && m.editMode == EditNone {
+				m.applyPageTitle()
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.Back):
+			if m.editMode == EditNone {
+				m.folderHistoryBack()
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.Forward):
+			if m.editMode == EditNone {
+				m.folderHistoryForward()
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.Delete) && m.activePane == ListPane:
+			if len(m.selectedBookmarks) > 0 {
+				m.enterConfirmDelete()
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.DeleteFolder) && m.activePane == TreePane:
+			if m.treeCursor < len(m.treeNodes) {
+				m.enterConfirmDeleteFolder(m.treeNodes[m.treeCursor].Folder)
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.DeadOnly):
+			if m.activePane == ListPane {
+				m.toggleDeadOnly()
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.Columns):
+			if m.editMode == EditNone {
+				m.showColumns = !m.showColumns
+				if m.showColumns {
+					m.statusMessage = "List columns: title, visits, date added"
+				} else {
+					m.statusMessage = "List columns hidden"
+				}
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.Stash):
+			if m.activePane == ListPane && m.editMode == EditNone {
+				m.stashToScratch()
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.Clone):
+			if m.activePane == ListPane && m.editMode == EditNone {
+				m.enterCloneMode()
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.Trash):
+			if m.editMode == EditNone {
+				if m.inTrash() {
+					m.enterConfirmEmptyTrash()
+				} else {
+					m.jumpToTrash()
+				}
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.Restore):
+			if m.activePane == ListPane && m.editMode == EditNone {
+				if m.inTrash() {
+					m.restoreFromTrash()
+				} else if m.inDeadLinks() {
+					m.unarchiveDeadLinks()
+				}
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.CopyURL):
+			if m.activePane == ListPane {
+				m.copySelectedURL()
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.Search):
+			m.enterSearchMode()
+			return m, nil
+
+		case key.Matches(msg, keys.Export):
+			m.enterExportMode()
+			return m, nil
+
+		case key.Matches(msg, keys.Inspector):
+			m.toggleInspector()
+			return m, nil
+
+		case key.Matches(msg, keys.Theme):
+			m.cycleTheme()
+			return m, nil
+
+		case key.Matches(msg, keys.SortFolders):
+			if m.editMode == EditNone {
+				m.toggleFolderSort()
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.Help):
+			if m.editMode == EditNone {
+				m.showHelp = true
+				m.helpScroll = 0
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.Audit):
+			if m.editMode == EditNone {
+				return m, m.startAudit(nil)
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.AuditFolder):
+			if m.editMode == EditNone {
+				return m, m.startFolderAudit()
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.Reaudit):
+			if m.editMode == EditNone {
+				return m, m.startReaudit()
+			}
+			return m, nil
+
+		case key.Matches(msg, keys.FolderSwitch):
+			if m.editMode == EditNone {
+				m.enterFolderSwitch()
+				return m, nil
+			}
+
+		case key.Matches(msg, keys.Import):
+			if m.editMode == EditNone {
+				m.enterImportMode()
+				return m, nil
+			}
+
+		case key.Matches(msg, keys.Replace):
+			if m.editMode == EditNone {
+				m.enterReplaceMode()
+				return m, nil
 			}
-			return m, nil
 
-		case "/":
-			m.enterSearchMode()
-			return m, nil
+		case key.Matches(msg, keys.EmptyFolders):
+			if m.editMode == EditNone {
+				m.enterEmptyFoldersMode()
+				return m, nil
+			}
 
-		case "x":
-			m.enterExportMode()
+		case key.Matches(msg, keys.FolderDups):
+			if m.editMode == EditNone {
+				m.enterFolderDupsMode()
+			}
 			return m, nil
 
-		case "i":
-			m.toggleInspector()
+		case key.Matches(msg, keys.Stats):
+			if m.editMode == EditNone {
+				m.enterStatsMode()
+			}
 			return m, nil
 
-		case "a":
+		case key.Matches(msg, keys.History):
 			if m.editMode == EditNone {
-				return m, m.startAudit()
+				return m, m.enterHistoryMode()
 			}
 			return m, nil
 
-		case "D":
+		case key.Matches(msg, keys.Dedup):
 			if m.editMode == EditNone {
 				if debugLog != nil {
 					debugLog.Println("User pressed D key - starting dedup")
@@ -568,8 +1733,12 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
-		case "ctrl+s":
+		case key.Matches(msg, keys.Commit):
 			if m.hasPendingChanges {
+				if m.shouldConfirmCommit() {
+					m.enterConfirmCommit()
+					return m, nil
+				}
 				return m.commitChanges(), nil
 			}
 			return m, nil
@@ -585,49 +1754,66 @@ func (m *Model) View() string {
 		debugLog.Printf("View: called %d times, dedupScanning=%v, editMode=%d", m.viewCount, m.dedupScanning, m.editMode)
 	}
 
+	if m.loading {
+		return m.renderLoading()
+	}
+
 	if !m.ready {
 		return "Loading..."
 	}
 
 	if m.err != nil {
+		if advice := db.Advice(m.err); advice != "" {
+			return fmt.Sprintf("Error: %v\n%s\n", m.err, advice)
+		}
 		return fmt.Sprintf("Error: %v\n", m.err)
 	}
 
-	numPanes := 2
-	if m.showInspector {
-		numPanes = 3
+	if m.width < minPaneWidth+4 || m.height < minPaneHeight+8 {
+		return fmt.Sprintf("Terminal too small (%dx%d); resize to at least %dx%d",
+			m.width, m.height, minPaneWidth+4, minPaneHeight+8)
 	}
 
-	paneWidth := (m.width / numPanes) - 4
-	paneHeight := m.height - 8
-
-	treeContent := m.renderTree(paneHeight)
-	treePane := m.stylePane(TreePane, treeContent, paneWidth, paneHeight)
-
-	var listPane string
-	if m.editMode != EditNone {
-		listContent := m.renderEditForm(paneHeight)
-		listPane = m.stylePane(ListPane, listContent, paneWidth, paneHeight)
-	} else {
-		listContent := m.renderList(paneHeight)
-		listPane = m.stylePane(ListPane, listContent, paneWidth, paneHeight)
+	if m.showHelp {
+		return m.renderHelpOverlay()
 	}
 
-	var mainView string
-	if m.showInspector {
-		inspectorContent := m.renderInspector(paneHeight)
-		inspectorPane := m.stylePane(InspectorPane, inspectorContent, paneWidth, paneHeight)
-		mainView = lipgloss.JoinHorizontal(lipgloss.Top, treePane, listPane, inspectorPane)
-	} else {
-		mainView = lipgloss.JoinHorizontal(lipgloss.Top, treePane, listPane)
+	paneWidth, paneHeight := m.paneWidth, m.paneHeight
+
+	var panes []string
+	for _, pane := range m.visiblePanes() {
+		switch pane {
+		case TreePane:
+			panes = append(panes, m.stylePane(TreePane, m.renderTree(paneHeight), paneWidth, paneHeight))
+		case ListPane:
+			var listContent string
+			if m.editMode != EditNone {
+				listContent = m.renderEditForm(paneHeight)
+			} else {
+				listContent = m.renderList(paneWidth, paneHeight)
+			}
+			panes = append(panes, m.stylePane(ListPane, listContent, paneWidth, paneHeight))
+		case InspectorPane:
+			panes = append(panes, m.stylePane(InspectorPane, m.renderInspector(paneHeight), paneWidth, paneHeight))
+		}
 	}
+	mainView := lipgloss.JoinHorizontal(lipgloss.Top, panes...)
 
 	title := titleStyle.Render("GopherMark - Firefox/LibreWolf Bookmark Manager")
 
-	help := "j/k: nav | Space: toggle | Tab: switch | /: search | s: scratch | S: jump | n: new | e: edit | m: mark | x: export | i: inspector | a: audit | D: dedup | "
+	help := ""
+	if m.numPanes == 1 {
+		help += "⚠ terminal too narrow: showing one pane, Tab switches | "
+	} else if m.showInspector && m.numPanes < 3 {
+		help += "⚠ terminal too narrow for the inspector | "
+	}
+	help += shortHelp() + " | "
+	if len(m.auditResults) > 0 {
+		help += "f: dead only | R: re-audit dead | "
+	}
 	if len(m.selectedBookmarks) > 0 {
 		help += fmt.Sprintf("d: delete (%d) | ", len(m.selectedBookmarks))
-		if m.currentFolder != nil && m.currentFolder.Title == "Scratch" {
+		if m.canBulkMove() {
 			help += fmt.Sprintf("b: bulk move (%d) | ", len(m.selectedBookmarks))
 		}
 	}
@@ -635,7 +1821,8 @@ func (m *Model) View() string {
 		help += "Ctrl+S: commit | "
 	}
 	if m.auditInProgress {
-		help += fmt.Sprintf("Audit: %d/%d | ", m.auditCompleted, m.auditTotal)
+		spinnerFrames := []string{"⠋", "⠙", "⠹", "⠸"}
+		help += fmt.Sprintf("%s Audit: %d/%d | ", spinnerFrames[m.scanSpinner], m.auditCompleted, m.auditTotal)
 	}
 	help += "q: quit"
 
@@ -660,19 +1847,67 @@ func (m *Model) renderEditForm(maxHeight int) string {
 	var lines []string
 
 	if m.editMode == AuditMode {
-		lines = append(lines, folderStyle.Render("🔍 Link Audit"))
+		header := folderStyle.Render("🔍 Link Audit")
+		if m.auditScope != nil {
+			header += dimStyle.Render(" of " + truncatePathLeft(folderBreadcrumb(m.root, m.auditScope), 50))
+		}
+		lines = append(lines, header)
 		lines = append(lines, "")
 		if m.auditInProgress {
 			spinnerFrames := []string{"⠋", "⠙", "⠹", "⠸"}
 			spinner := spinnerFrames[m.scanSpinner]
 			progress := fmt.Sprintf("%s Progress: %d/%d", spinner, m.auditCompleted, m.auditTotal)
 			lines = append(lines, normalItemStyle.Render(progress))
+			lines = append(lines, normalItemStyle.Render(m.auditTally()))
+			lines = append(lines, "")
+			if m.auditCancelled {
+				lines = append(lines, dimStyle.Render("Cancelling..."))
+			} else {
+				lines = append(lines, dimStyle.Render("Checking links for broken URLs... (Esc: cancel)"))
+			}
+		} else if len(m.auditFlaggedLinks()) == 0 {
+			lines = append(lines, dimStyle.Render("Audit complete: no dead links"))
+			lines = append(lines, normalItemStyle.Render(m.auditTally()))
+			lines = append(lines, m.auditDiffSummary()...)
 			lines = append(lines, "")
-			lines = append(lines, dimStyle.Render("Checking links for broken URLs..."))
+			lines = append(lines, dimStyle.Render(m.auditHTTPSHint()+"w: save report | any other key: close"))
 		} else {
-			lines = append(lines, dimStyle.Render("Audit complete"))
+			summary := fmt.Sprintf("Audit complete: %d dead links", len(m.auditDeadLinks))
+			if len(m.auditParkedLinks) > 0 {
+				summary += fmt.Sprintf(", %d parked domains", len(m.auditParkedLinks))
+			}
+			lines = append(lines, normalItemStyle.Render(summary))
+			lines = append(lines, normalItemStyle.Render(m.auditTally()))
+			lines = append(lines, m.auditDiffSummary()...)
 			lines = append(lines, "")
-			lines = append(lines, dimStyle.Render("Press any key to close"))
+
+			flagged := m.auditFlaggedLinks()
+			start, end := scrollWindow(m.auditSelected, len(flagged), maxHeight-8)
+			for i := start; i < end; i++ {
+				if i == len(m.auditDeadLinks) {
+					lines = append(lines, dimStyle.Render(fmt.Sprintf("Parked domains (%d):", len(m.auditParkedLinks))))
+				}
+
+				bookmark := flagged[i]
+				prefix := "  "
+				style := normalItemStyle
+				if i == m.auditSelected {
+					prefix = "❯ "
+					style = selectedItemStyle
+				}
+
+				reason := m.auditDetails[bookmark.ID].Status.String()
+				if code := m.auditDetails[bookmark.ID].StatusCode; code != 0 && i < len(m.auditDeadLinks) {
+					reason = fmt.Sprintf("%d", code)
+				}
+				lines = append(lines, style.Render(fmt.Sprintf("%s[%s] %s", prefix, reason, bookmark.URL)))
+			}
+			lines = append(lines, "")
+			hint := "j/k: navigate | Enter: jump to bookmark | "
+			if len(m.auditDeadLinks) > 0 {
+				hint += "A: archive dead | "
+			}
+			lines = append(lines, dimStyle.Render(hint+m.auditHTTPSHint()+"w: save report | any other key: close"))
 		}
 		return strings.Join(lines, "\n")
 	}
@@ -686,86 +1921,481 @@ func (m *Model) renderEditForm(maxHeight int) string {
 			spinner := spinnerFrames[m.scanSpinner]
 			lines = append(lines, dimStyle.Render(spinner+" Scanning database for duplicates..."))
 			lines = append(lines, "")
+			if m.dedupScanned != nil {
+				lines = append(lines, normalItemStyle.Render(fmt.Sprintf("Scanned %d rows", m.dedupScanned.Load())))
+				lines = append(lines, "")
+			}
 			lines = append(lines, dimStyle.Render("This may take a moment for large databases."))
-		} else if len(m.dedupGroups) == 0 {
+		} else if len(m.dedupAll) == 0 {
 			lines = append(lines, dimStyle.Render("No duplicates found"))
 			lines = append(lines, "")
 			lines = append(lines, dimStyle.Render("Press any key to close"))
+		} else if len(m.dedupResults) == 0 {
+			lines = append(lines, dimStyle.Render(fmt.Sprintf("No duplicates within a single folder (%d across folders)", len(m.dedupAll))))
+			lines = append(lines, "")
+			lines = append(lines, dimStyle.Render("s: show all | any other key: close"))
+		} else if m.dedupDetail {
+			lines = append(lines, m.renderDuplicateGroup(maxHeight-2)...)
+		} else {
+			if m.dedupSameFolder {
+				lines = append(lines, normalItemStyle.Render(fmt.Sprintf("Found %d duplicate groups within one folder (of %d):", len(m.dedupResults), len(m.dedupAll))))
+			} else {
+				lines = append(lines, normalItemStyle.Render(fmt.Sprintf("Found %d duplicate groups:", len(m.dedupResults))))
+			}
+
+			// Reserve rows for the header, both section labels and the help line.
+			start, end := scrollWindow(m.dedupSelected, len(m.dedupResults), maxHeight-9)
+			for i := start; i < end; i++ {
+				if i == 0 && m.dedupExactCount > 0 {
+					lines = append(lines, "")
+					lines = append(lines, dimStyle.Render(fmt.Sprintf("Exact URL matches (%d)", m.dedupExactCount)))
+				}
+				if i == m.dedupExactCount {
+					lines = append(lines, "")
+					lines = append(lines, dimStyle.Render(fmt.Sprintf("Probable: similar titles (%d)", len(m.dedupResults)-m.dedupExactCount)))
+				}
+
+				prefix := "  "
+				style := normalItemStyle
+				if i == m.dedupSelected {
+					prefix = "❯ "
+					style = selectedItemStyle
+				}
+				lines = append(lines, style.Render(prefix+dedupSummary(m.dedupResults[i])))
+			}
+			lines = append(lines, "")
+			filter := "s: same folder only"
+			if m.dedupSameFolder {
+				filter = "s: show all"
+			}
+			lines = append(lines, dimStyle.Render("j/k: navigate | Enter: resolve | "+filter+" | any other key: close"))
+		}
+
+		return strings.Join(lines, "\n")
+	}
+
+	if m.editMode == AuditDiffMode && m.auditDiff != nil {
+		lines = append(lines, folderStyle.Render("🔍 Changes Since Last Audit"))
+		lines = append(lines, "")
+		lines = append(lines, dimStyle.Render("Previous audit: "+m.auditDiffSince.Format("2006-01-02 15:04")))
+		lines = append(lines, "")
+
+		changes := m.auditDiffTransitions()
+		if len(changes) == 0 {
+			lines = append(lines, dimStyle.Render("No links broke or recovered"))
+		}
+
+		start, end := scrollWindow(m.auditDiffCursor, len(changes), maxHeight-9)
+		for i := start; i < end; i++ {
+			if i == 0 && len(m.auditDiff.NewlyBroken) > 0 {
+				lines = append(lines, lipgloss.NewStyle().Foreground(accentColor).Render(fmt.Sprintf("Newly broken (%d):", len(m.auditDiff.NewlyBroken))))
+			}
+			if i == len(m.auditDiff.NewlyBroken) {
+				lines = append(lines, normalItemStyle.Render(fmt.Sprintf("Recovered (%d):", len(m.auditDiff.Recovered))))
+			}
+
+			change := changes[i]
+			prefix := "  "
+			style := normalItemStyle
+			if i == m.auditDiffCursor {
+				prefix = "❯ "
+				style = selectedItemStyle
+			}
+			reason := change.After.Status.String()
+			if code := change.After.StatusCode; code != 0 {
+				reason = fmt.Sprintf("%d", code)
+			}
+			lines = append(lines, style.Render(fmt.Sprintf("%s[%s] %s", prefix, reason, change.After.Bookmark.URL)))
+		}
+		lines = append(lines, "")
+		lines = append(lines, dimStyle.Render("j/k: navigate | Enter: jump to bookmark | Esc: back | any other key: close"))
+
+		return strings.Join(lines, "\n")
+	}
+
+	if m.editMode == FolderDupsMode {
+		return m.renderFolderDups(maxHeight)
+	}
+
+	if m.editMode == StatsMode {
+		return m.renderStats(maxHeight)
+	}
+
+	if m.editMode == HistoryMode {
+		return m.renderHistory(maxHeight)
+	}
+
+	if m.editMode == ConfirmArchiveDead {
+		return m.renderConfirmArchiveDead(maxHeight)
+	}
+
+	if m.editMode == EmptyFoldersMode {
+		lines = append(lines, folderStyle.Render("📂 Empty Folders"))
+		lines = append(lines, "")
+
+		if len(m.emptyFolders) == 0 {
+			lines = append(lines, dimStyle.Render("No empty folders found"))
+			lines = append(lines, "")
+			lines = append(lines, dimStyle.Render("Press any key to close"))
+			return strings.Join(lines, "\n")
+		}
+
+		lines = append(lines, normalItemStyle.Render(fmt.Sprintf("Found %d empty folders:", len(m.emptyFolders))))
+		lines = append(lines, "")
+
+		start, end := scrollWindow(m.emptyFolderSelected, len(m.emptyFolders), maxHeight-7)
+		for i := start; i < end; i++ {
+			folder := m.emptyFolders[i]
+			prefix := "  "
+			style := normalItemStyle
+			if i == m.emptyFolderSelected {
+				prefix = "❯ "
+				style = selectedItemStyle
+			}
+
+			path := folder.Title
+			if parent := m.emptyFolderPaths[folder.ID]; parent != "" {
+				path = parent + " / " + folder.Title
+			}
+			lines = append(lines, style.Render(prefix+truncatePathLeft(path, 60)))
+		}
+		lines = append(lines, "")
+		lines = append(lines, dimStyle.Render("j/k: scroll | d: delete all | any other key: close"))
+
+		return strings.Join(lines, "\n")
+	}
+
+	if m.editMode == ConfirmDelete {
+		lines = append(lines, folderStyle.Render("🗑 Delete Bookmarks"))
+		lines = append(lines, "")
+		if m.inTrash() {
+			lines = append(lines, normalItemStyle.Render(fmt.Sprintf("Delete %d selected bookmarks for good?", len(m.selectedBookmarks))))
+		} else {
+			lines = append(lines, normalItemStyle.Render(fmt.Sprintf("Move %d selected bookmarks to the trash?", len(m.selectedBookmarks))))
+		}
+		lines = append(lines, "")
+
+		selected := m.selectedBookmarkList()
+
+		// Marks made in search results can span folders; say how many go
+		// from each, so a stray match in the wrong folder stands out.
+		if groups := countByFolder(m.root, selected); len(groups) > 1 {
+			const groupCount = 6
+			lines = append(lines, normalItemStyle.Render(fmt.Sprintf("From %d folders:", len(groups))))
+			for i, group := range groups {
+				if i == groupCount {
+					lines = append(lines, dimStyle.Render(fmt.Sprintf("  ...and %d more folders", len(groups)-groupCount)))
+					break
+				}
+				path := group.Path
+				if path == "" {
+					path = "(top level)"
+				}
+				lines = append(lines, dimStyle.Render(fmt.Sprintf("  %3d from %s", group.Count, truncatePathLeft(path, 40))))
+			}
+			lines = append(lines, "")
+		}
+
+		const previewCount = 5
+		for i, bookmark := range selected {
+			if i == previewCount {
+				lines = append(lines, dimStyle.Render(fmt.Sprintf("  ...and %d more", len(selected)-previewCount)))
+				break
+			}
+			lines = append(lines, dimStyle.Render("  • "+truncateDisplay(bookmark.DisplayTitle(), 35)))
+		}
+		lines = append(lines, "")
+		lines = append(lines, dimStyle.Render("y/Enter: delete | n/Esc: cancel"))
+
+		return strings.Join(lines, "\n")
+	}
+
+	if m.editMode == ConfirmQuit {
+		lines = append(lines, folderStyle.Render("⚠ Unsaved Changes"))
+		lines = append(lines, "")
+		lines = append(lines, normalItemStyle.Render("Your staged changes haven't been written to the browser database yet."))
+		lines = append(lines, "")
+		lines = append(lines, dimStyle.Render("c: commit and quit | d: discard and quit | Esc: keep editing"))
+
+		return strings.Join(lines, "\n")
+	}
+
+	if m.editMode == ConfirmCommit {
+		lines = append(lines, folderStyle.Render("💾 Commit Changes"))
+		lines = append(lines, "")
+		lines = append(lines, normalItemStyle.Render("This will modify your real browser database:"))
+		lines = append(lines, dimStyle.Render("  "+truncatePathLeft(m.dbPath, 60)))
+		lines = append(lines, "")
+		lines = append(lines, normalItemStyle.Render("A copy of it as it is now will be saved to:"))
+		lines = append(lines, dimStyle.Render("  "+truncatePathLeft(m.stagingDB.BackupPath(), 60)))
+		lines = append(lines, dimStyle.Render("  (replaced by each later commit)"))
+		lines = append(lines, "")
+		if trash := m.trashFolder(); trash != nil && len(trash.Children) > 0 {
+			lines = append(lines, lipgloss.NewStyle().Foreground(accentColor).Render(
+				fmt.Sprintf("⚠ The trash is emptied first: %d bookmarks in it are deleted for good.", countBookmarks(trash))))
+			lines = append(lines, "")
+		}
+		if m.commitBlockedBy != "" {
+			lines = append(lines, lipgloss.NewStyle().Foreground(accentColor).Render(
+				fmt.Sprintf("⚠ %s is running. Close it first or the commit will be refused.", m.commitBlockedBy)))
 		} else {
-			lines = append(lines, normalItemStyle.Render(fmt.Sprintf("Found %d duplicate groups:", len(m.dedupGroups))))
+			lines = append(lines, dimStyle.Render("✓ No browser is running"))
+		}
+		lines = append(lines, "")
+		lines = append(lines, dimStyle.Render("This message is shown before your first commit and while the trash isn't empty."))
+		lines = append(lines, "")
+		lines = append(lines, dimStyle.Render("y: commit | n/Esc: cancel"))
+
+		return strings.Join(lines, "\n")
+	}
+
+	if m.editMode == ConfirmOpen {
+		lines = append(lines, folderStyle.Render("🌐 Open in Browser"))
+		lines = append(lines, "")
+		lines = append(lines, normalItemStyle.Render(fmt.Sprintf("This will open %d tabs:", len(m.openURLs))))
+		lines = append(lines, "")
+
+		shown := min(len(m.openURLs), max(maxHeight-9, 1))
+		for _, u := range m.openURLs[:shown] {
+			lines = append(lines, dimStyle.Render("  "+truncateDisplay(u, 60)))
+		}
+		if shown < len(m.openURLs) {
+			lines = append(lines, dimStyle.Render(fmt.Sprintf("  ... and %d more", len(m.openURLs)-shown)))
+		}
+		if m.openSkipped > 0 {
+			lines = append(lines, "")
+			lines = append(lines, dimStyle.Render(fmt.Sprintf("%d non-web links will be skipped", m.openSkipped)))
+		}
+		lines = append(lines, "")
+		lines = append(lines, dimStyle.Render("y/Enter: open | n/Esc: cancel"))
+
+		return strings.Join(lines, "\n")
+	}
+
+	if m.editMode == ConfirmEmptyTrash {
+		lines = append(lines, folderStyle.Render("🗑 Empty Trash"))
+		lines = append(lines, "")
+		if trash := m.trashFolder(); trash != nil {
+			lines = append(lines, normalItemStyle.Render(fmt.Sprintf("Delete the %d bookmarks in the trash for good?", countBookmarks(trash))))
+		}
+		lines = append(lines, "")
+		lines = append(lines, lipgloss.NewStyle().Foreground(accentColor).Render("This can't be undone once committed."))
+		lines = append(lines, "")
+		lines = append(lines, dimStyle.Render("y/Enter: empty the trash | n/Esc: cancel"))
+
+		return strings.Join(lines, "\n")
+	}
+
+	if m.editMode == ConfirmHTTPSUpgrade {
+		lines = append(lines, folderStyle.Render("🔒 Upgrade to HTTPS"))
+		lines = append(lines, "")
+		lines = append(lines, normalItemStyle.Render(fmt.Sprintf("Move %d bookmarks to the HTTPS page they redirect to:", len(m.httpsUpgrades))))
+		lines = append(lines, "")
+
+		shown := min(len(m.httpsUpgrades), max(maxHeight-10, 1))
+		for _, upgrade := range m.httpsUpgrades[:shown] {
+			lines = append(lines, dimStyle.Render("  "+truncateDisplay(upgrade.Bookmark.URL, 60)))
+		}
+		if shown < len(m.httpsUpgrades) {
+			lines = append(lines, dimStyle.Render(fmt.Sprintf("  ... and %d more", len(m.httpsUpgrades)-shown)))
+		}
+		if m.httpsUpgradeSkipped > 0 {
+			lines = append(lines, "")
+			lines = append(lines, dimStyle.Render(fmt.Sprintf("%d redirect to another host or page and will be left alone", m.httpsUpgradeSkipped)))
+		}
+		lines = append(lines, "")
+		lines = append(lines, dimStyle.Render("y/Enter: stage | n/Esc: cancel"))
+
+		return strings.Join(lines, "\n")
+	}
+
+	if m.editMode == ConfirmDeleteFolder && m.deletingFolder != nil {
+		bookmarks, folders, separators := countContents(m.deletingFolder)
+		total := bookmarks + folders + separators
+
+		lines = append(lines, folderStyle.Render("🗑 Delete Folder"))
+		lines = append(lines, "")
+		lines = append(lines, normalItemStyle.Render(fmt.Sprintf("Delete \"%s\" and everything inside it?", m.deletingFolder.DisplayTitle())))
+		lines = append(lines, "")
+		lines = append(lines, normalItemStyle.Render(fmt.Sprintf("%d items will be removed:", total+1)))
+		lines = append(lines, dimStyle.Render(fmt.Sprintf("  • %d bookmarks", bookmarks)))
+		lines = append(lines, dimStyle.Render(fmt.Sprintf("  • %d subfolders", folders)))
+		if separators > 0 {
+			lines = append(lines, dimStyle.Render(fmt.Sprintf("  • %d separators", separators)))
+		}
+		lines = append(lines, dimStyle.Render("  • the folder itself"))
+		lines = append(lines, "")
+		lines = append(lines, lipgloss.NewStyle().Foreground(accentColor).Render("This can't be undone once committed."))
+		lines = append(lines, "")
+		lines = append(lines, dimStyle.Render("Y (shift+y): delete everything | n/Esc: cancel"))
+
+		return strings.Join(lines, "\n")
+	}
+
+	if m.editMode == ExportMode {
+		lines = append(lines, folderStyle.Render("📤 Export Bookmarks"))
+		lines = append(lines, "")
+		lines = append(lines, "Choose export format:")
+		lines = append(lines, "")
+		lines = append(lines, normalItemStyle.Render("  j - Export to JSON"))
+		lines = append(lines, normalItemStyle.Render("  l - Export to JSON Lines (one bookmark per line)"))
+		lines = append(lines, normalItemStyle.Render("  h - Export to HTML (Netscape format)"))
+		lines = append(lines, normalItemStyle.Render("  v - Export to a searchable HTML page"))
+		lines = append(lines, normalItemStyle.Render("  m - Export to Markdown"))
+		lines = append(lines, normalItemStyle.Render("  o - Export to OPML (RSS readers)"))
+		lines = append(lines, "")
+		if len(m.selectedBookmarks) > 0 {
+			scope := "all bookmarks"
+			if m.exportSelectedOnly {
+				scope = fmt.Sprintf("%d selected bookmarks only", len(m.selectedBookmarks))
+			}
+			lines = append(lines, normalItemStyle.Render("  s - Toggle selected only"))
+			lines = append(lines, dimStyle.Render("  Exporting: "+scope))
+			lines = append(lines, "")
+		}
+		if len(m.auditResults) > 0 {
+			lines = append(lines, normalItemStyle.Render("  f - Filter by link status (after an audit)"))
+			lines = append(lines, dimStyle.Render("  Links: "+m.exportStatus.String()))
 			lines = append(lines, "")
+		}
+		lines = append(lines, dimStyle.Render("Esc: cancel"))
+
+		return strings.Join(lines, "\n")
+	}
+
+	if m.editMode == ExportPreviewMode {
+		return m.renderExportPreview(maxHeight)
+	}
+
+	if m.editMode == SearchMode {
+		lines = append(lines, folderStyle.Render("🔍 Search Bookmarks"))
+		lines = append(lines, "")
+		lines = append(lines, m.searchInput.View())
+		lines = append(lines, "")
+
+		if m.inSearchMode {
+			lines = append(lines, dimStyle.Render(fmt.Sprintf("Found %d results", len(m.searchResults))))
+		} else {
+			lines = append(lines, dimStyle.Render("Type to search..."))
+		}
+		lines = append(lines, "")
+		lines = append(lines, dimStyle.Render("Enter/Esc: exit search"))
+
+		return strings.Join(lines, "\n")
+	}
+
+	if m.editMode == ScratchAdd {
+		lines = append(lines, folderStyle.Render("📥 Quick Add to Scratch"))
+		lines = append(lines, "")
+		lines = append(lines, dimStyle.Render("Add links to refine later"))
+		lines = append(lines, "")
+		lines = append(lines, "URL:")
+		lines = append(lines, m.scratchInput.View())
+		lines = append(lines, "")
+		lines = append(lines, dimStyle.Render("Enter: save | Esc: cancel"))
+
+		return strings.Join(lines, "\n")
+	}
+
+	if m.editMode == FolderSwitchMode {
+		header, action := "📂 Jump to Folder", "jump"
+		if m.cloning != nil {
+			header, action = "📑 Clone "+truncateDisplay(m.cloning.DisplayTitle(), 40)+" to Folder", "clone here"
+		}
+		lines = append(lines, folderStyle.Render(header))
+		lines = append(lines, "")
+		lines = append(lines, m.folderInput.View())
+		lines = append(lines, "")
 
-			start := 0
-			end := len(m.dedupGroups)
-			if end > maxHeight-6 {
-				if m.dedupSelected > maxHeight/2 {
-					start = m.dedupSelected - maxHeight/2
-				}
-				end = start + maxHeight - 6
-				if end > len(m.dedupGroups) {
-					end = len(m.dedupGroups)
-					start = end - (maxHeight - 6)
-					if start < 0 {
-						start = 0
-					}
-				}
-			}
+		if len(m.folderMatches) == 0 {
+			lines = append(lines, dimStyle.Render("  (no matching folders)"))
+		}
 
-			for i := start; i < end; i++ {
-				prefix := "  "
-				style := normalItemStyle
-				if i == m.dedupSelected {
-					prefix = "❯ "
-					style = selectedItemStyle
-				}
-				lines = append(lines, style.Render(prefix+m.dedupGroups[i]))
+		start, end := scrollWindow(m.folderSelected, len(m.folderMatches), maxHeight-6)
+		for i := start; i < end; i++ {
+			prefix := "  "
+			style := normalItemStyle
+			if i == m.folderSelected {
+				prefix = "❯ "
+				style = selectedItemStyle
 			}
-			lines = append(lines, "")
-			lines = append(lines, dimStyle.Render("j/k: navigate | any other key: close"))
+			lines = append(lines, style.Render(prefix+truncatePathLeft(m.folderMatches[i].Path, 60)))
 		}
+		lines = append(lines, "")
+		lines = append(lines, dimStyle.Render("↑/↓: choose | Enter: "+action+" | Esc: cancel"))
 
 		return strings.Join(lines, "\n")
 	}
 
-	if m.editMode == ExportMode {
-		lines = append(lines, folderStyle.Render("📤 Export Bookmarks"))
+	if m.editMode == ReplaceMode {
+		lines = append(lines, folderStyle.Render("🔁 Find and Replace in URLs"))
 		lines = append(lines, "")
-		lines = append(lines, "Choose export format:")
+		lines = append(lines, "Find:")
+		lines = append(lines, m.findInput.View())
 		lines = append(lines, "")
-		lines = append(lines, normalItemStyle.Render("  j - Export to JSON"))
-		lines = append(lines, normalItemStyle.Render("  h - Export to HTML (Netscape format)"))
+		lines = append(lines, "Replace with:")
+		lines = append(lines, m.replaceInput.View())
 		lines = append(lines, "")
-		lines = append(lines, dimStyle.Render("Esc: cancel"))
+		mode := "plain text"
+		if m.replaceRegex {
+			mode = "regex ($1 refers to groups)"
+		}
+		lines = append(lines, dimStyle.Render("Matching: "+mode))
+		lines = append(lines, "")
+		lines = append(lines, dimStyle.Render("Tab: switch field | Ctrl+R: toggle regex | Enter: preview | Esc: cancel"))
 
 		return strings.Join(lines, "\n")
 	}
 
-	if m.editMode == SearchMode {
-		lines = append(lines, folderStyle.Render("🔍 Search Bookmarks"))
+	if m.editMode == ReplacePreviewMode {
+		lines = append(lines, folderStyle.Render("🔁 Find and Replace in URLs"))
 		lines = append(lines, "")
-		lines = append(lines, m.searchInput.View())
+
+		selected := 0
+		for _, r := range m.replacements {
+			if r.Selected {
+				selected++
+			}
+		}
+		lines = append(lines, normalItemStyle.Render(fmt.Sprintf("%d of %d matching bookmarks selected:", selected, len(m.replacements))))
 		lines = append(lines, "")
 
-		if m.inSearchMode {
-			lines = append(lines, dimStyle.Render(fmt.Sprintf("Found %d results", len(m.searchResults))))
-		} else {
-			lines = append(lines, dimStyle.Render("Type to search..."))
+		start, end := scrollWindow(m.replaceSelected, len(m.replacements), (maxHeight-7)/3)
+		for i := start; i < end; i++ {
+			r := m.replacements[i]
+			prefix := "  "
+			style := normalItemStyle
+			if i == m.replaceSelected {
+				prefix = "❯ "
+				style = selectedItemStyle
+			}
+			check := "[ ]"
+			if r.Selected {
+				check = "[x]"
+			}
+			lines = append(lines, style.Render(prefix+check+" "+truncateDisplay(r.Bookmark.DisplayTitle(), 50)))
+			lines = append(lines, dimStyle.Render("      - "+truncateDisplay(r.Bookmark.URL, 70)))
+			if r.Err != nil {
+				lines = append(lines, dimStyle.Render("      + "+truncateDisplay(r.NewURL, 50)+" (invalid: "+r.Err.Error()+")"))
+			} else {
+				lines = append(lines, dimStyle.Render("      + "+truncateDisplay(r.NewURL, 70)))
+			}
 		}
 		lines = append(lines, "")
-		lines = append(lines, dimStyle.Render("Enter/Esc: exit search"))
+		lines = append(lines, dimStyle.Render("j/k: scroll | Space: toggle | Enter: apply | Esc: back"))
 
 		return strings.Join(lines, "\n")
 	}
 
-	if m.editMode == ScratchAdd {
-		lines = append(lines, folderStyle.Render("📥 Quick Add to Scratch"))
+	if m.editMode == ImportMode {
+		lines = append(lines, folderStyle.Render("📥 Import Chrome Bookmarks"))
 		lines = append(lines, "")
-		lines = append(lines, dimStyle.Render("Add links to refine later"))
+		lines = append(lines, dimStyle.Render("Imported into a \"Chrome\" folder in the bookmarks menu"))
 		lines = append(lines, "")
-		lines = append(lines, "URL:")
-		lines = append(lines, m.scratchInput.View())
+		lines = append(lines, "Path to Chrome's Bookmarks file:")
+		lines = append(lines, m.importInput.View())
 		lines = append(lines, "")
-		lines = append(lines, dimStyle.Render("Enter: save | Esc: cancel"))
+		lines = append(lines, dimStyle.Render("Enter: import | Esc: cancel"))
 
 		return strings.Join(lines, "\n")
 	}
@@ -802,11 +2432,7 @@ func (m *Model) renderEditForm(maxHeight int) string {
 				prefix = "❯ "
 				style = selectedItemStyle
 			}
-			title := folder.Title
-			if len(title) > 35 {
-				title = title[:32] + "..."
-			}
-			lines = append(lines, style.Render(prefix+title))
+			lines = append(lines, style.Render(prefix+truncateDisplay(folder.DisplayTitle(), 35)))
 		}
 		lines = append(lines, "")
 		lines = append(lines, dimStyle.Render("j/k: navigate | Enter: move | Esc: cancel"))
@@ -814,6 +2440,20 @@ func (m *Model) renderEditForm(maxHeight int) string {
 		return strings.Join(lines, "\n")
 	}
 
+	if m.editMode == RenameFolder {
+		lines = append(lines, folderStyle.Render("✏ Rename Folder"))
+		lines = append(lines, "")
+		lines = append(lines, dimStyle.Render("ID: "+fmt.Sprintf("%d", m.renamingFolder.ID)))
+		lines = append(lines, "")
+
+		lines = append(lines, "Title:")
+		lines = append(lines, m.titleInput.View())
+		lines = append(lines, "")
+		lines = append(lines, dimStyle.Render("Enter: save | Esc: cancel"))
+
+		return strings.Join(lines, "\n")
+	}
+
 	if m.editMode == AddTitle || m.editMode == AddURL {
 		lines = append(lines, folderStyle.Render("➕ Add New Bookmark"))
 		lines = append(lines, "")
@@ -834,12 +2474,11 @@ func (m *Model) renderEditForm(maxHeight int) string {
 		return strings.Join(lines, "\n")
 	}
 
-	if m.listCursor >= len(m.bookmarks) {
+	bookmark := m.selectedBookmark()
+	if bookmark == nil {
 		return "No bookmark selected"
 	}
 
-	bookmark := m.bookmarks[m.listCursor]
-
 	lines = append(lines, folderStyle.Render("✏ Edit Bookmark"))
 	lines = append(lines, "")
 	lines = append(lines, dimStyle.Render("ID: "+fmt.Sprintf("%d", bookmark.ID)))
@@ -859,7 +2498,11 @@ func (m *Model) renderEditForm(maxHeight int) string {
 
 func (m *Model) renderTree(maxHeight int) string {
 	var lines []string
-	lines = append(lines, folderStyle.Render("📁 Folder Tree"))
+	header := folderStyle.Render("📁 Folder Tree")
+	if m.sortFolders {
+		header += dimStyle.Render(" (A-Z)")
+	}
+	lines = append(lines, header)
 	lines = append(lines, "")
 
 	for i, node := range m.treeNodes {
@@ -892,14 +2535,42 @@ func (m *Model) renderTree(maxHeight int) string {
 				PaddingLeft(1)
 		}
 
-		title := node.Folder.Title
-		maxLen := 35 - (node.Depth * 2)
-		if len(title) > maxLen {
-			title = title[:maxLen-3] + "..."
+		direct := countBookmarks(node.Folder)
+		badge := fmt.Sprintf("(%d)", direct)
+		if isTagFolder(m.root, node.Folder) {
+			badge = fmt.Sprintf("(%d)", len(m.tagIndex[node.Folder.Title]))
+		} else if node.Folder.GUID == "tags________" {
+			badge = fmt.Sprintf("(%d tags)", len(node.Folder.Children))
+		} else if node.HasKids && !node.Expanded {
+			if total := m.folderTotals[node.Folder.ID]; total != direct {
+				badge = fmt.Sprintf("(%d, %d total)", direct, total)
+			}
+		}
+		// Dead link counts from the last audit; a running audit hides them.
+		if dead := m.deadLinkCounts[node.Folder.ID]; dead > 0 {
+			badge += fmt.Sprintf(" (%d dead)", dead)
+		}
+
+		// Folders holding exact duplicates from the last dedup scan.
+		marker := ""
+		if m.dedupFolders[node.Folder.ID] {
+			marker = " ⧉"
 		}
 
+		title := node.Folder.DisplayTitle()
+		if node.Folder.GUID == "tags________" {
+			title = "🏷 Tags"
+		}
+		// Leave room for pane and item padding, the cursor, the expand
+		// indicator and the badge after the title.
+		maxLen := m.paneWidth - 6 - (node.Depth * 2) - (len(badge) + 1) - lipgloss.Width(marker)
+		if maxLen < 4 {
+			maxLen = 4
+		}
+		title = truncateDisplay(title, maxLen)
+
 		line := prefix + indent + indicator + title
-		lines = append(lines, titleStyle.Render(line))
+		lines = append(lines, titleStyle.Render(line)+" "+dimStyle.Render(badge)+lipgloss.NewStyle().Foreground(accentColor).Render(marker))
 	}
 
 	if len(m.treeNodes) == 0 {
@@ -908,55 +2579,101 @@ func (m *Model) renderTree(maxHeight int) string {
 
 	// Scroll window to keep cursor visible
 	if len(lines) > maxHeight {
-		start := 0
-		if m.treeCursor > maxHeight/2 {
-			start = m.treeCursor - maxHeight/2
-		}
-		end := start + maxHeight
-		if end > len(lines) {
-			end = len(lines)
-			start = end - maxHeight
-			if start < 0 {
-				start = 0
-			}
-		}
-		lines = lines[start:end]
+		start := treeScrollStart(m.treeCursor, len(lines), maxHeight)
+		lines = lines[start : start+maxHeight]
 	}
 
 	return strings.Join(lines, "\n")
 }
 
-func (m *Model) renderList(maxHeight int) string {
+// treeScrollStart is the first rendered tree line shown when the tree,
+// header included, is taller than the pane.
+func treeScrollStart(cursor, totalLines, maxHeight int) int {
+	if totalLines <= maxHeight {
+		return 0
+	}
+	start := 0
+	if cursor > maxHeight/2 {
+		start = cursor - maxHeight/2
+	}
+	if start+maxHeight > totalLines {
+		start = max(totalLines-maxHeight, 0)
+	}
+	return start
+}
+
+func (m *Model) renderList(maxWidth, maxHeight int) string {
 	var lines []string
 
 	var displayBookmarks []*models.Bookmark
-	var headerTitle string
+	var headerTitle, headerFlags string
 
 	if m.inSearchMode && len(m.searchResults) > 0 {
-		displayBookmarks = m.searchResults
+		displayBookmarks = m.listBookmarks()
 		headerTitle = fmt.Sprintf("🔍 Search Results (%d)", len(m.searchResults))
 	} else if m.currentFolder != nil {
-		displayBookmarks = m.bookmarks
-		headerTitle = "📄 " + m.currentFolder.Title
+		displayBookmarks = m.listBookmarks()
 		if m.hasPendingChanges {
-			headerTitle += " [modified]"
+			headerFlags += " [modified]"
 		}
 	} else {
-		displayBookmarks = m.bookmarks
+		displayBookmarks = m.listBookmarks()
 		headerTitle = "📄 Bookmarks"
 	}
+	if m.deadOnly && !m.inSearchMode {
+		headerFlags += " [dead only]"
+	}
 
+	if headerTitle == "" {
+		// The breadcrumb gets whatever the pane has left after the icon and
+		// flags, losing its top-level folders first.
+		available := max(maxWidth-2-3-lipgloss.Width(headerFlags), 1)
+		if isTagFolder(m.root, m.currentFolder) {
+			headerTitle = "🏷 " + truncateDisplay(m.currentFolder.Title, available)
+		} else {
+			headerTitle = "📄 " + truncatePathLeft(folderBreadcrumb(m.root, m.currentFolder), available)
+		}
+	}
+	headerTitle += headerFlags
+
+	// In column mode the line under the title carries the column headings,
+	// so rows stay where mouse handling expects them.
+	// Leave room for pane padding, item padding, the selection prefix and
+	// the gaps between columns.
+	titleWidth := max(maxWidth-2-1-3-visitsColumnWidth-dateColumnWidth-2, 8)
+	// Without columns titles get the whole row, except in search results,
+	// where some is kept back for the folder path.
+	rowWidth := max(maxWidth-2-1-3, 8)
+	if m.inSearchMode {
+		rowWidth = max(rowWidth*3/5, 8)
+	}
 	lines = append(lines, folderStyle.Render(headerTitle))
-	lines = append(lines, "")
+	if m.showColumns {
+		lines = append(lines, dimStyle.PaddingLeft(1).Render(m.listColumns("   ", "Title", "Visits", "Added", titleWidth)))
+	} else {
+		lines = append(lines, "")
+	}
 
 	if len(displayBookmarks) == 0 {
 		if m.inSearchMode {
 			lines = append(lines, dimStyle.Render("  (no results)"))
+		} else if m.deadOnly {
+			lines = append(lines, dimStyle.Render("  (no dead links)"))
 		} else {
 			lines = append(lines, dimStyle.Render("  (no bookmarks)"))
 		}
 	} else {
-		for i, bookmark := range displayBookmarks {
+		// Only format the rows that fit below the header; the cursor indexes
+		// bookmarks, not rendered lines.
+		start, end := scrollWindow(m.listCursor, len(displayBookmarks), maxHeight-len(lines))
+		for i := start; i < end; i++ {
+			bookmark := displayBookmarks[i]
+			if bookmark.IsSeparator() {
+				rule := strings.Repeat("─", max(maxWidth-6, 3))
+				lines = append(lines, dimStyle.Render("   "+rule))
+				continue
+			}
+
 			selectMark := " "
 			if m.selectedBookmarks[bookmark.ID] {
 				selectMark = "✓"
@@ -972,38 +2689,68 @@ func (m *Model) renderList(maxHeight int) string {
 				style = selectedItemStyle
 			}
 
-			title := bookmark.Title
-			if title == "" {
-				title = "(untitled)"
-			}
-			if len(title) > 38 {
-				title = title[:35] + "..."
+			if m.showColumns {
+				row := m.listColumns(prefix, truncateDisplay(bookmark.DisplayTitle(), titleWidth),
+					fmt.Sprintf("%d", bookmark.VisitCount), bookmark.DateAdded.Format("2006-01-02"), titleWidth)
+				lines = append(lines, style.Render(row))
+				continue
 			}
 
-			lines = append(lines, style.Render(prefix+title))
-		}
-	}
+			title := truncateDisplay(bookmark.DisplayTitle(), rowWidth)
 
-	// Scroll window
-	if len(lines) > maxHeight {
-		start := 0
-		if m.listCursor > maxHeight/2 {
-			start = m.listCursor - maxHeight/2
-		}
-		end := start + maxHeight
-		if end > len(lines) {
-			end = len(lines)
-			start = end - maxHeight
-			if start < 0 {
-				start = 0
+			line := style.Render(prefix + title)
+			if m.inSearchMode && i < len(m.searchResults) && m.searchResults[i].FolderPath != "" {
+				// Leave room for the selection prefix, item padding and separator.
+				pathWidth := maxWidth - lipgloss.Width(prefix+title) - 3
+				if path := truncatePathLeft(m.searchResults[i].FolderPath, pathWidth); path != "" {
+					line += " " + dimStyle.Render(path)
+				}
 			}
+			lines = append(lines, line)
 		}
-		lines = lines[start:end]
 	}
 
 	return strings.Join(lines, "\n")
 }
 
+// scrollWindow returns the [start, end) range of items to show in a pane of
+// the given height, keeping the cursor roughly centered.
+func scrollWindow(cursor, total, height int) (int, int) {
+	if height < 1 {
+		height = 1
+	}
+	if total <= height {
+		return 0, total
+	}
+
+	start := 0
+	if cursor > height/2 {
+		start = cursor - height/2
+	}
+	if start+height > total {
+		start = total - height
+	}
+	return start, start + height
+}
+
+const (
+	visitsColumnWidth = 6
+	dateColumnWidth   = 10
+)
+
+// listColumns lays out one row of the list's column mode: the title padded
+// to titleWidth, then the visit count right-aligned, then the date.
+func (m *Model) listColumns(prefix, title, visits, added string, titleWidth int) string {
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		prefix,
+		lipgloss.NewStyle().Width(titleWidth).Render(title),
+		" ",
+		lipgloss.NewStyle().Width(visitsColumnWidth).Align(lipgloss.Right).Render(visits),
+		" ",
+		lipgloss.NewStyle().Width(dateColumnWidth).Render(added),
+	)
+}
+
 func (m *Model) stylePane(pane Pane, content string, width, height int) string {
 	style := paneStyle
 	if pane == m.activePane {
@@ -1012,6 +2759,113 @@ func (m *Model) stylePane(pane Pane, content string, width, height int) string {
 	return style.Width(width).Height(height).Render(content)
 }
 
+// Layout rows above the first line of pane content: the title bar and the
+// pane's top border. Each pane also has a two-line header.
+const (
+	paneContentTop   = 2
+	paneHeaderHeight = 2
+)
+
+// The smallest pane content size worth drawing. Below this, panes are
+// dropped from the layout rather than squeezed.
+const (
+	minPaneWidth  = 20
+	minPaneHeight = 5
+)
+
+// computeLayout sizes the panes for the current terminal, hiding the
+// inspector and then the tree or list when there isn't room for them.
+func (m *Model) computeLayout() {
+	m.numPanes = 2
+	if m.showInspector {
+		m.numPanes = 3
+	}
+	for m.numPanes > 1 && m.width/m.numPanes-4 < minPaneWidth {
+		m.numPanes--
+	}
+
+	m.paneWidth = max(m.width/m.numPanes-4, minPaneWidth)
+	m.paneHeight = max(m.height-8, minPaneHeight)
+}
+
+// visiblePanes lists the panes View draws, left to right. With room for
+// only one, it's the focused pane, or the list while a form is open.
+func (m *Model) visiblePanes() []Pane {
+	switch {
+	case m.numPanes >= 3:
+		return []Pane{TreePane, ListPane, InspectorPane}
+	case m.numPanes == 2:
+		return []Pane{TreePane, ListPane}
+	case m.editMode != EditNone:
+		return []Pane{ListPane}
+	default:
+		return []Pane{m.activePane}
+	}
+}
+
+// handleMouse maps clicks and wheel events onto the pane layout drawn by
+// View. Clicking a row focuses its pane and moves the cursor there; clicking
+// the highlighted folder again opens it like Enter.
+func (m *Model) handleMouse(msg tea.MouseMsg) {
+	if m.editMode != EditNone || !m.ready {
+		return
+	}
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		m.cursorUp()
+		return
+	case tea.MouseButtonWheelDown:
+		m.cursorDown()
+		return
+	case tea.MouseButtonLeft:
+		if msg.Action != tea.MouseActionPress {
+			return
+		}
+	default:
+		return
+	}
+
+	paneHeight := m.paneHeight
+	outerWidth := m.paneWidth + 2 // border
+
+	row := msg.Y - paneContentTop
+	if row < 0 || row >= paneHeight {
+		return
+	}
+
+	panes := m.visiblePanes()
+	column := msg.X / outerWidth
+	if column >= len(panes) {
+		return
+	}
+
+	switch panes[column] {
+	case TreePane:
+		totalLines := len(m.treeNodes) + paneHeaderHeight
+		index := treeScrollStart(m.treeCursor, totalLines, paneHeight) + row - paneHeaderHeight
+		if index < 0 || index >= len(m.treeNodes) {
+			return
+		}
+		if m.activePane == TreePane && index == m.treeCursor {
+			m.toggleOrSelectFolder()
+			return
+		}
+		m.activePane = TreePane
+		m.treeCursor = index
+
+	case ListPane:
+		bookmarks := m.listBookmarks()
+		start, _ := scrollWindow(m.listCursor, len(bookmarks), paneHeight-paneHeaderHeight)
+		index := start + row - paneHeaderHeight
+		if row < paneHeaderHeight || index >= len(bookmarks) || bookmarks[index].IsSeparator() {
+			return
+		}
+		m.activePane = ListPane
+		m.listCursor = index
+	}
+}
+
 func (m *Model) togglePane() {
 	if m.activePane == TreePane {
 		m.activePane = ListPane
@@ -1026,12 +2880,12 @@ func (m *Model) cursorDown() {
 			m.treeCursor++
 		}
 	} else {
-		maxItems := len(m.bookmarks)
-		if m.inSearchMode {
-			maxItems = len(m.searchResults)
-		}
-		if m.listCursor < maxItems-1 {
-			m.listCursor++
+		bookmarks := m.listBookmarks()
+		for i := m.listCursor + 1; i < len(bookmarks); i++ {
+			if !bookmarks[i].IsSeparator() {
+				m.listCursor = i
+				break
+			}
 		}
 	}
 }
@@ -1042,8 +2896,55 @@ func (m *Model) cursorUp() {
 			m.treeCursor--
 		}
 	} else {
-		if m.listCursor > 0 {
-			m.listCursor--
+		bookmarks := m.listBookmarks()
+		for i := m.listCursor - 1; i >= 0 && i < len(bookmarks); i-- {
+			if !bookmarks[i].IsSeparator() {
+				m.listCursor = i
+				break
+			}
+		}
+	}
+}
+
+// cursorCount is how many rows the active pane's cursor moves over.
+func (m *Model) cursorCount() int {
+	if m.activePane == TreePane {
+		return len(m.treeNodes)
+	}
+	return len(m.listBookmarks())
+}
+
+// pageRows is how many rows fit in a pane below its header.
+func (m *Model) pageRows() int {
+	return max(m.paneHeight-paneHeaderHeight, 1)
+}
+
+// cursorBy moves the active pane's cursor delta rows, stopping at either
+// end. In the list a separator can't hold the cursor, so it carries on to
+// the next bookmark, or comes back if there's none that way.
+func (m *Model) cursorBy(delta int) {
+	if m.activePane == TreePane {
+		if len(m.treeNodes) > 0 {
+			m.treeCursor = min(max(m.treeCursor+delta, 0), len(m.treeNodes)-1)
+		}
+		return
+	}
+
+	bookmarks := m.listBookmarks()
+	if len(bookmarks) == 0 {
+		return
+	}
+	target := min(max(m.listCursor+delta, 0), len(bookmarks)-1)
+	step := 1
+	if delta < 0 {
+		step = -1
+	}
+	for _, dir := range []int{step, -step} {
+		for i := target; i >= 0 && i < len(bookmarks); i += dir {
+			if !bookmarks[i].IsSeparator() {
+				m.listCursor = i
+				return
+			}
 		}
 	}
 }
@@ -1059,7 +2960,7 @@ func (m *Model) toggleOrSelectFolder() {
 		node.Expanded = !node.Expanded
 		m.expandedFolders[node.Folder.ID] = node.Expanded
 
-		m.treeNodes = BuildFlatTree(m.root, m.expandedFolders)
+		m.treeNodes = BuildFlatTree(m.root, m.expandedFolders, m.sortFolders)
 
 		newIdx := FindNodeIndex(m.treeNodes, node.Folder.ID)
 		if newIdx >= 0 {
@@ -1068,41 +2969,180 @@ func (m *Model) toggleOrSelectFolder() {
 	}
 
 	m.currentFolder = node.Folder
-	m.bookmarks = getBookmarksForFolder(m.currentFolder)
+	m.bookmarks = m.folderContents(m.currentFolder)
+	m.listCursor = 0
+	m.inSearchMode = false
+	m.searchResults = nil
+}
+
+func (m *Model) collapseAll() {
+	var selected *models.Bookmark
+	if m.treeCursor < len(m.treeNodes) {
+		selected = m.treeNodes[m.treeCursor].Folder
+	}
+
+	m.expandedFolders = make(map[int64]bool)
+	m.treeNodes = BuildFlatTree(m.root, m.expandedFolders, m.sortFolders)
+	m.treeCursor = 0
+
+	if selected != nil {
+		if idx := FindNearestVisibleIndex(m.treeNodes, m.root, selected); idx >= 0 {
+			m.treeCursor = idx
+		}
+	}
+	m.statusMessage = "Collapsed all folders"
+}
+
+func (m *Model) expandAll() {
+	var selected *models.Bookmark
+	if m.treeCursor < len(m.treeNodes) {
+		selected = m.treeNodes[m.treeCursor].Folder
+	}
+
+	for _, folder := range db.GetFolders(m.root) {
+		if hasSubfolders(folder) {
+			m.expandedFolders[folder.ID] = true
+		}
+	}
+	m.treeNodes = BuildFlatTree(m.root, m.expandedFolders, m.sortFolders)
+
+	if selected != nil {
+		if idx := FindNodeIndex(m.treeNodes, selected.ID); idx >= 0 {
+			m.treeCursor = idx
+		}
+	}
+	m.statusMessage = "Expanded all folders"
+}
+
+// listBookmarks returns the bookmarks currently shown in the list pane:
+// search results while a search is active, otherwise the current folder
+// (narrowed to dead links when that filter is on).
+func (m *Model) listBookmarks() []*models.Bookmark {
+	if !m.inSearchMode {
+		if !m.deadOnly {
+			return m.bookmarks
+		}
+
+		var dead []*models.Bookmark
+		for _, bookmark := range m.bookmarks {
+			if m.auditResults[bookmark.ID] == "DEAD" {
+				dead = append(dead, bookmark)
+			}
+		}
+		return dead
+	}
+
+	bookmarks := make([]*models.Bookmark, 0, len(m.searchResults))
+	for _, result := range m.searchResults {
+		bookmarks = append(bookmarks, result.Bookmark)
+	}
+	return bookmarks
+}
+
+func (m *Model) toggleDeadOnly() {
+	if !m.deadOnly && len(m.auditResults) == 0 {
+		m.statusMessage = "No audit results yet (press a to audit links)"
+		return
+	}
+
+	m.deadOnly = !m.deadOnly
 	m.listCursor = 0
+	if m.deadOnly {
+		m.statusMessage = "Showing dead links only (m: mark, d: delete)"
+	} else {
+		m.statusMessage = "Showing all bookmarks"
+	}
+}
+
+func (m *Model) selectedBookmark() *models.Bookmark {
+	bookmarks := m.listBookmarks()
+	if m.listCursor >= len(bookmarks) || bookmarks[m.listCursor].IsSeparator() {
+		return nil
+	}
+	return bookmarks[m.listCursor]
 }
 
 func (m *Model) enterEditMode() {
-	if m.listCursor >= len(m.bookmarks) {
+	bookmark := m.selectedBookmark()
+	if bookmark == nil {
 		return
 	}
 
 	if m.stagingDB == nil {
 		var err error
-		m.stagingDB, err = staging.CreateStaging(m.dbPath)
+		m.stagingDB, err = m.createStaging()
 		if err != nil {
 			m.statusMessage = "Failed to create staging database: " + err.Error()
 			return
 		}
 	}
 
-	bookmark := m.bookmarks[m.listCursor]
 	m.titleInput.SetValue(bookmark.Title)
 	m.urlInput.SetValue(bookmark.URL)
 
-	m.editMode = EditTitle
-	m.titleInput.Focus()
-	m.statusMessage = "Editing bookmark (changes staged until Ctrl+S)"
+	m.editMode = EditTitle
+	m.titleInput.Focus()
+	m.statusMessage = "Editing bookmark (changes staged until Ctrl+S)"
+}
+
+func (m *Model) enterRenameFolder(folder *models.Bookmark) {
+	if isBuiltinFolder(folder) {
+		m.statusMessage = "⚠ Built-in folders can't be renamed"
+		return
+	}
+
+	if m.stagingDB == nil {
+		var err error
+		m.stagingDB, err = m.createStaging()
+		if err != nil {
+			m.statusMessage = "Failed to create staging database: " + err.Error()
+			return
+		}
+	}
+
+	m.renamingFolder = folder
+	m.titleInput.SetValue(folder.Title)
+	m.editMode = RenameFolder
+	m.titleInput.Focus()
+	m.statusMessage = "Renaming folder (changes staged until Ctrl+S)"
+}
+
+func (m *Model) saveFolderTitle() {
+	folder := m.renamingFolder
+	newTitle := strings.TrimSpace(m.titleInput.Value())
+
+	m.editMode = EditNone
+	m.titleInput.Blur()
+	m.renamingFolder = nil
+
+	if newTitle == "" {
+		m.statusMessage = "⚠ Folder title can't be empty"
+		return
+	}
+	if newTitle == folder.Title {
+		m.statusMessage = ""
+		return
+	}
+
+	if err := m.stagingDB.UpdateBookmarkTitle(folder.ID, newTitle); err != nil {
+		m.statusMessage = "Failed to rename folder: " + err.Error()
+		return
+	}
+
+	folder.Title = newTitle
+	m.treeNodes = BuildFlatTree(m.root, m.expandedFolders, m.sortFolders)
+	m.markChanged()
+	m.statusMessage = "✓ Renamed folder to " + newTitle + " (Ctrl+S to commit)"
 }
 
 func (m *Model) enterAddMode() {
-	if m.currentFolder == nil {
+	if m.currentFolder == nil || m.inTagView() || m.inOrphanedView() {
 		return
 	}
 
 	if m.stagingDB == nil {
 		var err error
-		m.stagingDB, err = staging.CreateStaging(m.dbPath)
+		m.stagingDB, err = m.createStaging()
 		if err != nil {
 			m.statusMessage = "Failed to create staging database: " + err.Error()
 			return
@@ -1129,7 +3169,7 @@ func (m *Model) enterSearchMode() {
 func (m *Model) enterScratchMode() {
 	if m.stagingDB == nil {
 		var err error
-		m.stagingDB, err = staging.CreateStaging(m.dbPath)
+		m.stagingDB, err = m.createStaging()
 		if err != nil {
 			m.statusMessage = "Failed to create staging database: " + err.Error()
 			return
@@ -1142,6 +3182,220 @@ func (m *Model) enterScratchMode() {
 	m.statusMessage = "Quick add to Scratch folder"
 }
 
+func (m *Model) enterFolderSwitch() {
+	m.folderInput.Placeholder = "Jump to folder..."
+	m.folderInput.SetValue("")
+	m.folderInput.Focus()
+	m.folderMatches = SearchFolders(m.root, "")
+	m.folderSelected = 0
+	m.editMode = FolderSwitchMode
+	m.statusMessage = ""
+}
+
+func (m *Model) jumpToFolder(folder *models.Bookmark) {
+	ExpandPath(m.root, folder, m.expandedFolders)
+	m.treeNodes = BuildFlatTree(m.root, m.expandedFolders, m.sortFolders)
+	if idx := FindNodeIndex(m.treeNodes, folder.ID); idx >= 0 {
+		m.treeCursor = idx
+	}
+
+	m.currentFolder = folder
+	m.bookmarks = m.folderContents(m.currentFolder)
+	m.listCursor = 0
+	m.inSearchMode = false
+	m.searchResults = nil
+	m.activePane = TreePane
+	m.statusMessage = "Jumped to " + folder.Title
+}
+
+// maxFolderHistory is how many folders back (and forward) are remembered.
+const maxFolderHistory = 20
+
+func (m *Model) pushFolderHistory(folder *models.Bookmark) {
+	m.folderBack = append(m.folderBack, folder)
+	if len(m.folderBack) > maxFolderHistory {
+		m.folderBack = m.folderBack[len(m.folderBack)-maxFolderHistory:]
+	}
+	m.folderForward = nil
+}
+
+// folderHistoryBack returns to the previously viewed folder, like a
+// browser's back button. Folders deleted since are skipped.
+func (m *Model) folderHistoryBack() {
+	folder := m.popLiveFolder(&m.folderBack)
+	if folder == nil {
+		m.statusMessage = "No earlier folder"
+		return
+	}
+	if m.currentFolder != nil {
+		m.folderForward = append(m.folderForward, m.currentFolder)
+	}
+	m.navigatingHistory = true
+	m.jumpToFolder(folder)
+	m.statusMessage = "◀ Back to " + folder.DisplayTitle()
+}
+
+func (m *Model) folderHistoryForward() {
+	folder := m.popLiveFolder(&m.folderForward)
+	if folder == nil {
+		m.statusMessage = "No later folder"
+		return
+	}
+	if m.currentFolder != nil {
+		m.folderBack = append(m.folderBack, m.currentFolder)
+	}
+	m.navigatingHistory = true
+	m.jumpToFolder(folder)
+	m.statusMessage = "▶ Forward to " + folder.DisplayTitle()
+}
+
+// popLiveFolder pops folders off stack until it finds one still in the tree.
+func (m *Model) popLiveFolder(stack *[]*models.Bookmark) *models.Bookmark {
+	for len(*stack) > 0 {
+		folder := (*stack)[len(*stack)-1]
+		*stack = (*stack)[:len(*stack)-1]
+		if folder != m.currentFolder && findBookmarkByID(m.root, folder.ID) != nil {
+			return folder
+		}
+	}
+	return nil
+}
+
+func (m *Model) enterImportMode() {
+	if m.stagingDB == nil {
+		var err error
+		m.stagingDB, err = m.createStaging()
+		if err != nil {
+			m.statusMessage = "Failed to create staging database: " + err.Error()
+			return
+		}
+	}
+
+	m.importInput.SetValue("")
+	m.importInput.Focus()
+	m.editMode = ImportMode
+	m.statusMessage = "Import bookmarks from Chrome"
+}
+
+func (m *Model) enterReplaceMode() {
+	if m.stagingDB == nil {
+		var err error
+		m.stagingDB, err = m.createStaging()
+		if err != nil {
+			m.statusMessage = "Failed to create staging database: " + err.Error()
+			return
+		}
+	}
+
+	m.replaceInput.Blur()
+	m.findInput.Focus()
+	m.editMode = ReplaceMode
+	m.statusMessage = "Find and replace in bookmark URLs"
+}
+
+func (m *Model) previewReplacements() {
+	replacements, err := FindURLReplacements(m.root, m.findInput.Value(), m.replaceInput.Value(), m.replaceRegex)
+	if err != nil {
+		m.statusMessage = "❌ " + err.Error()
+		return
+	}
+	if len(replacements) == 0 {
+		m.statusMessage = "No bookmark URLs match"
+		return
+	}
+
+	m.replacements = replacements
+	m.replaceSelected = 0
+	m.editMode = ReplacePreviewMode
+	m.statusMessage = fmt.Sprintf("%d bookmark URLs would change", len(replacements))
+}
+
+// applyReplacements stages the selected URL changes. Each bookmark is moved
+// to a place for its new URL, as when editing a single URL.
+func (m *Model) applyReplacements() {
+	var updated, failed int
+	for _, r := range m.replacements {
+		if !r.Selected {
+			continue
+		}
+		if err := m.stagingDB.RepointBookmarkURL(r.Bookmark.ID, r.NewURL); err != nil {
+			failed++
+			if debugLog != nil {
+				debugLog.Printf("applyReplacements: bookmark %d: %v", r.Bookmark.ID, err)
+			}
+			continue
+		}
+		if placeID, err := m.stagingDB.BookmarkPlaceID(r.Bookmark.ID); err == nil {
+			r.Bookmark.FK = &placeID
+		}
+		r.Bookmark.URL = r.NewURL
+		r.Bookmark.LastModified = time.Now()
+		updated++
+	}
+
+	m.replacements = nil
+	m.editMode = EditNone
+	m.findInput.Blur()
+	m.replaceInput.Blur()
+	if updated > 0 {
+		m.markChanged()
+	}
+
+	if failed > 0 {
+		m.statusMessage = fmt.Sprintf("⚠ Updated %d URLs, failed %d (Ctrl+S to commit)", updated, failed)
+	} else {
+		m.statusMessage = fmt.Sprintf("✓ Updated %d URLs (Ctrl+S to commit)", updated)
+	}
+}
+
+func (m *Model) importChrome() {
+	path := strings.TrimSpace(m.importInput.Value())
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+
+	m.editMode = EditNone
+	m.importInput.Blur()
+
+	imported, err := importer.ImportChromeJSON(path)
+	if err != nil {
+		m.statusMessage = "❌ Import failed: " + err.Error()
+		return
+	}
+
+	parent := findFolderByGUID(m.root, "menu________")
+	if parent == nil {
+		parent = m.root
+	}
+
+	if err := m.stagingDB.ImportTree(parent.ID, imported); err != nil {
+		m.statusMessage = "❌ Import failed: " + err.Error()
+		return
+	}
+
+	parent.Children = append(parent.Children, imported)
+	m.treeNodes = BuildFlatTree(m.root, m.expandedFolders, m.sortFolders)
+	m.markChanged()
+	m.statusMessage = fmt.Sprintf("✓ Imported %d bookmarks into %s / Chrome (Ctrl+S to commit)",
+		countBookmarksRecursive(imported), parent.Title)
+}
+
+func (m *Model) showSearchResults() {
+	m.editMode = EditNone
+	m.searchInput.Blur()
+
+	if !m.inSearchMode || len(m.searchResults) == 0 {
+		m.exitSearchMode()
+		m.statusMessage = "No results"
+		return
+	}
+
+	m.activePane = ListPane
+	m.statusMessage = fmt.Sprintf("%d results (Esc: clear search)", len(m.searchResults))
+}
+
 func (m *Model) exitSearchMode() {
 	m.editMode = EditNone
 	m.searchInput.Blur()
@@ -1151,11 +3405,11 @@ func (m *Model) exitSearchMode() {
 }
 
 func (m *Model) saveTitle() *Model {
-	if m.listCursor >= len(m.bookmarks) {
+	bookmark := m.selectedBookmark()
+	if bookmark == nil {
 		return m
 	}
 
-	bookmark := m.bookmarks[m.listCursor]
 	newTitle := m.titleInput.Value()
 
 	if newTitle != bookmark.Title {
@@ -1166,7 +3420,7 @@ func (m *Model) saveTitle() *Model {
 			return m
 		}
 		bookmark.Title = newTitle
-		m.hasPendingChanges = true
+		m.markChanged()
 	}
 
 	m.editMode = EditURL
@@ -1177,22 +3431,32 @@ func (m *Model) saveTitle() *Model {
 }
 
 func (m *Model) saveURL() *Model {
-	if m.listCursor >= len(m.bookmarks) {
+	bookmark := m.selectedBookmark()
+	if bookmark == nil {
 		return m
 	}
 
-	bookmark := m.bookmarks[m.listCursor]
 	newURL := m.urlInput.Value()
 
+	if newURL != bookmark.URL && !m.checkURLInput(&m.urlInput) {
+		return m
+	}
+
 	if newURL != bookmark.URL && bookmark.FK != nil {
-		err := m.stagingDB.UpdateBookmarkURL(*bookmark.FK, newURL)
+		// Other bookmarks may share this place, so move only this one to
+		// a place for the new URL rather than rewriting the shared row.
+		err := m.stagingDB.RepointBookmarkURL(bookmark.ID, newURL)
 		if err != nil {
 			m.statusMessage = "Failed to update URL: " + err.Error()
 			m.editMode = EditNone
 			return m
 		}
+		if placeID, err := m.stagingDB.BookmarkPlaceID(bookmark.ID); err == nil {
+			bookmark.FK = &placeID
+		}
 		bookmark.URL = newURL
-		m.hasPendingChanges = true
+		bookmark.LastModified = time.Now()
+		m.markChanged()
 	}
 
 	m.editMode = EditNone
@@ -1203,71 +3467,353 @@ func (m *Model) saveURL() *Model {
 	return m
 }
 
+// checkURLInput validates the URL typed into input, leaving the user in the
+// current edit mode when it is rejected. A bare host is rewritten to the
+// suggested https:// URL so a second Enter accepts it.
+func (m *Model) checkURLInput(input *textinput.Model) bool {
+	suggestion, err := validateURL(input.Value())
+	if err == nil {
+		return true
+	}
+
+	if suggestion != "" {
+		input.SetValue(suggestion)
+		input.CursorEnd()
+		m.statusMessage = "⚠ " + err.Error() + ": press Enter to save as " + suggestion
+		return false
+	}
+
+	m.statusMessage = "⚠ " + err.Error()
+	return false
+}
+
+// shouldConfirmCommit reports whether a commit is explained before it
+// happens: the first one, and any while the trash holds bookmarks, since
+// committing empties it.
+func (m *Model) shouldConfirmCommit() bool {
+	trash := m.trashFolder()
+	return !m.config.CommitConfirmed || (trash != nil && len(trash.Children) > 0)
+}
+
+func (m *Model) enterConfirmCommit() {
+	if m.stagingDB == nil {
+		m.statusMessage = "No changes to commit"
+		return
+	}
+	m.commitBlockedBy = ""
+	if running, process := db.IsBrowserRunning(); running {
+		m.commitBlockedBy = process
+	}
+	m.editMode = ConfirmCommit
+	m.statusMessage = "Confirm the commit"
+}
+
 func (m *Model) commitChanges() *Model {
 	if m.stagingDB == nil {
 		m.statusMessage = "No changes to commit"
 		return m
 	}
 
+	if running, process := db.IsBrowserRunning(); running {
+		m.statusMessage = fmt.Sprintf("⚠ Close %s before committing; changes are still staged", process)
+		return m
+	}
+
+	m.stagingDB.SetChangeLog(m.config.ChangeLog)
 	err := m.stagingDB.Commit()
-	if err != nil {
-		m.statusMessage = "⚠ Commit failed: " + err.Error()
+	if errors.Is(err, staging.ErrIntegrity) {
+		m.statusMessage = "❌ Commit aborted, the real database was not touched: " + err.Error()
+		return m
+	}
+	if err != nil && !errors.Is(err, staging.ErrChangeLog) {
+		m.statusMessage = "⚠ Commit failed: " + withAdvice(err)
 		return m
 	}
 
+	backupPath := m.stagingDB.BackupPath()
 	m.stagingDB = nil
 	m.hasPendingChanges = false
-	m.statusMessage = "✓ Changes committed successfully!"
+	if err != nil {
+		m.statusMessage = "⚠ " + err.Error()
+		return m
+	}
+	m.statusMessage = fmt.Sprintf("✓ Integrity check passed, changes committed! Previous database saved to %s (undo with: gophermark -restore %s)",
+		backupPath, backupPath)
+
+	return m
+}
+
+func (m *Model) saveNewTitle() *Model {
+	m.editMode = AddURL
+	m.urlInput.Focus()
+	m.titleInput.Blur()
+	return m
+}
+
+func (m *Model) saveNewBookmark() *Model {
+	if m.currentFolder == nil {
+		m.statusMessage = "No folder selected"
+		m.editMode = EditNone
+		return m
+	}
+
+	title := m.titleInput.Value()
+	url := m.urlInput.Value()
+
+	if title == "" || url == "" {
+		m.statusMessage = "Title and URL are required"
+		return m
+	}
+
+	if !m.checkURLInput(&m.urlInput) {
+		return m
+	}
+
+	err := m.stagingDB.AddBookmark(m.currentFolder.ID, title, url)
+	if err != nil {
+		m.statusMessage = "Failed to add bookmark: " + err.Error()
+		m.editMode = EditNone
+		return m
+	}
+
+	newBookmark := &models.Bookmark{
+		Title: title,
+		URL:   url,
+		Type:  models.TypeBookmark,
+	}
+	m.bookmarks = append(m.bookmarks, newBookmark)
+	m.currentFolder.Children = append(m.currentFolder.Children, newBookmark)
+
+	m.markChanged()
+	m.editMode = EditNone
+	m.statusMessage = "✓ Bookmark added to staging (Ctrl+S to commit)"
+	m.titleInput.Blur()
+	m.urlInput.Blur()
+
+	m.listCursor = len(m.bookmarks) - 1
 
 	return m
 }
 
-func (m *Model) saveNewTitle() *Model {
-	m.editMode = AddURL
-	m.urlInput.Focus()
-	m.titleInput.Blur()
-	return m
+// moveListItem swaps the highlighted item with its neighbour in the list,
+// delta slots away, and keeps the cursor on it.
+func (m *Model) moveListItem(delta int) {
+	if m.inSearchMode || m.deadOnly {
+		m.statusMessage = "⚠ Clear the search or dead-link filter to reorder"
+		return
+	}
+	if m.inTagView() || m.inOrphanedView() {
+		return
+	}
+
+	target := m.listCursor + delta
+	if m.listCursor >= len(m.bookmarks) || target < 0 || target >= len(m.bookmarks) {
+		return
+	}
+
+	if m.stagingDB == nil {
+		var err error
+		m.stagingDB, err = m.createStaging()
+		if err != nil {
+			m.statusMessage = "Failed to create staging database: " + err.Error()
+			return
+		}
+	}
+
+	folder := m.currentFolder
+	current, neighbor := -1, -1
+	for i, child := range folder.Children {
+		switch child {
+		case m.bookmarks[m.listCursor]:
+			current = i
+		case m.bookmarks[target]:
+			neighbor = i
+		}
+	}
+	if current < 0 || neighbor < 0 {
+		return
+	}
+
+	if err := m.stagingDB.SwapPositions(folder.ID, current, neighbor); err != nil {
+		m.statusMessage = "Failed to reorder: " + err.Error()
+		return
+	}
+
+	folder.Children[current], folder.Children[neighbor] = folder.Children[neighbor], folder.Children[current]
+	for i, child := range folder.Children {
+		child.Position = i
+	}
+
+	m.bookmarks = m.folderContents(folder)
+	m.listCursor = target
+	m.markChanged()
+	m.statusMessage = "✓ Reordered (Ctrl+S to commit)"
+}
+
+// insertSeparator stages a separator directly below the highlighted item,
+// or at the end of the folder when it has no bookmarks.
+func (m *Model) insertSeparator() {
+	if m.inTagView() || m.inOrphanedView() {
+		return
+	}
+
+	if m.stagingDB == nil {
+		var err error
+		m.stagingDB, err = m.createStaging()
+		if err != nil {
+			m.statusMessage = "Failed to create staging database: " + err.Error()
+			return
+		}
+	}
+
+	folder := m.currentFolder
+	index := len(folder.Children)
+	position := len(folder.Children)
+	if m.listCursor < len(m.bookmarks) {
+		current := m.bookmarks[m.listCursor]
+		for i, child := range folder.Children {
+			if child == current {
+				index = i + 1
+				position = current.Position + 1
+				break
+			}
+		}
+	}
+
+	if err := m.stagingDB.AddSeparator(folder.ID, position); err != nil {
+		m.statusMessage = "Failed to add separator: " + err.Error()
+		return
+	}
+
+	for _, child := range folder.Children {
+		if child.Position >= position {
+			child.Position++
+		}
+	}
+
+	now := time.Now()
+	separator := &models.Bookmark{
+		Type:         models.TypeSeparator,
+		Parent:       folder.ID,
+		Position:     position,
+		DateAdded:    now,
+		LastModified: now,
+	}
+	folder.Children = append(folder.Children[:index], append([]*models.Bookmark{separator}, folder.Children[index:]...)...)
+	m.bookmarks = m.folderContents(folder)
+
+	m.markChanged()
+	m.statusMessage = "✓ Separator added to staging (Ctrl+S to commit)"
+}
+
+// ensureScratchFolder returns the Scratch folder, creating it in staging
+// and in the tree when it doesn't exist yet.
+func (m *Model) ensureScratchFolder() (*models.Bookmark, error) {
+	return m.ensureMenuFolder("Scratch", "", m.stagingDB.FindOrCreateScratchFolder)
+}
+
+// ensureMenuFolder returns the folder with guid, or titled title when guid
+// is empty, creating it with create and adding it to the end of the
+// bookmarks menu in the tree if needed.
+func (m *Model) ensureMenuFolder(title, guid string, create func() (int64, error)) (*models.Bookmark, error) {
+	folder := findFolderByTitle(m.root, title)
+	if guid != "" {
+		folder = findFolderByGUID(m.root, guid)
+	}
+	if folder != nil {
+		return folder, nil
+	}
+
+	folderID, err := create()
+	if err != nil {
+		return nil, err
+	}
+
+	bookmarksMenu := findFolderByGUID(m.root, "menu________")
+	if bookmarksMenu == nil {
+		return nil, fmt.Errorf("bookmarks menu not found")
+	}
+
+	now := time.Now()
+	folder = &models.Bookmark{
+		ID:           folderID,
+		Type:         models.TypeFolder,
+		Parent:       bookmarksMenu.ID,
+		Position:     len(bookmarksMenu.Children),
+		Title:        title,
+		DateAdded:    now,
+		LastModified: now,
+		GUID:         guid,
+		Children:     make([]*models.Bookmark, 0),
+	}
+	bookmarksMenu.Children = append(bookmarksMenu.Children, folder)
+	m.treeNodes = BuildFlatTree(m.root, m.expandedFolders, m.sortFolders)
+
+	return folder, nil
 }
 
-func (m *Model) saveNewBookmark() *Model {
-	if m.currentFolder == nil {
-		m.statusMessage = "No folder selected"
-		m.editMode = EditNone
-		return m
+// stashToScratch moves the marked bookmarks, or the highlighted one when
+// none are marked, into the Scratch folder to be sorted later.
+func (m *Model) stashToScratch() {
+	bookmarks := m.selectedBookmarkList()
+	if len(bookmarks) == 0 {
+		if bookmark := m.selectedBookmark(); bookmark != nil && bookmark.IsBookmark() {
+			bookmarks = []*models.Bookmark{bookmark}
+		}
+	}
+	if len(bookmarks) == 0 {
+		return
 	}
 
-	title := m.titleInput.Value()
-	url := m.urlInput.Value()
-
-	if title == "" || url == "" {
-		m.statusMessage = "Title and URL are required"
-		return m
+	if m.stagingDB == nil {
+		var err error
+		m.stagingDB, err = m.createStaging()
+		if err != nil {
+			m.statusMessage = "Failed to create staging database: " + err.Error()
+			return
+		}
 	}
 
-	err := m.stagingDB.AddBookmark(m.currentFolder.ID, title, url)
+	scratchFolder, err := m.ensureScratchFolder()
 	if err != nil {
-		m.statusMessage = "Failed to add bookmark: " + err.Error()
-		m.editMode = EditNone
-		return m
+		m.statusMessage = "Failed to find/create Scratch folder: " + err.Error()
+		return
 	}
 
-	newBookmark := &models.Bookmark{
-		Title: title,
-		URL:   url,
-		Type:  models.TypeBookmark,
-	}
-	m.bookmarks = append(m.bookmarks, newBookmark)
-	m.currentFolder.Children = append(m.currentFolder.Children, newBookmark)
+	moved := make(map[int64]bool)
+	var moveErrors int
+	for _, bookmark := range bookmarks {
+		if bookmark.Parent == scratchFolder.ID {
+			continue
+		}
+		if err := m.stagingDB.MoveBookmark(bookmark.ID, scratchFolder.ID, len(scratchFolder.Children)); err != nil {
+			moveErrors++
+			continue
+		}
 
-	m.hasPendingChanges = true
-	m.editMode = EditNone
-	m.statusMessage = "✓ Bookmark added to staging (Ctrl+S to commit)"
-	m.titleInput.Blur()
-	m.urlInput.Blur()
+		removeFromTree(m.root, map[int64]bool{bookmark.ID: true})
+		bookmark.Parent = scratchFolder.ID
+		bookmark.Position = len(scratchFolder.Children)
+		scratchFolder.Children = append(scratchFolder.Children, bookmark)
+		moved[bookmark.ID] = true
+	}
 
-	m.listCursor = len(m.bookmarks) - 1
+	for id := range moved {
+		delete(m.selectedBookmarks, id)
+	}
+	m.bookmarks = m.folderContents(m.currentFolder)
+	if m.listCursor >= len(m.bookmarks) {
+		m.listCursor = max(len(m.bookmarks)-1, 0)
+	}
+	if len(moved) > 0 {
+		m.markChanged()
+	}
 
-	return m
+	if moveErrors > 0 {
+		m.statusMessage = fmt.Sprintf("⚠ Stashed %d bookmarks to Scratch, failed %d (Ctrl+S to commit)", len(moved), moveErrors)
+	} else {
+		m.statusMessage = fmt.Sprintf("✓ Stashed %d bookmarks to Scratch (S: jump there, Ctrl+S to commit)", len(moved))
+	}
 }
 
 func (m *Model) saveScratchBookmark() *Model {
@@ -1278,44 +3824,21 @@ func (m *Model) saveScratchBookmark() *Model {
 		return m
 	}
 
-	scratchFolder := findFolderByTitle(m.root, "Scratch")
-	var scratchFolderID int64
-
-	if scratchFolder == nil {
-		var err error
-		scratchFolderID, err = m.stagingDB.FindOrCreateScratchFolder()
-		if err != nil {
-			m.statusMessage = "Failed to find/create Scratch folder: " + err.Error()
-			m.editMode = EditNone
-			return m
-		}
-
-		bookmarksMenu := findFolderByGUID(m.root, "menu________")
-		if bookmarksMenu != nil {
-			now := time.Now()
-			scratchFolder = &models.Bookmark{
-				ID:           scratchFolderID,
-				Type:         models.TypeFolder,
-				Parent:       bookmarksMenu.ID,
-				Position:     len(bookmarksMenu.Children),
-				Title:        "Scratch",
-				DateAdded:    now,
-				LastModified: now,
-				Children:     make([]*models.Bookmark, 0),
-			}
-			bookmarksMenu.Children = append(bookmarksMenu.Children, scratchFolder)
-			m.treeNodes = BuildFlatTree(m.root, m.expandedFolders)
-		}
-	} else {
-		scratchFolderID = scratchFolder.ID
+	if !m.checkURLInput(&m.scratchInput) {
+		return m
 	}
 
-	title := url
-	if len(title) > 50 {
-		title = title[:47] + "..."
+	scratchFolder, err := m.ensureScratchFolder()
+	if err != nil {
+		m.statusMessage = "Failed to find/create Scratch folder: " + err.Error()
+		m.editMode = EditNone
+		return m
 	}
+	scratchFolderID := scratchFolder.ID
+
+	title := truncateDisplay(url, 50)
 
-	err := m.stagingDB.AddBookmark(scratchFolderID, title, url)
+	err = m.stagingDB.AddBookmark(scratchFolderID, title, url)
 	if err != nil {
 		m.statusMessage = "Failed to add to scratch: " + err.Error()
 		m.editMode = EditNone
@@ -1335,10 +3858,10 @@ func (m *Model) saveScratchBookmark() *Model {
 	scratchFolder.Children = append(scratchFolder.Children, newBookmark)
 
 	if m.currentFolder == scratchFolder {
-		m.bookmarks = getBookmarksForFolder(m.currentFolder)
+		m.bookmarks = m.folderContents(m.currentFolder)
 	}
 
-	m.hasPendingChanges = true
+	m.markChanged()
 	m.editMode = EditNone
 	m.statusMessage = "✓ Added to Scratch (Ctrl+S to commit)"
 	m.scratchInput.Blur()
@@ -1346,19 +3869,213 @@ func (m *Model) saveScratchBookmark() *Model {
 	return m
 }
 
-func (m *Model) toggleSelection() {
-	if m.listCursor >= len(m.bookmarks) {
+func (m *Model) copySelectedURL() {
+	bookmark := m.selectedBookmark()
+	if bookmark == nil || bookmark.URL == "" {
+		m.statusMessage = "No URL to copy"
+		return
+	}
+
+	if err := clipboard.WriteAll(bookmark.URL); err != nil {
+		m.statusMessage = "❌ Copy failed: " + err.Error()
 		return
 	}
 
-	bookmark := m.bookmarks[m.listCursor]
+	m.statusMessage = "✓ Copied URL"
+}
+
+func (m *Model) toggleSelection() {
+	bookmark := m.selectedBookmark()
+	if bookmark == nil {
+		return
+	}
 	if m.selectedBookmarks[bookmark.ID] {
 		delete(m.selectedBookmarks, bookmark.ID)
-		m.statusMessage = fmt.Sprintf("Deselected: %s", bookmark.Title)
+		m.statusMessage = fmt.Sprintf("Deselected: %s", bookmark.DisplayTitle())
 	} else {
 		m.selectedBookmarks[bookmark.ID] = true
-		m.statusMessage = fmt.Sprintf("Selected: %s (%d total)", bookmark.Title, len(m.selectedBookmarks))
+		m.statusMessage = fmt.Sprintf("Selected: %s (%d total)", bookmark.DisplayTitle(), len(m.selectedBookmarks))
+	}
+}
+
+// selectAllVisible marks every bookmark in the list pane: the current
+// folder, or the search results while searching.
+func (m *Model) selectAllVisible() {
+	added := 0
+	for _, bookmark := range m.listBookmarks() {
+		if bookmark.IsBookmark() && !m.selectedBookmarks[bookmark.ID] {
+			m.selectedBookmarks[bookmark.ID] = true
+			added++
+		}
+	}
+	m.statusMessage = fmt.Sprintf("Selected %d more (%d total)", added, len(m.selectedBookmarks))
+}
+
+// invertSelection flips the marks on the bookmarks in the list pane.
+// Marks on bookmarks outside it are left alone.
+func (m *Model) invertSelection() {
+	for _, bookmark := range m.listBookmarks() {
+		if !bookmark.IsBookmark() {
+			continue
+		}
+		if m.selectedBookmarks[bookmark.ID] {
+			delete(m.selectedBookmarks, bookmark.ID)
+		} else {
+			m.selectedBookmarks[bookmark.ID] = true
+		}
+	}
+	m.statusMessage = fmt.Sprintf("Inverted selection (%d total)", len(m.selectedBookmarks))
+}
+
+func (m *Model) enterEmptyFoldersMode() {
+	m.emptyFolders = findEmptyFolders(m.root)
+	m.emptyFolderPaths = db.FolderPaths(m.root)
+	m.emptyFolderSelected = 0
+	m.editMode = EmptyFoldersMode
+	m.statusMessage = fmt.Sprintf("Found %d empty folders", len(m.emptyFolders))
+}
+
+// deleteEmptyFolders stages deletion of every folder found by the scan.
+// The list is ordered children-first, so nested empty folders are already
+// gone by the time their parent is deleted.
+func (m *Model) deleteEmptyFolders() {
+	if m.stagingDB == nil {
+		var err error
+		m.stagingDB, err = m.createStaging()
+		if err != nil {
+			m.statusMessage = "Failed to create staging database: " + err.Error()
+			m.editMode = EditNone
+			return
+		}
+	}
+
+	currentPath := findPath(m.root, m.currentFolder)
+
+	deleted := make(map[int64]bool)
+	var deleteErrors int
+	for _, folder := range m.emptyFolders {
+		if err := m.stagingDB.DeleteFolder(folder.ID); err != nil {
+			deleteErrors++
+			continue
+		}
+		deleted[folder.ID] = true
+	}
+
+	removeFromTree(m.root, deleted)
+	for id := range deleted {
+		delete(m.expandedFolders, id)
+	}
+
+	for i := len(currentPath) - 1; i >= 0; i-- {
+		if !deleted[currentPath[i].ID] {
+			m.currentFolder = currentPath[i]
+			break
+		}
+	}
+	m.bookmarks = m.folderContents(m.currentFolder)
+	m.listCursor = 0
+	m.treeNodes = BuildFlatTree(m.root, m.expandedFolders, m.sortFolders)
+	m.treeCursor = 0
+	if idx := FindNearestVisibleIndex(m.treeNodes, m.root, m.currentFolder); idx >= 0 {
+		m.treeCursor = idx
+	}
+
+	m.emptyFolders = nil
+	m.editMode = EditNone
+	if len(deleted) > 0 {
+		m.markChanged()
+	}
+
+	if deleteErrors > 0 {
+		m.statusMessage = fmt.Sprintf("⚠ Deleted %d empty folders, failed %d (Ctrl+S to commit)", len(deleted), deleteErrors)
+	} else {
+		m.statusMessage = fmt.Sprintf("✓ Deleted %d empty folders (Ctrl+S to commit)", len(deleted))
+	}
+}
+
+func (m *Model) enterConfirmDelete() {
+	m.editMode = ConfirmDelete
+	m.statusMessage = fmt.Sprintf("Confirm deletion of %d bookmarks", len(m.selectedBookmarks))
+	if groups := countByFolder(m.root, m.selectedBookmarkList()); len(groups) > 1 {
+		m.statusMessage += fmt.Sprintf(" from %d folders", len(groups))
+	}
+}
+
+func (m *Model) enterConfirmDeleteFolder(folder *models.Bookmark) {
+	if isBuiltinFolder(folder) {
+		m.statusMessage = "⚠ Built-in folders can't be deleted"
+		return
+	}
+
+	m.deletingFolder = folder
+	m.editMode = ConfirmDeleteFolder
+	m.statusMessage = fmt.Sprintf("Confirm deletion of folder \"%s\"", folder.DisplayTitle())
+}
+
+// deleteFolderRecursive stages removal of the folder awaiting confirmation
+// and everything under it, then drops the subtree from the in-memory tree.
+func (m *Model) deleteFolderRecursive() {
+	folder := m.deletingFolder
+	m.deletingFolder = nil
+	if folder == nil {
+		return
+	}
+
+	if m.stagingDB == nil {
+		var err error
+		m.stagingDB, err = m.createStaging()
+		if err != nil {
+			m.statusMessage = "Failed to create staging database: " + err.Error()
+			return
+		}
+	}
+
+	if err := m.stagingDB.DeleteFolderRecursive(folder.ID); err != nil {
+		m.statusMessage = "❌ Failed to delete folder: " + err.Error()
+		return
+	}
+
+	bookmarks, folders, separators := countContents(folder)
+	for _, bookmark := range collectAllBookmarks(folder) {
+		delete(m.selectedBookmarks, bookmark.ID)
+	}
+	for _, subfolder := range db.GetFolders(folder) {
+		delete(m.expandedFolders, subfolder.ID)
+	}
+
+	currentPath := findPath(m.root, m.currentFolder)
+	for i, node := range currentPath {
+		if node.ID == folder.ID && i > 0 {
+			m.currentFolder = currentPath[i-1]
+			break
+		}
 	}
+	removeFromTree(m.root, map[int64]bool{folder.ID: true})
+
+	m.bookmarks = m.folderContents(m.currentFolder)
+	m.listCursor = 0
+	m.treeNodes = BuildFlatTree(m.root, m.expandedFolders, m.sortFolders)
+	if idx := FindNearestVisibleIndex(m.treeNodes, m.root, m.currentFolder); idx >= 0 {
+		m.treeCursor = idx
+	} else if m.treeCursor >= len(m.treeNodes) {
+		m.treeCursor = len(m.treeNodes) - 1
+	}
+
+	m.markChanged()
+	m.statusMessage = fmt.Sprintf("✓ Deleted folder \"%s\" and %d items inside it (Ctrl+S to commit)",
+		folder.DisplayTitle(), bookmarks+folders+separators)
+}
+
+// selectedBookmarkList resolves the selected IDs against the whole tree,
+// since a selection can outlive the folder it was made in.
+func (m *Model) selectedBookmarkList() []*models.Bookmark {
+	var selected []*models.Bookmark
+	for _, bookmark := range collectAllBookmarks(m.root) {
+		if m.selectedBookmarks[bookmark.ID] {
+			selected = append(selected, bookmark)
+		}
+	}
+	return selected
 }
 
 func (m *Model) deleteSelected() {
@@ -1368,44 +4085,87 @@ func (m *Model) deleteSelected() {
 
 	if m.stagingDB == nil {
 		var err error
-		m.stagingDB, err = staging.CreateStaging(m.dbPath)
+		m.stagingDB, err = m.createStaging()
 		if err != nil {
 			m.statusMessage = "Failed to create staging database: " + err.Error()
 			return
 		}
 	}
 
-	var deleteErrors []string
-	deletedCount := 0
-	for bookmarkID := range m.selectedBookmarks {
-		err := m.stagingDB.DeleteBookmark(bookmarkID)
-		if err != nil {
-			deleteErrors = append(deleteErrors, err.Error())
+	// Bookmarks go to the trash first; only deleting from the trash
+	// removes them for good.
+	permanent := m.inTrash()
+	var trash *models.Bookmark
+	if !permanent {
+		var err error
+		if trash, err = m.ensureTrashFolder(); err != nil {
+			m.statusMessage = "Failed to find/create the trash folder: " + err.Error()
+			return
+		}
+	}
+
+	// Selections are kept by ID, so they can span folders when made from
+	// search results; prune the tree and the results along with the list.
+	selected := m.selectedBookmarkList()
+	deleted := make(map[int64]bool)
+	var deleteErrors int
+	for _, bookmark := range selected {
+		var err error
+		if permanent {
+			err = m.stagingDB.DeleteBookmark(bookmark.ID)
 		} else {
-			deletedCount++
+			err = m.stagingDB.TrashBookmark(bookmark.ID)
 		}
+		if err != nil {
+			deleteErrors++
+			continue
+		}
+		deleted[bookmark.ID] = true
+		delete(m.selectedBookmarks, bookmark.ID)
 	}
 
-	var remainingBookmarks []*models.Bookmark
-	for _, bookmark := range m.bookmarks {
-		if !m.selectedBookmarks[bookmark.ID] || deleteErrors != nil {
-			remainingBookmarks = append(remainingBookmarks, bookmark)
+	removeFromTree(m.root, deleted)
+	if !permanent {
+		for _, bookmark := range selected {
+			if deleted[bookmark.ID] {
+				bookmark.Parent = trash.ID
+				bookmark.Position = len(trash.Children)
+				trash.Children = append(trash.Children, bookmark)
+			}
 		}
 	}
-	m.bookmarks = remainingBookmarks
+	m.bookmarks = m.folderContents(m.currentFolder)
 
-	m.selectedBookmarks = make(map[int64]bool)
+	if m.inSearchMode {
+		remaining := m.searchResults[:0]
+		for _, result := range m.searchResults {
+			if !deleted[result.Bookmark.ID] {
+				remaining = append(remaining, result)
+			}
+		}
+		m.searchResults = remaining
+		if len(m.searchResults) == 0 {
+			m.exitSearchMode()
+		}
+	}
 
-	if m.listCursor >= len(m.bookmarks) && len(m.bookmarks) > 0 {
-		m.listCursor = len(m.bookmarks) - 1
+	if visible := len(m.listBookmarks()); m.listCursor >= visible && visible > 0 {
+		m.listCursor = visible - 1
 	}
 
-	m.hasPendingChanges = true
+	if len(deleted) > 0 {
+		m.markChanged()
+	}
 
-	if len(deleteErrors) > 0 {
-		m.statusMessage = fmt.Sprintf("⚠ Deleted %d, failed %d (Ctrl+S to commit)", deletedCount, len(deleteErrors))
-	} else {
-		m.statusMessage = fmt.Sprintf("✓ Deleted %d bookmarks (Ctrl+S to commit)", deletedCount)
+	switch {
+	case deleteErrors > 0 && permanent:
+		m.statusMessage = fmt.Sprintf("⚠ Deleted %d, failed %d (Ctrl+S to commit)", len(deleted), deleteErrors)
+	case deleteErrors > 0:
+		m.statusMessage = fmt.Sprintf("⚠ Moved %d to the trash, failed %d (Ctrl+S to commit)", len(deleted), deleteErrors)
+	case permanent:
+		m.statusMessage = fmt.Sprintf("✓ Deleted %d bookmarks for good (Ctrl+S to commit)", len(deleted))
+	default:
+		m.statusMessage = fmt.Sprintf("✓ Moved %d bookmarks to the trash (X: open it, Ctrl+S to commit)", len(deleted))
 	}
 }
 
@@ -1415,31 +4175,152 @@ func (m *Model) enterExportMode() {
 }
 
 func (m *Model) exportJSON() {
+	m.exportTo("json", export.WriteJSON)
+}
+
+func (m *Model) exportHTML() {
+	m.exportTo("html", export.WriteHTML)
+}
+
+func (m *Model) exportHTMLViewer() {
+	m.exportTo("viewer.html", export.WriteHTMLViewer)
+}
+
+func (m *Model) exportMarkdown() {
+	m.exportTo("md", export.WriteMarkdown)
+}
+
+func (m *Model) exportJSONL() {
+	m.exportTo("jsonl", export.WriteJSONL)
+}
+
+func (m *Model) exportOPML() {
+	m.exportTo("opml", export.WriteOPML)
+}
+
+// exportPreview is an export rendered in memory, waiting on
+// ExportPreviewMode to be written to filename.
+type exportPreview struct {
+	filename string
+	data     []byte
+	count    int // bookmarks in the export
+}
+
+// exportTo renders the whole tree, or only the marked bookmarks when
+// exportSelectedOnly is set, and shows the start of it before anything is
+// written. The file goes to a timestamped name in the current directory.
+func (m *Model) exportTo(ext string, write func(io.Writer, *models.Bookmark) error) {
 	timestamp := time.Now().Format("2006-01-02_15-04-05")
-	filename := filepath.Join(".", fmt.Sprintf("bookmarks_%s.json", timestamp))
+	filename := filepath.Join(".", fmt.Sprintf("bookmarks_%s.%s", timestamp, ext))
+
+	root := m.root
+	if m.exportSelectedOnly {
+		root = &models.Bookmark{
+			Type:     models.TypeFolder,
+			Title:    "Selected bookmarks",
+			Children: m.selectedBookmarkList(),
+		}
+	}
 
-	err := export.ExportJSON(m.root, filename)
-	if err != nil {
+	if m.exportStatus != exportAll {
+		root = pruneTree(root, m.exportStatusMatches)
+		if root == nil {
+			m.statusMessage = fmt.Sprintf("⚠ Nothing to export (%s)", m.exportStatus)
+			return
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := write(&buf, root); err != nil {
 		m.statusMessage = "❌ Export failed: " + err.Error()
-	} else {
-		m.statusMessage = "✓ Exported to " + filename
+		return
 	}
 
-	m.editMode = EditNone
+	m.exportPreview = exportPreview{filename: filename, data: buf.Bytes(), count: countBookmarksRecursive(root)}
+	m.editMode = ExportPreviewMode
+	m.statusMessage = fmt.Sprintf("Preview: %d bookmarks to %s", m.exportPreview.count, filename)
 }
 
-func (m *Model) exportHTML() {
-	timestamp := time.Now().Format("2006-01-02_15-04-05")
-	filename := filepath.Join(".", fmt.Sprintf("bookmarks_%s.html", timestamp))
+// writeExport writes the previewed export to its file.
+func (m *Model) writeExport() {
+	preview := m.exportPreview
+	m.exportPreview = exportPreview{}
+	m.editMode = EditNone
 
-	err := export.ExportHTML(m.root, filename)
+	err := os.WriteFile(preview.filename, preview.data, 0644)
 	if err != nil {
 		m.statusMessage = "❌ Export failed: " + err.Error()
+	} else if m.exportStatus != exportAll {
+		if m.exportSelectedOnly {
+			m.selectedBookmarks = make(map[int64]bool)
+		}
+		m.statusMessage = fmt.Sprintf("✓ Exported %d bookmarks (%s) to %s", preview.count, m.exportStatus, preview.filename)
+	} else if m.exportSelectedOnly {
+		m.selectedBookmarks = make(map[int64]bool)
+		m.statusMessage = fmt.Sprintf("✓ Exported %d selected bookmarks to %s", preview.count, preview.filename)
 	} else {
-		m.statusMessage = "✓ Exported to " + filename
+		m.statusMessage = "✓ Exported to " + preview.filename
 	}
 
-	m.editMode = EditNone
+	m.exportSelectedOnly = false
+	m.exportStatus = exportAll
+}
+
+func (m *Model) renderExportPreview(maxHeight int) string {
+	preview := m.exportPreview
+	var lines []string
+	lines = append(lines, folderStyle.Render("📤 Export Preview"))
+	lines = append(lines, dimStyle.Render("To: "+truncatePathLeft(preview.filename, max(m.paneWidth-8, 8))))
+	content := strings.Split(strings.TrimRight(string(preview.data), "\n"), "\n")
+	lines = append(lines, normalItemStyle.Render(fmt.Sprintf("%d bookmarks, %d lines", preview.count, len(content))))
+	lines = append(lines, "")
+
+	shown := min(len(content), max(maxHeight-8, 1))
+	for _, line := range content[:shown] {
+		line = strings.ReplaceAll(line, "\t", "    ")
+		lines = append(lines, dimStyle.Render("  "+truncateDisplay(line, max(m.paneWidth-6, 8))))
+	}
+	if shown < len(content) {
+		lines = append(lines, dimStyle.Render(fmt.Sprintf("  ... %d more lines", len(content)-shown)))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, dimStyle.Render("y/Enter: write file | n/Esc: back to formats"))
+	return strings.Join(lines, "\n")
+}
+
+// exportStatus narrows an export to links in one state in the last audit.
+type exportStatus int
+
+const (
+	exportAll exportStatus = iota
+	exportAlive
+	exportDead
+	numExportStatuses
+)
+
+func (s exportStatus) String() string {
+	switch s {
+	case exportAlive:
+		return "live links only"
+	case exportDead:
+		return "dead links only"
+	default:
+		return "all"
+	}
+}
+
+// exportStatusMatches reports whether the last audit put bookmark in the
+// state being exported. Bookmarks it didn't check match neither filter, and
+// parked domains count as neither live nor dead.
+func (m *Model) exportStatusMatches(bookmark *models.Bookmark) bool {
+	switch m.auditResults[bookmark.ID] {
+	case "OK", "HTTPS":
+		return m.exportStatus == exportAlive
+	case "DEAD":
+		return m.exportStatus == exportDead
+	}
+	return false
 }
 
 func (m *Model) renderInspector(maxHeight int) string {
@@ -1447,29 +4328,57 @@ func (m *Model) renderInspector(maxHeight int) string {
 	lines = append(lines, folderStyle.Render("🔬 Inspector"))
 	lines = append(lines, "")
 
-	if m.activePane != ListPane || len(m.bookmarks) == 0 || m.listCursor >= len(m.bookmarks) {
+	bookmark := m.selectedBookmark()
+	if m.activePane != ListPane || bookmark == nil {
 		lines = append(lines, dimStyle.Render("(no bookmark selected)"))
 		return strings.Join(lines, "\n")
 	}
 
-	bookmark := m.bookmarks[m.listCursor]
+	// Values are indented two columns inside the pane's padding.
+	valueWidth := max(m.paneWidth-4, 8)
 
 	lines = append(lines, normalItemStyle.Render("Title:"))
-	title := bookmark.Title
-	if len(title) > 30 {
-		title = title[:27] + "..."
-	}
-	lines = append(lines, dimStyle.Render("  "+title))
+	lines = append(lines, dimStyle.Render("  "+truncateDisplay(bookmark.DisplayTitle(), valueWidth)))
 	lines = append(lines, "")
 
-	lines = append(lines, normalItemStyle.Render("URL:"))
-	url := bookmark.URL
-	if len(url) > 30 {
-		url = url[:27] + "..."
+	if m.pageTitle.bookmarkID == bookmark.ID {
+		lines = append(lines, normalItemStyle.Render("Page title:"))
+		switch {
+		case m.pageTitle.fetching:
+			lines = append(lines, dimStyle.Render("  fetching..."))
+		case m.pageTitle.err != nil:
+			lines = append(lines, lipgloss.NewStyle().Foreground(accentColor).PaddingLeft(2).Width(valueWidth+2).Render("⚠ "+m.pageTitle.err.Error()))
+		default:
+			lines = append(lines, dimStyle.PaddingLeft(2).Width(valueWidth+2).Render(m.pageTitle.title))
+			if m.pageTitle.title != bookmark.Title {
+				lines = append(lines, dimStyle.Render("  P: use as title"))
+			}
+		}
+		lines = append(lines, "")
 	}
-	lines = append(lines, dimStyle.Render("  "+url))
+
+	lines = append(lines, normalItemStyle.Render("URL:"))
+	lines = append(lines, dimStyle.Render("  "+truncateDisplay(bookmark.URL, valueWidth)))
 	lines = append(lines, "")
 
+	if bookmark.Description != "" {
+		lines = append(lines, normalItemStyle.Render("Description:"))
+		lines = append(lines, dimStyle.PaddingLeft(2).Width(valueWidth+2).Render(bookmark.Description))
+		lines = append(lines, "")
+	}
+
+	if len(bookmark.Tags) > 0 {
+		lines = append(lines, normalItemStyle.Render("Tags:"))
+		lines = append(lines, dimStyle.Render("  "+strings.Join(bookmark.Tags, ", ")))
+		lines = append(lines, "")
+	}
+
+	if len(bookmark.Keywords) > 0 {
+		lines = append(lines, normalItemStyle.Render("Keywords:"))
+		lines = append(lines, dimStyle.Render("  "+strings.Join(bookmark.Keywords, ", ")))
+		lines = append(lines, "")
+	}
+
 	lines = append(lines, normalItemStyle.Render("GUID:"))
 	lines = append(lines, dimStyle.Render("  "+bookmark.GUID))
 	lines = append(lines, "")
@@ -1490,6 +4399,19 @@ func (m *Model) renderInspector(maxHeight int) string {
 	lines = append(lines, dimStyle.Render(fmt.Sprintf("  %d", bookmark.VisitCount)))
 	lines = append(lines, "")
 
+	if bookmark.FK != nil {
+		place := fmt.Sprintf("  id %d, frecency %d", *bookmark.FK, bookmark.Frecency)
+		if bookmark.Hidden {
+			place += ", hidden"
+		}
+		if bookmark.Typed {
+			place += ", typed"
+		}
+		lines = append(lines, normalItemStyle.Render("Place:"))
+		lines = append(lines, dimStyle.Render(truncateDisplay(place, valueWidth+2)))
+		lines = append(lines, "")
+	}
+
 	if status, ok := m.auditResults[bookmark.ID]; ok {
 		lines = append(lines, normalItemStyle.Render("Link Status:"))
 		statusStyle := dimStyle
@@ -1497,6 +4419,25 @@ func (m *Model) renderInspector(maxHeight int) string {
 			statusStyle = lipgloss.NewStyle().Foreground(accentColor)
 		}
 		lines = append(lines, statusStyle.Render("  "+status))
+
+		detail, ok := m.auditDetails[bookmark.ID]
+		if ok && detail.RedirectLoop() {
+			lines = append(lines, "")
+			lines = append(lines, lipgloss.NewStyle().Foreground(accentColor).Render(
+				fmt.Sprintf("  ⚠ Gave up after %d redirects (loop?)", detail.Redirects)))
+		} else if ok && detail.FinalURL != "" {
+			lines = append(lines, "")
+			if detail.Status == audit.StatusRedirectHTTPS {
+				lines = append(lines, normalItemStyle.Render("Upgrade to HTTPS:"))
+			} else {
+				hops := "1 redirect"
+				if detail.Redirects != 1 {
+					hops = fmt.Sprintf("%d redirects", detail.Redirects)
+				}
+				lines = append(lines, normalItemStyle.Render("→ "+hops+", final:"))
+			}
+			lines = append(lines, dimStyle.Render("  "+truncateDisplay(detail.FinalURL, valueWidth)))
+		}
 	}
 
 	return strings.Join(lines, "\n")
@@ -1504,6 +4445,7 @@ func (m *Model) renderInspector(maxHeight int) string {
 
 func (m *Model) toggleInspector() {
 	m.showInspector = !m.showInspector
+	m.computeLayout()
 	if m.showInspector {
 		m.statusMessage = "Inspector panel shown"
 	} else {
@@ -1511,14 +4453,175 @@ func (m *Model) toggleInspector() {
 	}
 }
 
-func (m *Model) startAudit() tea.Cmd {
+func (m *Model) cycleTheme() {
+	m.themeIndex = (m.themeIndex + 1) % len(themes)
+	applyTheme(themes[m.themeIndex])
+	m.statusMessage = fmt.Sprintf("Theme: %s", themes[m.themeIndex].Name)
+}
+
+// writeAuditReport saves every result of the audit, not just the dead
+// links, to a timestamped JSON file in the current directory.
+func (m *Model) writeAuditReport() {
+	results := make([]audit.LinkResult, 0, len(m.auditDetails))
+	for _, result := range m.auditDetails {
+		results = append(results, result)
+	}
+
+	timestamp := time.Now().Format("2006-01-02_15-04-05")
+	filename := filepath.Join(".", fmt.Sprintf("audit_%s.json", timestamp))
+
+	report := audit.NewFullReport(m.dbPath, results, db.FolderPaths(m.root))
+	if err := audit.WriteReport(report, filename); err != nil {
+		m.statusMessage = "❌ " + err.Error()
+		return
+	}
+	m.statusMessage = fmt.Sprintf("✓ Saved %d results (%d broken) to %s", len(report.Results), len(report.Broken), filename)
+}
+
+// toggleFolderSort switches the tree between the stored folder order and
+// alphabetical order, keeping the cursor on the same folder.
+func (m *Model) toggleFolderSort() {
+	var selected *models.Bookmark
+	if m.treeCursor < len(m.treeNodes) {
+		selected = m.treeNodes[m.treeCursor].Folder
+	}
+
+	m.sortFolders = !m.sortFolders
+	m.treeNodes = BuildFlatTree(m.root, m.expandedFolders, m.sortFolders)
+	if selected != nil {
+		if idx := FindNodeIndex(m.treeNodes, selected.ID); idx >= 0 {
+			m.treeCursor = idx
+		}
+	}
+
+	if m.sortFolders {
+		m.statusMessage = "Folders sorted by name (display only)"
+	} else {
+		m.statusMessage = "Folders in their saved order"
+	}
+}
+
+// savePreferences writes UI state back to the config file on quit. A
+// failure here shouldn't stop the program from exiting.
+func (m *Model) savePreferences() {
+	m.config.ShowInspector = m.showInspector
+	m.config.Theme = themes[m.themeIndex].Name
+	m.config.SortFolders = m.sortFolders
+	m.config.DatabasePath = m.dbPath
+	if err := m.config.Save(); err != nil && debugLog != nil {
+		debugLog.Printf("savePreferences: %v", err)
+	}
+}
+
+// createStaging copies the database into the configured staging directory,
+// pointing at staging_dir when the default one is too small.
+func (m *Model) createStaging() (*staging.StagingDB, error) {
+	stagingDB, err := staging.CreateStagingWithOptions(m.dbPath, staging.Options{StagingDir: m.config.StagingDir})
+	if errors.Is(err, staging.ErrNoSpace) {
+		return nil, fmt.Errorf("%w (set staging_dir in the config to use a bigger directory)", err)
+	}
+	return stagingDB, err
+}
+
+// withAdvice is err's message followed by the db package's suggestion for
+// dealing with it, when there is one.
+func withAdvice(err error) string {
+	if advice := db.Advice(err); advice != "" {
+		return err.Error() + ". " + advice
+	}
+	return err.Error()
+}
+
+// defaultAuditCacheDays is how long an audited URL's result is reused,
+// unless audit_cache_days says otherwise.
+const defaultAuditCacheDays = 7
+
+// newAuditor sets up an auditor from the config. With reuseCached, links
+// checked within audit_cache_days aren't fetched again.
+func (m *Model) newAuditor(reuseCached bool) *audit.Auditor {
+	auditor := audit.NewConfiguredAuditor(m.config)
+	if cache := m.loadAuditCache(); cache != nil {
+		maxAge := m.auditCacheAge()
+		if !reuseCached {
+			maxAge = 0
+		}
+		auditor.SetCache(cache, maxAge)
+	}
+	return auditor
+}
+
+// auditCacheAge is how long a checked URL is trusted before it's fetched
+// again; zero when audit_cache_days turns the cache off.
+func (m *Model) auditCacheAge() time.Duration {
+	days := m.config.AuditCacheDays
+	if days < 0 {
+		return 0
+	}
+	if days == 0 {
+		days = defaultAuditCacheDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// loadAuditCache reads the URL cache the first time an audit needs it. It
+// returns nil when the cache is turned off or can't be read.
+func (m *Model) loadAuditCache() *audit.Cache {
+	if m.auditCacheAge() == 0 {
+		return nil
+	}
+	if m.auditCache == nil {
+		path, err := config.AuditCacheFile()
+		if err != nil {
+			return nil
+		}
+		m.auditCache, err = audit.LoadCache(path)
+		if err != nil {
+			if debugLog != nil {
+				debugLog.Printf("loadAuditCache: %v", err)
+			}
+			return nil
+		}
+	}
+	return m.auditCache
+}
+
+func (m *Model) saveAuditCache() {
+	if m.auditCache == nil {
+		return
+	}
+	path, err := config.AuditCacheFile()
+	if err != nil {
+		return
+	}
+	if err := m.auditCache.Save(path, m.auditCacheAge()); err != nil && debugLog != nil {
+		debugLog.Printf("saveAuditCache: %v", err)
+	}
+}
+
+// startAudit checks every link under scope, or every link at all when
+// scope is nil. Results from an earlier audit are replaced either way.
+func (m *Model) startAudit(scope *models.Bookmark) tea.Cmd {
 	m.editMode = AuditMode
 	m.auditInProgress = true
+	m.deadLinkCounts = nil
+	m.auditScope = scope
 	m.auditResults = make(map[int64]string)
+	m.auditDetails = make(map[int64]audit.LinkResult)
 	m.auditTotal = 0
+	for _, bookmark := range collectAllBookmarks(m.auditRoot()) {
+		if bookmark.URL != "" {
+			m.auditTotal++
+		}
+	}
 	m.auditCompleted = 0
+	m.auditCached = 0
+	m.auditCounts = make(map[audit.LinkStatus]int)
+	m.auditCancelled = false
 	m.scanSpinner = 0
 	m.statusMessage = "Starting link audit..."
+	if scope != nil {
+		m.statusMessage = fmt.Sprintf("Auditing %d links in %s...", m.auditTotal, scope.DisplayTitle())
+	}
 
 	return tea.Batch(
 		m.runAudit(),
@@ -1526,6 +4629,165 @@ func (m *Model) startAudit() tea.Cmd {
 	)
 }
 
+// startFolderAudit audits the folder open in the list pane and the folders
+// inside it.
+func (m *Model) startFolderAudit() tea.Cmd {
+	if m.currentFolder == nil {
+		return nil
+	}
+	if isTagFolder(m.root, m.currentFolder) {
+		m.statusMessage = "⚠ Open a folder to audit it"
+		return nil
+	}
+	return m.startAudit(m.currentFolder)
+}
+
+// auditRoot is the top of the subtree being audited.
+func (m *Model) auditRoot() *models.Bookmark {
+	if m.auditScope != nil {
+		return m.auditScope
+	}
+	return m.root
+}
+
+// startReaudit re-checks only the links the last audit marked dead, keeping
+// every other result as it was.
+func (m *Model) startReaudit() tea.Cmd {
+	var dead []*models.Bookmark
+	for _, bookmark := range collectAllBookmarks(m.root) {
+		if m.auditResults[bookmark.ID] == "DEAD" {
+			dead = append(dead, bookmark)
+		}
+	}
+
+	if len(dead) == 0 {
+		m.statusMessage = "No dead links to re-check"
+		return nil
+	}
+
+	m.editMode = AuditMode
+	m.auditInProgress = true
+	m.deadLinkCounts = nil
+	m.auditTotal = len(dead)
+	m.auditCompleted = 0
+	m.auditCached = 0
+	m.auditCounts = make(map[audit.LinkStatus]int)
+	m.auditCancelled = false
+	m.scanSpinner = 0
+	m.statusMessage = fmt.Sprintf("Re-checking %d dead links...", len(dead))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.auditCancel = cancel
+	// Dead links are being re-checked because they may be back, so the
+	// cache is only updated, never trusted.
+	auditor := m.newAuditor(false)
+	m.auditResultChan = auditor.AuditBookmarks(ctx, dead)
+
+	return tea.Batch(
+		waitForAuditResult(m.auditResultChan),
+		m.tickAudit(),
+	)
+}
+
+// auditFlaggedLinks lists what the results screen offers to jump to: dead
+// links first, then parked domains.
+func (m *Model) auditFlaggedLinks() []*models.Bookmark {
+	flagged := append([]*models.Bookmark{}, m.auditDeadLinks...)
+	return append(flagged, m.auditParkedLinks...)
+}
+
+// compareWithLastAudit diffs the results of the audit that just finished
+// against those saved by the previous one, then saves the new results in
+// their place.
+func (m *Model) compareWithLastAudit() {
+	m.auditDiff = nil
+
+	path, err := config.AuditResultsFile()
+	if err != nil {
+		return
+	}
+
+	previous, savedAt, err := audit.LoadResults(path, m.dbPath)
+	if err != nil && debugLog != nil {
+		debugLog.Printf("compareWithLastAudit: %v", err)
+	}
+	if previous != nil {
+		diff := audit.DiffResults(previous, m.auditDetails)
+		m.auditDiff = &diff
+		m.auditDiffSince = savedAt
+	}
+
+	if err := audit.SaveResults(path, m.dbPath, m.auditDetails); err != nil && debugLog != nil {
+		debugLog.Printf("compareWithLastAudit: %v", err)
+	}
+}
+
+// auditDiffTransitions lists newly broken links ahead of recovered ones.
+func (m *Model) auditDiffTransitions() []audit.Transition {
+	if m.auditDiff == nil {
+		return nil
+	}
+	changes := append([]audit.Transition{}, m.auditDiff.NewlyBroken...)
+	return append(changes, m.auditDiff.Recovered...)
+}
+
+func (m *Model) auditDiffSummary() []string {
+	if m.auditDiff == nil {
+		return nil
+	}
+	summary := fmt.Sprintf("Since %s: %d newly broken, %d recovered (c: show changes)",
+		m.auditDiffSince.Format("2006-01-02"), len(m.auditDiff.NewlyBroken), len(m.auditDiff.Recovered))
+	style := dimStyle
+	if len(m.auditDiff.NewlyBroken) > 0 {
+		style = lipgloss.NewStyle().Foreground(accentColor)
+	}
+	return []string{style.Render(summary)}
+}
+
+// auditHTTPSHint offers the HTTPS upgrade on the results screen when the
+// audit found links to upgrade.
+func (m *Model) auditHTTPSHint() string {
+	if m.auditCounts[audit.StatusRedirectHTTPS] == 0 {
+		return ""
+	}
+	return "H: upgrade to HTTPS | "
+}
+
+func (m *Model) auditTally() string {
+	tally := fmt.Sprintf("Alive: %d  Dead: %d  Timeout: %d",
+		m.auditCounts[audit.StatusAlive], m.auditCounts[audit.StatusDead], m.auditCounts[audit.StatusTimeout])
+	if upgrades := m.auditCounts[audit.StatusRedirectHTTPS]; upgrades > 0 {
+		tally += fmt.Sprintf("  HTTPS: %d", upgrades)
+	}
+	if parked := m.auditCounts[audit.StatusParked]; parked > 0 {
+		tally += fmt.Sprintf("  Parked: %d", parked)
+	}
+	if skipped := m.auditCounts[audit.StatusSkipped]; skipped > 0 {
+		tally += fmt.Sprintf("  Skipped (non-web): %d", skipped)
+	}
+	return tally
+}
+
+// jumpToBookmark opens the folder containing bookmark and puts the list
+// cursor on it.
+func (m *Model) jumpToBookmark(bookmark *models.Bookmark) {
+	folder := findBookmarkByID(m.root, bookmark.Parent)
+	if folder == nil {
+		m.statusMessage = "Bookmark's folder not found"
+		return
+	}
+
+	m.jumpToFolder(folder)
+	for i, b := range m.listBookmarks() {
+		if b.ID == bookmark.ID {
+			m.listCursor = i
+			break
+		}
+	}
+	m.activePane = ListPane
+	m.statusMessage = ""
+}
+
 func (m *Model) tickAudit() tea.Cmd {
 	return tea.Tick(50*time.Millisecond, func(t time.Time) tea.Msg {
 		return auditTickMsg{}
@@ -1533,15 +4795,23 @@ func (m *Model) tickAudit() tea.Cmd {
 }
 
 func (m *Model) runAudit() tea.Cmd {
-	return func() tea.Msg {
-		auditor := audit.NewAuditor(10)
-		ctx := context.Background()
-		resultChan := auditor.AuditAll(ctx, m.root)
+	ctx, cancel := context.WithCancel(context.Background())
+	m.auditCancel = cancel
+	auditor := m.newAuditor(true)
+	m.auditResultChan = auditor.AuditAll(ctx, m.auditRoot())
 
-		for range resultChan {
-		}
+	return waitForAuditResult(m.auditResultChan)
+}
 
-		return auditCompleteMsg{}
+// waitForAuditResult delivers the next audit result to Update, which
+// re-issues it until the auditor closes the channel.
+func waitForAuditResult(results <-chan audit.LinkResult) tea.Cmd {
+	return func() tea.Msg {
+		result, ok := <-results
+		if !ok {
+			return auditCompleteMsg{}
+		}
+		return auditProgressMsg{result: result}
 	}
 }
 
@@ -1551,7 +4821,11 @@ func (m *Model) startDedup() tea.Cmd {
 	}
 	m.editMode = DedupMode
 	m.dedupScanning = true
-	m.dedupGroups = nil
+	m.dedupResults = nil
+	m.dedupAll = nil
+	m.dedupAllExact = 0
+	m.dedupFolders = nil
+	m.dedupDetail = false
 	m.scanSpinner = 0
 	m.statusMessage = "Scanning for duplicates..."
 
@@ -1566,6 +4840,8 @@ func (m *Model) startDedup() tea.Cmd {
 
 func (m *Model) runDedup() tea.Cmd {
 	dbPath := m.dbPath
+	scanned := &atomic.Int64{}
+	m.dedupScanned = scanned
 	if debugLog != nil {
 		debugLog.Println("runDedup: creating command function")
 	}
@@ -1588,11 +4864,174 @@ func (m *Model) runDedup() tea.Cmd {
 		if debugLog != nil {
 			debugLog.Println("runDedup: database opened, calling FindDuplicates")
 		}
-		groups, err := dedup.FindDuplicates(dbConn.Conn())
+		groups, err := dedup.FindDuplicatesWithOptions(dbConn.Conn(), dedup.ScanOptions{
+			Progress: func(rowsScanned int) {
+				scanned.Store(int64(rowsScanned))
+			},
+		})
 		if debugLog != nil {
 			debugLog.Printf("runDedup: FindDuplicates returned, groups=%d, err=%v", len(groups), err)
 		}
-		return dedupResultMsg{groups: groups, err: err}
+		if err != nil {
+			return dedupResultMsg{err: db.Classify(err)}
+		}
+
+		similar, err := dedup.FindSimilarTitles(dbConn.Conn(), similarTitleThreshold)
+		return dedupResultMsg{groups: groups, similar: similar, err: db.Classify(err)}
+	}
+}
+
+func dedupSummary(group dedup.DuplicateGroup) string {
+	where := "same folder"
+	if group.AcrossFolders {
+		where = "across folders"
+	}
+	if group.Title != "" {
+		return fmt.Sprintf("%s (%d similar, %s)", group.Title, len(group.Bookmarks), where)
+	}
+	return fmt.Sprintf("%s (%d duplicates, %s)", group.URL, len(group.Bookmarks), where)
+}
+
+// filterDedup rebuilds the listed groups from every group found, keeping
+// only those within a single folder when that filter is on.
+func (m *Model) filterDedup() {
+	m.dedupResults = nil
+	m.dedupExactCount = 0
+	for i, group := range m.dedupAll {
+		if m.dedupSameFolder && group.AcrossFolders {
+			continue
+		}
+		m.dedupResults = append(m.dedupResults, group)
+		if i < m.dedupAllExact {
+			m.dedupExactCount++
+		}
+	}
+	m.dedupSelected = min(m.dedupSelected, max(len(m.dedupResults)-1, 0))
+}
+
+func (m *Model) renderDuplicateGroup(maxHeight int) []string {
+	var lines []string
+	group := m.dedupResults[m.dedupSelected]
+
+	lines = append(lines, normalItemStyle.Render(dedupSummary(group)))
+	lines = append(lines, "")
+
+	start, end := scrollWindow(m.dedupKeep, len(group.Bookmarks), (maxHeight-5)/2)
+	for i := start; i < end; i++ {
+		bookmark := group.Bookmarks[i]
+		prefix := "  "
+		style := normalItemStyle
+		if i == m.dedupKeep {
+			prefix = "❯ "
+			style = selectedItemStyle
+		}
+
+		title := truncateDisplay(bookmark.DisplayTitle(), 35)
+		if i == m.dedupKeep {
+			title += " [keep]"
+		}
+		lines = append(lines, style.Render(prefix+title))
+
+		details := fmt.Sprintf("    %s | %d visits | frecency %d | added %s",
+			m.dedupPaths[bookmark.ID], bookmark.VisitCount, group.Frecency[bookmark.ID], bookmark.DateAdded.Format("2006-01-02"))
+		lines = append(lines, dimStyle.Render(details))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, dimStyle.Render("j/k: choose keeper | d: delete others | M: merge stats + delete others | Esc: back"))
+	return lines
+}
+
+// resolveDuplicateGroup stages deletion of every bookmark in the selected
+// group except the chosen keeper. With merge set, the keeper first takes the
+// summed visit count of the distinct places involved and the earliest
+// added date, so history stats survive the cleanup.
+func (m *Model) resolveDuplicateGroup(merge bool) {
+	if m.stagingDB == nil {
+		var err error
+		m.stagingDB, err = m.createStaging()
+		if err != nil {
+			m.statusMessage = "Failed to create staging database: " + err.Error()
+			return
+		}
+	}
+
+	group := m.dedupResults[m.dedupSelected]
+	keeper := group.Bookmarks[m.dedupKeep]
+
+	if merge {
+		visits := 0
+		seenPlaces := make(map[int64]bool)
+		earliest := keeper.DateAdded
+		for _, bookmark := range group.Bookmarks {
+			if bookmark.FK != nil && !seenPlaces[*bookmark.FK] {
+				seenPlaces[*bookmark.FK] = true
+				visits += bookmark.VisitCount
+			}
+			if bookmark.DateAdded.Before(earliest) {
+				earliest = bookmark.DateAdded
+			}
+		}
+
+		if keeper.FK != nil {
+			if err := m.stagingDB.UpdateBookmarkVisitCount(*keeper.FK, visits); err != nil {
+				m.statusMessage = "Failed to merge visit counts: " + err.Error()
+				return
+			}
+		}
+		if err := m.stagingDB.UpdateBookmarkDateAdded(keeper.ID, earliest); err != nil {
+			m.statusMessage = "Failed to merge date added: " + err.Error()
+			return
+		}
+
+		if node := findBookmarkByID(m.root, keeper.ID); node != nil {
+			node.VisitCount = visits
+			node.DateAdded = earliest
+		}
+	}
+
+	deleted := make(map[int64]bool)
+	var deleteErrors int
+	for _, bookmark := range group.Bookmarks {
+		if bookmark.ID == keeper.ID {
+			continue
+		}
+		if err := m.stagingDB.DeleteBookmark(bookmark.ID); err != nil {
+			deleteErrors++
+			continue
+		}
+		deleted[bookmark.ID] = true
+		delete(m.selectedBookmarks, bookmark.ID)
+	}
+
+	removeFromTree(m.root, deleted)
+	m.bookmarks = m.folderContents(m.currentFolder)
+	if m.listCursor >= len(m.bookmarks) && len(m.bookmarks) > 0 {
+		m.listCursor = len(m.bookmarks) - 1
+	}
+	m.markChanged()
+
+	for i := range m.dedupAll {
+		if &m.dedupAll[i].Bookmarks[0] == &group.Bookmarks[0] {
+			m.dedupAll = append(m.dedupAll[:i:i], m.dedupAll[i+1:]...)
+			if i < m.dedupAllExact {
+				m.dedupAllExact--
+			}
+			break
+		}
+	}
+	m.filterDedup()
+	m.dedupDetail = false
+	m.dedupFolders = duplicateFolders(m.dedupAll[:m.dedupAllExact])
+
+	action := "Deleted"
+	if merge {
+		action = "Merged and deleted"
+	}
+	if deleteErrors > 0 {
+		m.statusMessage = fmt.Sprintf("⚠ %s %d duplicates, failed %d (Ctrl+S to commit)", action, len(deleted), deleteErrors)
+	} else {
+		m.statusMessage = fmt.Sprintf("✓ %s %d duplicates (Ctrl+S to commit)", action, len(deleted))
 	}
 }
 
@@ -1616,6 +5055,36 @@ func collectAllBookmarks(node *models.Bookmark) []*models.Bookmark {
 	return bookmarks
 }
 
+// folderContents lists what the list pane shows for folder. A tag shows
+// every bookmark carrying it, wherever it's filed.
+func (m *Model) folderContents(folder *models.Bookmark) []*models.Bookmark {
+	if folder != nil && isTagFolder(m.root, folder) {
+		return buildTagIndex(m.root)[folder.Title]
+	}
+	return getBookmarksForFolder(folder)
+}
+
+// inTagView reports whether the list pane is showing a tag rather than a
+// folder, where adding and reordering have no meaning.
+func (m *Model) inTagView() bool {
+	if m.currentFolder != nil && isTagFolder(m.root, m.currentFolder) {
+		m.statusMessage = "⚠ Tags list bookmarks from many folders; open a folder to add or reorder"
+		return true
+	}
+	return false
+}
+
+// inOrphanedView reports whether the list pane is showing the orphaned
+// bookmarks, whose folder exists only in GopherMark and can't hold anything
+// new.
+func (m *Model) inOrphanedView() bool {
+	if m.currentFolder != nil && m.currentFolder.GUID == db.OrphanedFolderGUID {
+		m.statusMessage = "⚠ Orphaned bookmarks can only be moved out (t or b) or deleted"
+		return true
+	}
+	return false
+}
+
 func getBookmarksForFolder(folder *models.Bookmark) []*models.Bookmark {
 	if folder == nil {
 		return nil
@@ -1623,7 +5092,7 @@ func getBookmarksForFolder(folder *models.Bookmark) []*models.Bookmark {
 
 	var bookmarks []*models.Bookmark
 	for _, child := range folder.Children {
-		if child.IsBookmark() {
+		if child.IsBookmark() || child.IsSeparator() {
 			bookmarks = append(bookmarks, child)
 		}
 	}
@@ -1638,13 +5107,13 @@ func (m *Model) jumpToScratch() {
 	}
 
 	ExpandPath(m.root, scratchFolder, m.expandedFolders)
-	m.treeNodes = BuildFlatTree(m.root, m.expandedFolders)
+	m.treeNodes = BuildFlatTree(m.root, m.expandedFolders, m.sortFolders)
 
 	idx := FindNodeIndex(m.treeNodes, scratchFolder.ID)
 	if idx >= 0 {
 		m.treeCursor = idx
 		m.currentFolder = scratchFolder
-		m.bookmarks = getBookmarksForFolder(m.currentFolder)
+		m.bookmarks = m.folderContents(m.currentFolder)
 		m.listCursor = 0
 		m.activePane = ListPane
 		m.statusMessage = "Jumped to Scratch folder"
@@ -1665,6 +5134,34 @@ func findFolderByTitle(node *models.Bookmark, title string) *models.Bookmark {
 	return nil
 }
 
+func findBookmarkByID(node *models.Bookmark, id int64) *models.Bookmark {
+	if node.ID == id {
+		return node
+	}
+
+	for _, child := range node.Children {
+		if result := findBookmarkByID(child, id); result != nil {
+			return result
+		}
+	}
+
+	return nil
+}
+
+// removeFromTree drops the given IDs from the in-memory tree so the view
+// matches what has been staged.
+func removeFromTree(node *models.Bookmark, ids map[int64]bool) {
+	remaining := node.Children[:0]
+	for _, child := range node.Children {
+		if ids[child.ID] {
+			continue
+		}
+		removeFromTree(child, ids)
+		remaining = append(remaining, child)
+	}
+	node.Children = remaining
+}
+
 func findFolderByGUID(node *models.Bookmark, guid string) *models.Bookmark {
 	if node.IsFolder() && node.GUID == guid {
 		return node
@@ -1679,11 +5176,18 @@ func findFolderByGUID(node *models.Bookmark, guid string) *models.Bookmark {
 	return nil
 }
 
+// canBulkMove reports whether b works in the folder being shown: Scratch,
+// where links wait to be filed, or the orphaned bookmarks, which can only be
+// moved out.
+func (m *Model) canBulkMove() bool {
+	return m.currentFolder != nil && (m.currentFolder.Title == "Scratch" || m.currentFolder.GUID == db.OrphanedFolderGUID)
+}
+
 func (m *Model) enterBulkMoveMode() {
 	var allFolders []*models.Bookmark
 	var collectFolders func(*models.Bookmark)
 	collectFolders = func(node *models.Bookmark) {
-		if node.IsFolder() && node.Title != "Scratch" {
+		if node.IsFolder() && node.Title != "Scratch" && node.GUID != db.OrphanedFolderGUID {
 			allFolders = append(allFolders, node)
 		}
 		for _, child := range node.Children {
@@ -1707,7 +5211,7 @@ func (m *Model) executeBulkMove() *Model {
 
 	if m.stagingDB == nil {
 		var err error
-		m.stagingDB, err = staging.CreateStaging(m.dbPath)
+		m.stagingDB, err = m.createStaging()
 		if err != nil {
 			m.statusMessage = "Failed to create staging database: " + err.Error()
 			m.editMode = EditNone
@@ -1715,46 +5219,37 @@ func (m *Model) executeBulkMove() *Model {
 		}
 	}
 
+	// Marks can be in other folders than the one on screen, so resolve
+	// them against the whole tree rather than the list.
 	destFolder := m.bulkMoveFolders[m.bulkMoveSelected]
 	movedCount := 0
 	var moveErrors []string
 
-	for bookmarkID := range m.selectedBookmarks {
-		var bookmark *models.Bookmark
-		for _, b := range m.bookmarks {
-			if b.ID == bookmarkID {
-				bookmark = b
-				break
-			}
-		}
-
-		if bookmark == nil {
+	for _, bookmark := range m.selectedBookmarkList() {
+		if bookmark.Parent == destFolder.ID {
 			continue
 		}
-
-		err := m.stagingDB.MoveBookmark(bookmarkID, destFolder.ID, len(destFolder.Children))
-		if err != nil {
+		if err := m.stagingDB.MoveBookmark(bookmark.ID, destFolder.ID, len(destFolder.Children)); err != nil {
 			moveErrors = append(moveErrors, err.Error())
-		} else {
-			movedCount++
+			continue
 		}
-	}
 
-	var remainingBookmarks []*models.Bookmark
-	for _, bookmark := range m.bookmarks {
-		if !m.selectedBookmarks[bookmark.ID] {
-			remainingBookmarks = append(remainingBookmarks, bookmark)
-		}
+		removeFromTree(m.root, map[int64]bool{bookmark.ID: true})
+		bookmark.Parent = destFolder.ID
+		bookmark.Position = len(destFolder.Children)
+		destFolder.Children = append(destFolder.Children, bookmark)
+		delete(m.selectedBookmarks, bookmark.ID)
+		movedCount++
 	}
-	m.bookmarks = remainingBookmarks
 
-	m.selectedBookmarks = make(map[int64]bool)
-	m.hasPendingChanges = true
-	m.editMode = EditNone
-
-	if m.listCursor >= len(m.bookmarks) && len(m.bookmarks) > 0 {
-		m.listCursor = len(m.bookmarks) - 1
+	m.bookmarks = m.folderContents(m.currentFolder)
+	if m.listCursor >= len(m.bookmarks) {
+		m.listCursor = max(len(m.bookmarks)-1, 0)
 	}
+	if movedCount > 0 {
+		m.markChanged()
+	}
+	m.editMode = EditNone
 
 	if len(moveErrors) > 0 {
 		m.statusMessage = fmt.Sprintf("⚠ Moved %d/%d to %s (Ctrl+S to commit)", movedCount, movedCount+len(moveErrors), destFolder.Title)
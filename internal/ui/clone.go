@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/levineuwirth/gophermark/internal/db"
+	"github.com/levineuwirth/gophermark/internal/models"
+)
+
+// enterCloneMode asks where to file the highlighted bookmark a second time,
+// with the folder picker starting on the current folder.
+func (m *Model) enterCloneMode() {
+	bookmark := m.selectedBookmark()
+	if bookmark == nil || !bookmark.IsBookmark() {
+		return
+	}
+	if bookmark.ID <= 0 {
+		m.statusMessage = "⚠ Commit the new bookmark before cloning it"
+		return
+	}
+
+	m.enterFolderSwitch()
+	m.folderInput.Placeholder = "Clone into folder..."
+	m.cloning = bookmark
+	for i, match := range m.folderMatches {
+		if match.Folder == m.currentFolder {
+			m.folderSelected = i
+			break
+		}
+	}
+	m.statusMessage = "Clone " + bookmark.DisplayTitle() + " into..."
+}
+
+// cloneBookmarkTo stages a second bookmark for m.cloning in folder, sharing
+// its place so history and tags aren't duplicated.
+func (m *Model) cloneBookmarkTo(folder *models.Bookmark) {
+	source := m.cloning
+	m.cloning = nil
+
+	if folder.GUID == "root________" || folder.GUID == "tags________" || folder.GUID == db.OrphanedFolderGUID || isTagFolder(m.root, folder) {
+		m.statusMessage = "⚠ Bookmarks can't be filed in " + folder.DisplayTitle()
+		return
+	}
+
+	if m.stagingDB == nil {
+		var err error
+		m.stagingDB, err = m.createStaging()
+		if err != nil {
+			m.statusMessage = "Failed to create staging database: " + err.Error()
+			return
+		}
+	}
+
+	cloneID, err := m.stagingDB.CloneBookmark(source.ID, folder.ID)
+	if err != nil {
+		m.statusMessage = "Failed to clone bookmark: " + err.Error()
+		return
+	}
+
+	now := time.Now()
+	clone := *source
+	clone.ID = cloneID
+	clone.Parent = folder.ID
+	clone.Position = len(folder.Children)
+	clone.DateAdded = now
+	clone.LastModified = now
+	clone.Description = "" // descriptions belong to the bookmark, not the place
+	clone.Children = nil
+	folder.Children = append(folder.Children, &clone)
+
+	if m.currentFolder == folder {
+		m.bookmarks = m.folderContents(m.currentFolder)
+	}
+	m.markChanged()
+	m.statusMessage = fmt.Sprintf("✓ Cloned %s into %s (Ctrl+S to commit)", source.DisplayTitle(), folder.DisplayTitle())
+}
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/levineuwirth/gophermark/internal/db"
+)
+
+// CompareModel is a read-only screen listing the URLs bookmarked in only
+// one of two profiles, or in both, side by side.
+type CompareModel struct {
+	nameA  string
+	nameB  string
+	diff   *db.ProfileDiff
+	offset int
+	width  int
+	height int
+}
+
+func NewCompareModel(nameA, nameB string, diff *db.ProfileDiff) *CompareModel {
+	return &CompareModel{
+		nameA: nameA,
+		nameB: nameB,
+		diff:  diff,
+	}
+}
+
+func (m *CompareModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *CompareModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "j", "down":
+			m.scroll(1)
+		case "k", "up":
+			m.scroll(-1)
+		case "pgdown", " ":
+			m.scroll(m.rows())
+		case "pgup":
+			m.scroll(-m.rows())
+		case "g":
+			m.offset = 0
+		}
+	}
+	return m, nil
+}
+
+func (m *CompareModel) rows() int {
+	// Title, blank line, column headers, blank line and the help line.
+	return max(m.height-7, 1)
+}
+
+func (m *CompareModel) scroll(delta int) {
+	longest := max(len(m.diff.OnlyA), len(m.diff.Both), len(m.diff.OnlyB))
+	m.offset = min(max(m.offset+delta, 0), max(longest-m.rows(), 0))
+}
+
+func (m *CompareModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	columnWidth := max(m.width/3-2, 10)
+	columns := []string{
+		m.renderColumn("Only in "+m.nameA, m.diff.OnlyA, columnWidth),
+		m.renderColumn("In both", m.diff.Both, columnWidth),
+		m.renderColumn("Only in "+m.nameB, m.diff.OnlyB, columnWidth),
+	}
+
+	title := titleStyle.Render(fmt.Sprintf("GopherMark - Compare %s / %s", m.nameA, m.nameB))
+	help := helpStyle.Render("j/k: scroll | PgUp/PgDn: page | g: top | q: quit")
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		lipgloss.JoinHorizontal(lipgloss.Top, columns...),
+		help,
+	)
+}
+
+func (m *CompareModel) renderColumn(header string, urls []string, width int) string {
+	var lines []string
+	lines = append(lines, folderStyle.Render(fmt.Sprintf("%s (%d)", truncatePathLeft(header, width-8), len(urls))))
+	lines = append(lines, "")
+
+	end := min(m.offset+m.rows(), len(urls))
+	for i := m.offset; i < end; i++ {
+		lines = append(lines, dimStyle.Render(truncateDisplay(urls[i], width-2)))
+	}
+
+	return lipgloss.NewStyle().Width(width).PaddingRight(2).Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/levineuwirth/gophermark/internal/models"
+	"github.com/levineuwirth/gophermark/internal/staging"
+)
+
+// deadLinksFolder returns the folder dead links are archived to, or nil
+// when nothing has been archived.
+func (m *Model) deadLinksFolder() *models.Bookmark {
+	return findFolderByGUID(m.root, staging.DeadLinksFolderGUID)
+}
+
+// inDeadLinks reports whether the list pane is showing the dead links
+// folder, where u puts bookmarks back.
+func (m *Model) inDeadLinks() bool {
+	archive := m.deadLinksFolder()
+	return archive != nil && !m.inSearchMode && m.currentFolder == archive
+}
+
+// enterConfirmArchiveDead collects the links the audit found dead that are
+// still filed elsewhere and asks before moving them. Rows inside tag
+// folders only carry a tag, so they stay put.
+func (m *Model) enterConfirmArchiveDead() {
+	archive := m.deadLinksFolder()
+	m.archiveCandidates = nil
+	for _, bookmark := range m.auditDeadLinks {
+		if findBookmarkByID(m.root, bookmark.ID) == nil || (archive != nil && bookmark.Parent == archive.ID) {
+			continue
+		}
+		if parent := findBookmarkByID(m.root, bookmark.Parent); parent != nil && isTagFolder(m.root, parent) {
+			continue
+		}
+		m.archiveCandidates = append(m.archiveCandidates, bookmark)
+	}
+
+	if len(m.archiveCandidates) == 0 {
+		m.statusMessage = "No dead links left to archive"
+		return
+	}
+
+	m.editMode = ConfirmArchiveDead
+	m.statusMessage = fmt.Sprintf("Confirm archiving %d dead links", len(m.archiveCandidates))
+}
+
+// archiveDeadLinks stages moving the candidates into the dead links
+// folder, creating it in the bookmarks menu if needed.
+func (m *Model) archiveDeadLinks() {
+	if m.stagingDB == nil {
+		var err error
+		m.stagingDB, err = m.createStaging()
+		if err != nil {
+			m.statusMessage = "Failed to create staging database: " + err.Error()
+			return
+		}
+	}
+
+	archive, err := m.ensureMenuFolder(staging.DeadLinksFolderTitle, staging.DeadLinksFolderGUID, m.stagingDB.FindOrCreateDeadLinksFolder)
+	if err != nil {
+		m.statusMessage = "Failed to find/create the dead links folder: " + err.Error()
+		return
+	}
+
+	var archived, failed int
+	for _, bookmark := range m.archiveCandidates {
+		if err := m.stagingDB.ArchiveDeadLink(bookmark.ID); err != nil {
+			failed++
+			if debugLog != nil {
+				debugLog.Printf("archiveDeadLinks: bookmark %d: %v", bookmark.ID, err)
+			}
+			continue
+		}
+
+		removeFromTree(m.root, map[int64]bool{bookmark.ID: true})
+		bookmark.Parent = archive.ID
+		bookmark.Position = len(archive.Children)
+		archive.Children = append(archive.Children, bookmark)
+		delete(m.selectedBookmarks, bookmark.ID)
+		archived++
+	}
+
+	m.archiveCandidates = nil
+	m.bookmarks = m.folderContents(m.currentFolder)
+	if m.listCursor >= len(m.bookmarks) {
+		m.listCursor = max(len(m.bookmarks)-1, 0)
+	}
+	if archived > 0 {
+		m.markChanged()
+	}
+
+	if failed > 0 {
+		m.statusMessage = fmt.Sprintf("⚠ Archived %d dead links to %s, failed %d (Ctrl+S to commit)", archived, staging.DeadLinksFolderTitle, failed)
+	} else {
+		m.statusMessage = fmt.Sprintf("✓ Archived %d dead links to %s (u there: put back, Ctrl+S to commit)", archived, staging.DeadLinksFolderTitle)
+	}
+}
+
+// unarchiveDeadLinks puts the marked bookmarks in the dead links folder, or
+// the highlighted one when none are marked, back where they were archived
+// from. Only bookmarks archived since the last commit can go back.
+func (m *Model) unarchiveDeadLinks() {
+	restored, attempted, err := m.putBack(m.deadLinksFolder(), (*staging.StagingDB).UnarchiveDeadLink)
+	if attempted == 0 {
+		return
+	}
+
+	if err != nil {
+		m.statusMessage = fmt.Sprintf("⚠ Put back %d, failed %d: %v", restored, attempted-restored, err)
+	} else {
+		m.statusMessage = fmt.Sprintf("✓ Put back %d bookmarks (Ctrl+S to commit)", restored)
+	}
+}
+
+func (m *Model) renderConfirmArchiveDead(maxHeight int) string {
+	var lines []string
+	lines = append(lines, folderStyle.Render("☠ Archive Dead Links"))
+	lines = append(lines, "")
+	lines = append(lines, normalItemStyle.Render(fmt.Sprintf("Move %d dead links to \"%s\" in the bookmarks menu?", len(m.archiveCandidates), staging.DeadLinksFolderTitle)))
+	lines = append(lines, "")
+
+	// Count where they come from, as deleting does for marks across folders.
+	counts := make(map[int64]int)
+	var parents []int64
+	for _, bookmark := range m.archiveCandidates {
+		if counts[bookmark.Parent] == 0 {
+			parents = append(parents, bookmark.Parent)
+		}
+		counts[bookmark.Parent]++
+	}
+	shown := min(len(parents), max(maxHeight-10, 1))
+	for _, id := range parents[:shown] {
+		path := "(unknown folder)"
+		if folder := findBookmarkByID(m.root, id); folder != nil {
+			path = folderBreadcrumb(m.root, folder)
+		}
+		lines = append(lines, dimStyle.Render(fmt.Sprintf("  %d from %s", counts[id], truncatePathLeft(path, 50))))
+	}
+	if shown < len(parents) {
+		lines = append(lines, dimStyle.Render(fmt.Sprintf("  ... and %d more folders", len(parents)-shown)))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, dimStyle.Render("Nothing is deleted; u in that folder puts them back until you commit."))
+	lines = append(lines, "")
+	lines = append(lines, dimStyle.Render("y/Enter: stage | n/Esc: cancel"))
+	return strings.Join(lines, "\n")
+}
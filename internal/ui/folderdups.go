@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/levineuwirth/gophermark/internal/db"
+	"github.com/levineuwirth/gophermark/internal/dedup"
+	"github.com/levineuwirth/gophermark/internal/models"
+)
+
+func (m *Model) enterFolderDupsMode() {
+	m.folderDups = dedup.FindDuplicateFolders(m.root, m.folderDupsAnywhere)
+	m.folderDupPaths = db.FolderPaths(m.root)
+	m.folderDupSelected = 0
+	m.editMode = FolderDupsMode
+	m.statusMessage = fmt.Sprintf("Found %d sets of duplicate folders", len(m.folderDups))
+}
+
+// mergeFolderGroup moves everything in the selected group's other folders
+// into the first one, then deletes them once they're empty. A folder that
+// holds the one being kept is skipped, since moving its contents into it
+// would put a folder inside itself.
+func (m *Model) mergeFolderGroup() {
+	if m.stagingDB == nil {
+		var err error
+		m.stagingDB, err = m.createStaging()
+		if err != nil {
+			m.statusMessage = "Failed to create staging database: " + err.Error()
+			return
+		}
+	}
+
+	group := m.folderDups[m.folderDupSelected]
+	keeper := group.Folders[0]
+
+	var merged, skipped, failed int
+	deleted := make(map[int64]bool)
+	for _, other := range group.Folders[1:] {
+		if dedup.IsDescendant(other, keeper) {
+			skipped++
+			continue
+		}
+
+		var remaining []*models.Bookmark
+		for _, child := range other.Children {
+			if err := m.stagingDB.MoveBookmark(child.ID, keeper.ID, len(keeper.Children)); err != nil {
+				failed++
+				remaining = append(remaining, child)
+				continue
+			}
+			child.Parent = keeper.ID
+			child.Position = len(keeper.Children)
+			keeper.Children = append(keeper.Children, child)
+		}
+		other.Children = remaining
+		if len(remaining) > 0 {
+			continue
+		}
+
+		if err := m.stagingDB.DeleteFolder(other.ID); err != nil {
+			failed++
+			continue
+		}
+		deleted[other.ID] = true
+		merged++
+	}
+
+	removeFromTree(m.root, deleted)
+	for id := range deleted {
+		delete(m.expandedFolders, id)
+	}
+	if m.currentFolder != nil && deleted[m.currentFolder.ID] {
+		m.currentFolder = keeper
+	}
+	m.bookmarks = m.folderContents(m.currentFolder)
+	m.listCursor = 0
+	m.treeNodes = BuildFlatTree(m.root, m.expandedFolders, m.sortFolders)
+	if idx := FindNearestVisibleIndex(m.treeNodes, m.root, m.currentFolder); idx >= 0 {
+		m.treeCursor = idx
+	}
+	m.markChanged()
+
+	m.folderDups = append(m.folderDups[:m.folderDupSelected:m.folderDupSelected], m.folderDups[m.folderDupSelected+1:]...)
+	m.folderDupSelected = min(m.folderDupSelected, max(len(m.folderDups)-1, 0))
+	m.folderDupPaths = db.FolderPaths(m.root)
+
+	m.statusMessage = fmt.Sprintf("✓ Merged %d folders into %s (Ctrl+S to commit)", merged, keeper.DisplayTitle())
+	if skipped > 0 || failed > 0 {
+		m.statusMessage = fmt.Sprintf("⚠ Merged %d folders into %s, skipped %d containing it, %d failures (Ctrl+S to commit)",
+			merged, keeper.DisplayTitle(), skipped, failed)
+	}
+}
+
+func (m *Model) renderFolderDups(maxHeight int) string {
+	var lines []string
+	lines = append(lines, folderStyle.Render("📂 Duplicate Folders"))
+	lines = append(lines, "")
+
+	scope := "with the same parent"
+	toggle := "a: match anywhere"
+	if m.folderDupsAnywhere {
+		scope = "anywhere in the tree"
+		toggle = "a: same parent only"
+	}
+
+	if len(m.folderDups) == 0 {
+		lines = append(lines, dimStyle.Render("No folders share a title "+scope))
+		lines = append(lines, "")
+		lines = append(lines, dimStyle.Render(toggle+" | any other key: close"))
+		return strings.Join(lines, "\n")
+	}
+
+	lines = append(lines, normalItemStyle.Render(fmt.Sprintf("%d titles used by more than one folder %s:", len(m.folderDups), scope)))
+	lines = append(lines, "")
+
+	group := m.folderDups[m.folderDupSelected]
+	// The selected group's folders are listed below the groups.
+	detailRows := len(group.Folders) + 2
+	start, end := scrollWindow(m.folderDupSelected, len(m.folderDups), max(maxHeight-7-detailRows, 1))
+	for i := start; i < end; i++ {
+		prefix := "  "
+		style := normalItemStyle
+		if i == m.folderDupSelected {
+			prefix = "❯ "
+			style = selectedItemStyle
+		}
+		lines = append(lines, style.Render(fmt.Sprintf("%s%s (%d folders)", prefix, m.folderDups[i].Title, len(m.folderDups[i].Folders))))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, dimStyle.Render("Merging moves everything into the first:"))
+	for i, folder := range group.Folders {
+		path := folder.DisplayTitle()
+		if parent := m.folderDupPaths[folder.ID]; parent != "" {
+			path = parent + " / " + path
+		}
+		label := fmt.Sprintf("  %s (%d bookmarks)", truncatePathLeft(path, 50), countBookmarksRecursive(folder))
+		if i == 0 {
+			label += " ← keep"
+		}
+		lines = append(lines, dimStyle.Render(label))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, dimStyle.Render("j/k: navigate | Enter/m: merge | "+toggle+" | any other key: close"))
+	return strings.Join(lines, "\n")
+}
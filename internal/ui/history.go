@@ -0,0 +1,194 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/levineuwirth/gophermark/internal/db"
+	"github.com/levineuwirth/gophermark/internal/models"
+)
+
+// defaultHistoryMinVisits is how often a page must have been visited to be
+// suggested, unless history_min_visits says otherwise.
+const defaultHistoryMinVisits = 5
+
+// maxHistoryPlaces caps how many pages the history view lists.
+const maxHistoryPlaces = 500
+
+type historyLoadedMsg struct {
+	places []db.HistoryPlace
+	err    error
+}
+
+func (m *Model) historyMinVisits() int {
+	if m.config != nil && m.config.HistoryMinVisits > 0 {
+		return m.config.HistoryMinVisits
+	}
+	return defaultHistoryMinVisits
+}
+
+// enterHistoryMode lists the pages visited often but never bookmarked,
+// read from the history in the background.
+func (m *Model) enterHistoryMode() tea.Cmd {
+	if m.currentFolder == nil || m.inTagView() || m.inOrphanedView() {
+		return nil
+	}
+
+	m.editMode = HistoryMode
+	m.historyPlaces = nil
+	m.historySelected = 0
+	m.historyMarked = make(map[int64]bool)
+	m.historyLoading = true
+	m.statusMessage = "Reading history..."
+
+	dbPath, minVisits := m.dbPath, m.historyMinVisits()
+	return func() tea.Msg {
+		conn, err := db.OpenReadOnly(dbPath)
+		if err != nil {
+			return historyLoadedMsg{err: err}
+		}
+		defer conn.Close()
+
+		places, err := conn.FetchUnbookmarkedPlaces(context.Background(), minVisits, maxHistoryPlaces)
+		return historyLoadedMsg{places: places, err: err}
+	}
+}
+
+// setHistoryPlaces drops pages already bookmarked this session, which the
+// database on disk doesn't know about until the commit.
+func (m *Model) setHistoryPlaces(places []db.HistoryPlace) {
+	bookmarked := make(map[string]bool)
+	var visit func(*models.Bookmark)
+	visit = func(node *models.Bookmark) {
+		if node.IsBookmark() {
+			bookmarked[node.URL] = true
+		}
+		for _, child := range node.Children {
+			visit(child)
+		}
+	}
+	visit(m.root)
+
+	m.historyPlaces = nil
+	for _, place := range places {
+		if !bookmarked[place.URL] {
+			m.historyPlaces = append(m.historyPlaces, place)
+		}
+	}
+	m.historySelected = 0
+}
+
+// bookmarkHistoryPlaces stages bookmarks in the current folder for the
+// marked pages, or the highlighted one when none are marked, and takes them
+// off the list.
+func (m *Model) bookmarkHistoryPlaces() {
+	if m.stagingDB == nil {
+		var err error
+		m.stagingDB, err = m.createStaging()
+		if err != nil {
+			m.statusMessage = "Failed to create staging database: " + err.Error()
+			return
+		}
+	}
+
+	folder := m.currentFolder
+	var added, failed int
+	var remaining []db.HistoryPlace
+	for i, place := range m.historyPlaces {
+		wanted := m.historyMarked[place.ID] || (len(m.historyMarked) == 0 && i == m.historySelected)
+		if !wanted {
+			remaining = append(remaining, place)
+			continue
+		}
+
+		title := place.Title
+		if title == "" {
+			title = place.URL
+		}
+		if err := m.stagingDB.AddBookmark(folder.ID, title, place.URL); err != nil {
+			failed++
+			remaining = append(remaining, place)
+			if debugLog != nil {
+				debugLog.Printf("bookmarkHistoryPlaces: %s: %v", place.URL, err)
+			}
+			continue
+		}
+
+		now := time.Now()
+		folder.Children = append(folder.Children, &models.Bookmark{
+			Type:         models.TypeBookmark,
+			Parent:       folder.ID,
+			Position:     len(folder.Children),
+			Title:        title,
+			URL:          place.URL,
+			VisitCount:   place.VisitCount,
+			DateAdded:    now,
+			LastModified: now,
+		})
+		added++
+	}
+
+	m.historyPlaces = remaining
+	m.historyMarked = make(map[int64]bool)
+	m.historySelected = min(m.historySelected, max(len(m.historyPlaces)-1, 0))
+	m.bookmarks = m.folderContents(m.currentFolder)
+	if added > 0 {
+		m.markChanged()
+	}
+
+	if failed > 0 {
+		m.statusMessage = fmt.Sprintf("⚠ Bookmarked %d pages in %s, failed %d (Ctrl+S to commit)", added, folder.DisplayTitle(), failed)
+	} else {
+		m.statusMessage = fmt.Sprintf("✓ Bookmarked %d pages in %s (Ctrl+S to commit)", added, folder.DisplayTitle())
+	}
+}
+
+func (m *Model) renderHistory(maxHeight int) string {
+	var lines []string
+	lines = append(lines, folderStyle.Render("🕘 Visited but not bookmarked"))
+	lines = append(lines, dimStyle.Render("Into: "+truncatePathLeft(folderBreadcrumb(m.root, m.currentFolder), max(m.paneWidth-8, 8))))
+	lines = append(lines, "")
+
+	if m.historyLoading {
+		lines = append(lines, dimStyle.Render("Reading history..."))
+		return strings.Join(lines, "\n")
+	}
+	if len(m.historyPlaces) == 0 {
+		lines = append(lines, dimStyle.Render(fmt.Sprintf("No unbookmarked pages visited %d or more times", m.historyMinVisits())))
+		lines = append(lines, "")
+		lines = append(lines, dimStyle.Render("Press any key to close"))
+		return strings.Join(lines, "\n")
+	}
+
+	// Each page takes two lines: its title and visits, then its URL.
+	start, end := scrollWindow(m.historySelected, len(m.historyPlaces), max((maxHeight-6)/2, 1))
+	for i := start; i < end; i++ {
+		place := m.historyPlaces[i]
+		prefix := "  "
+		style := normalItemStyle
+		if i == m.historySelected {
+			prefix = "❯ "
+			style = selectedItemStyle
+		}
+		mark := " "
+		if m.historyMarked[place.ID] {
+			mark = "✓"
+		}
+
+		title := place.Title
+		if title == "" {
+			title = "(untitled)"
+		}
+		visits := fmt.Sprintf(" (%d visits)", place.VisitCount)
+		title = truncateDisplay(title, max(m.paneWidth-8-len(visits), 8))
+		lines = append(lines, style.Render(prefix+mark+" "+title)+dimStyle.Render(visits))
+		lines = append(lines, dimStyle.Render("     "+truncateDisplay(place.URL, max(m.paneWidth-8, 8))))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, dimStyle.Render("j/k: navigate | Space/m: mark | Enter: bookmark marked (or highlighted) | any other key: close"))
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/levineuwirth/gophermark/internal/audit"
+	"github.com/levineuwirth/gophermark/internal/models"
+)
+
+// httpsUpgrade is a bookmark whose http URL the last audit found redirecting
+// to the same page over https.
+type httpsUpgrade struct {
+	Bookmark *models.Bookmark
+	NewURL   string
+}
+
+// enterConfirmHTTPSUpgrade collects the audited links that can move to
+// https unchanged and asks before staging them. Redirects that also change
+// the host or path are left alone.
+func (m *Model) enterConfirmHTTPSUpgrade() {
+	m.httpsUpgrades = nil
+	m.httpsUpgradeSkipped = 0
+	for id, result := range m.auditDetails {
+		if result.Status != audit.StatusRedirectHTTPS || findBookmarkByID(m.root, id) == nil {
+			continue
+		}
+		newURL, ok := result.HTTPSUpgrade()
+		if !ok {
+			m.httpsUpgradeSkipped++
+			continue
+		}
+		m.httpsUpgrades = append(m.httpsUpgrades, httpsUpgrade{Bookmark: result.Bookmark, NewURL: newURL})
+	}
+	sort.Slice(m.httpsUpgrades, func(i, j int) bool {
+		return m.httpsUpgrades[i].Bookmark.URL < m.httpsUpgrades[j].Bookmark.URL
+	})
+
+	if len(m.httpsUpgrades) == 0 {
+		m.statusMessage = "No links redirect to the same page over HTTPS"
+		if m.httpsUpgradeSkipped > 0 {
+			m.statusMessage += fmt.Sprintf(" (%d redirect elsewhere on the same host)", m.httpsUpgradeSkipped)
+		}
+		return
+	}
+
+	m.editMode = ConfirmHTTPSUpgrade
+	m.statusMessage = fmt.Sprintf("Confirm upgrading %d bookmarks to HTTPS", len(m.httpsUpgrades))
+}
+
+// applyHTTPSUpgrades stages the new URLs, as editing each URL would, and
+// clears their HTTPS flag from the audit results.
+func (m *Model) applyHTTPSUpgrades() {
+	if m.stagingDB == nil {
+		var err error
+		m.stagingDB, err = m.createStaging()
+		if err != nil {
+			m.statusMessage = "Failed to create staging database: " + err.Error()
+			return
+		}
+	}
+
+	var updated, failed int
+	for _, upgrade := range m.httpsUpgrades {
+		bookmark := upgrade.Bookmark
+		if err := m.stagingDB.RepointBookmarkURL(bookmark.ID, upgrade.NewURL); err != nil {
+			failed++
+			if debugLog != nil {
+				debugLog.Printf("applyHTTPSUpgrades: bookmark %d: %v", bookmark.ID, err)
+			}
+			continue
+		}
+		if placeID, err := m.stagingDB.BookmarkPlaceID(bookmark.ID); err == nil {
+			bookmark.FK = &placeID
+		}
+		bookmark.URL = upgrade.NewURL
+		bookmark.LastModified = time.Now()
+
+		result := m.auditDetails[bookmark.ID]
+		result.Status = audit.StatusAlive
+		result.FinalURL = ""
+		m.auditDetails[bookmark.ID] = result
+		m.auditResults[bookmark.ID] = "OK"
+		m.auditCounts[audit.StatusRedirectHTTPS]--
+		m.auditCounts[audit.StatusAlive]++
+		updated++
+	}
+
+	m.httpsUpgrades = nil
+	if updated > 0 {
+		m.markChanged()
+	}
+
+	if failed > 0 {
+		m.statusMessage = fmt.Sprintf("⚠ Upgraded %d URLs to HTTPS, failed %d (Ctrl+S to commit)", updated, failed)
+	} else {
+		m.statusMessage = fmt.Sprintf("✓ Upgraded %d URLs to HTTPS (Ctrl+S to commit)", updated)
+	}
+}
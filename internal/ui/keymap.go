@@ -0,0 +1,345 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// keyMap holds the main screen's bindings. Update matches keys against
+// these and the help overlay and help line are generated from them, so a
+// key can't change in one place without the other.
+type keyMap struct {
+	Down, Up, Top, Bottom                       key.Binding
+	HalfPageDown, HalfPageUp, PageDown, PageUp  key.Binding
+	Toggle, CollapseAll, ExpandAll, SwitchPane  key.Binding
+	FolderSwitch, Back, Forward, Search         key.Binding
+	Edit, New, Separator, MoveDown, MoveUp      key.Binding
+	Mark, MarkAll, InvertMarks, Delete, CopyURL key.Binding
+	Open, FetchTitle, UseTitle, Stash, Clone    key.Binding
+	Trash, Restore, DeadOnly, Columns           key.Binding
+	RenameFolder, DeleteFolder, SortFolders     key.Binding
+	Scratch, JumpScratch, BulkMove, Leave       key.Binding
+	Export, Replace, Import, Audit, AuditFolder key.Binding
+	Reaudit, Dedup, EmptyFolders, FolderDups    key.Binding
+	Stats, History, Inspector, Theme            key.Binding
+	Help, Commit, Quit, ForceQuit               key.Binding
+}
+
+func newBinding(help, desc string, keyNames ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keyNames...), key.WithHelp(help, desc))
+}
+
+// helpOnly describes a key handled by one of the other screens, which
+// match on their own.
+func helpOnly(help, desc string) key.Binding {
+	return key.NewBinding(key.WithHelp(help, desc))
+}
+
+// keys are the main screen's bindings. Where two keys share a line in the
+// help, like j/k, the first carries the help text.
+var keys = keyMap{
+	Down:         newBinding("j/k", "Move the cursor (arrow keys work too)", "j", "down"),
+	Up:           newBinding("k", "Move the cursor up", "k", "up"),
+	Top:          newBinding("g/G", "Jump to the first/last item (Home/End work too)", "g", "home"),
+	Bottom:       newBinding("G", "Jump to the last item", "G", "end"),
+	HalfPageDown: newBinding("Ctrl+D/U", "Move down/up half a page (PgDn/PgUp: a full page)", "ctrl+d"),
+	HalfPageUp:   newBinding("Ctrl+U", "Move up half a page", "ctrl+u"),
+	PageDown:     newBinding("PgDn", "Move down a page", "pgdown"),
+	PageUp:       newBinding("PgUp", "Move up a page", "pgup"),
+	Toggle:       newBinding("Space/Enter", "Expand or collapse a folder (tree pane); under Tags, list every bookmark with that tag", "enter", " "),
+	CollapseAll:  newBinding("z/Z", "Collapse/expand all folders", "z"),
+	ExpandAll:    newBinding("Z", "Expand all folders", "Z"),
+	SwitchPane:   newBinding("Tab", "Switch between the panes", "tab"),
+	FolderSwitch: newBinding("Ctrl+G", "Jump to a folder by typing part of its path", "ctrl+g"),
+	Back:         newBinding("Bksp/[ ]", "Back/forward through recently viewed folders", "backspace", "ctrl+o", "["),
+	Forward:      newBinding("]", "Forward through recently viewed folders", "]"),
+	Search:       newBinding("/", "Search titles, descriptions, tags and URLs; tag:name filters by tag", "/"),
+
+	Edit:        newBinding("e", "Edit the highlighted bookmark", "e"),
+	New:         newBinding("n", "Add a bookmark to the current folder", "n"),
+	Separator:   newBinding("-", "Insert a separator below the cursor", "-"),
+	MoveDown:    newBinding("J/K", "Move the highlighted bookmark down/up", "J", "shift+down"),
+	MoveUp:      newBinding("K", "Move the highlighted bookmark up", "K", "shift+up"),
+	Mark:        newBinding("m", "Mark for batch operations", "m"),
+	MarkAll:     newBinding("A", "Mark everything listed (folder or search results)", "A"),
+	InvertMarks: newBinding("V", "Invert the marks on everything listed", "V"),
+	Delete:      newBinding("d", "Move the marked bookmarks (from any folder) to the trash", "d"),
+	CopyURL:     newBinding("y", "Copy the highlighted URL", "y"),
+	Open:        newBinding("O", "Open marked bookmarks (or all listed) in the browser", "O"),
+	FetchTitle:  newBinding("p/P", "Fetch the page's title / use it as the title", "p"),
+	UseTitle:    newBinding("P", "Use the fetched page title", "P"),
+	Stash:       newBinding("t", "Stash marked bookmarks in Scratch", "t"),
+	Clone:       newBinding("c", "Clone the highlighted bookmark into another folder", "c"),
+	Trash:       newBinding("X", "Open the trash (in the trash: empty it)", "X"),
+	Restore:     newBinding("u", "In the trash, restore to the original folder; in ☠ Dead Links, put archived links back", "u"),
+	DeadOnly:    newBinding("f", "Show only dead links (after an audit)", "f"),
+	Columns:     newBinding("v", "Toggle visit count and date columns", "v"),
+
+	RenameFolder: newBinding("e", "Rename the highlighted folder", "e"),
+	DeleteFolder: newBinding("d", "Delete the folder and everything in it", "d"),
+	SortFolders:  newBinding("o", "Sort folders by name or saved order (display only)", "o"),
+
+	Scratch:     newBinding("s", "Quick add a link to Scratch", "s"),
+	JumpScratch: newBinding("S", "Jump to the Scratch folder", "S"),
+	BulkMove:    newBinding("b", "Bulk move the marked bookmarks (in Scratch or Orphaned)", "b"),
+	Leave:       newBinding("Esc", "Leave search results, or Scratch for the Bookmarks Bar", "esc"),
+
+	Export:       newBinding("x", "Export bookmarks", "x"),
+	Replace:      newBinding("F", "Find and replace in URLs", "F"),
+	Import:       newBinding("I", "Import a Chrome/Chromium Bookmarks file", "I"),
+	Audit:        newBinding("a", "Audit links", "a"),
+	AuditFolder:  newBinding("Ctrl+A", "Audit only the current folder and its subfolders", "ctrl+a"),
+	Reaudit:      newBinding("R", "Re-audit only the dead links", "R"),
+	Dedup:        newBinding("D", "Find duplicate bookmarks", "D"),
+	EmptyFolders: newBinding("E", "Find empty folders", "E"),
+	FolderDups:   newBinding("C", "Find and merge folders with the same title", "C"),
+	Stats:        newBinding("#", "Statistics: collection totals and bookmarks added per year", "#"),
+	History:      newBinding("W", "Pages visited often but never bookmarked, to add to the current folder", "W"),
+	Inspector:    newBinding("i", "Toggle the inspector", "i"),
+	Theme:        newBinding("T", "Cycle color themes", "T"),
+
+	Help:      newBinding("?", "Show or hide this help", "?"),
+	Commit:    newBinding("Ctrl+S", "Commit staged changes, asking first if the trash isn't empty (browser must be closed)", "ctrl+s"),
+	Quit:      newBinding("q", "Quit (with staged changes: commit, discard or keep editing)", "q"),
+	ForceQuit: newBinding("Q Ctrl+C", "Quit without saving (or cancel loading)", "Q", "ctrl+c"),
+}
+
+type keyGroup struct {
+	Name     string
+	Bindings []key.Binding
+}
+
+// keymap is what the help overlay is generated from: the main screen's
+// bindings first, then the keys of the other screens.
+var keymap = []keyGroup{
+	{
+		Name: "Navigation",
+		Bindings: []key.Binding{
+			keys.Down, keys.Top, keys.HalfPageDown, keys.Toggle, keys.CollapseAll,
+			keys.SwitchPane, keys.FolderSwitch, keys.Back, keys.Search,
+		},
+	},
+	{
+		Name: "Bookmarks pane",
+		Bindings: []key.Binding{
+			keys.Edit, keys.New, keys.Separator, keys.MoveDown, keys.Mark, keys.MarkAll,
+			keys.InvertMarks, keys.Delete, keys.CopyURL, keys.Open, keys.FetchTitle,
+			keys.Stash, keys.Clone, keys.Trash, keys.Restore, keys.DeadOnly, keys.Columns,
+		},
+	},
+	{
+		Name:     "Folders pane",
+		Bindings: []key.Binding{keys.RenameFolder, keys.DeleteFolder, keys.SortFolders},
+	},
+	{
+		Name:     "Scratch",
+		Bindings: []key.Binding{keys.Scratch, keys.JumpScratch, keys.BulkMove, keys.Leave},
+	},
+	{
+		Name: "Tools",
+		Bindings: []key.Binding{
+			keys.Export, keys.Replace, keys.Import, keys.Audit, keys.AuditFolder, keys.Reaudit,
+			keys.Dedup, keys.EmptyFolders, keys.FolderDups, keys.Stats, keys.History,
+			keys.Inspector, keys.Theme,
+		},
+	},
+	{
+		Name:     "General",
+		Bindings: []key.Binding{keys.Help, keys.Commit, keys.Quit, keys.ForceQuit},
+	},
+	{
+		Name: "Edit, add and search forms",
+		Bindings: []key.Binding{
+			helpOnly("Enter", "Save (search: show the results)"),
+			helpOnly("Esc", "Cancel"),
+			helpOnly("↑/↓ Ctrl+P/N", "Pick a match when jumping with Ctrl+G"),
+		},
+	},
+	{
+		Name: "Export",
+		Bindings: []key.Binding{
+			helpOnly("j/l", "JSON / JSON Lines"),
+			helpOnly("h/m/o", "HTML / Markdown / OPML"),
+			helpOnly("v", "Searchable HTML page for browsing"),
+			helpOnly("s", "Only the marked bookmarks"),
+			helpOnly("f", "All, live or dead links (after an audit)"),
+			helpOnly("y/Enter", "In the preview, write the file"),
+			helpOnly("Esc", "Cancel (in the preview: back to formats)"),
+		},
+	},
+	{
+		Name: "Find and replace",
+		Bindings: []key.Binding{
+			helpOnly("Tab", "Switch between find and replace"),
+			helpOnly("Ctrl+R", "Toggle regex matching"),
+			helpOnly("Enter", "Preview, then stage the changes"),
+			helpOnly("Space/m", "Include or skip a change (preview)"),
+			helpOnly("Esc", "Back / cancel"),
+		},
+	},
+	{
+		Name: "Audit results",
+		Bindings: []key.Binding{
+			helpOnly("Esc", "Cancel a running audit"),
+			helpOnly("j/k", "Move between dead and parked links"),
+			helpOnly("Enter", "Jump to the link"),
+			helpOnly("c", "Compare with the previous audit"),
+			helpOnly("w", "Save every result to a JSON report"),
+			helpOnly("H", "Stage every same-page HTTPS upgrade"),
+			helpOnly("A", "Move every dead link to the ☠ Dead Links folder"),
+		},
+	},
+	{
+		Name: "Duplicates and empty folders",
+		Bindings: []key.Binding{
+			helpOnly("Enter", "Open a duplicate group"),
+			helpOnly("s", "Show only duplicates within one folder"),
+			helpOnly("j/k", "Choose the bookmark to keep"),
+			helpOnly("d", "Delete the others (empty folders: delete all)"),
+			helpOnly("Enter/m", "Duplicate folders: merge into the first"),
+			helpOnly("a", "Duplicate folders: match anywhere or same parent"),
+			helpOnly("M", "Delete the others, merging their history"),
+			helpOnly("Esc", "Back to the group list"),
+		},
+	},
+	{
+		Name: "Visited but not bookmarked",
+		Bindings: []key.Binding{
+			helpOnly("Space/m", "Mark a page"),
+			helpOnly("Enter", "Bookmark the marked pages, or the highlighted one"),
+		},
+	},
+	{
+		Name: "Confirmations",
+		Bindings: []key.Binding{
+			helpOnly("y", "Confirm deleting, emptying the trash, committing, opening tabs, upgrading to HTTPS or archiving dead links"),
+			helpOnly("Y", "Confirm deleting a folder"),
+			helpOnly("n/Esc", "Cancel"),
+		},
+	},
+}
+
+// shortHelpKeys are the bindings on the help line at the bottom of the
+// screen, each with the shorter label used there.
+var shortHelpKeys = []struct {
+	binding key.Binding
+	label   string
+}{
+	{keys.Down, "nav"},
+	{keys.Toggle, "toggle"},
+	{keys.CollapseAll, "collapse/expand all"},
+	{keys.SwitchPane, "switch"},
+	{keys.Search, "search"},
+	{keys.Edit, "edit"},
+	{keys.New, "new"},
+	{keys.Mark, "mark"},
+	{keys.CopyURL, "copy URL"},
+	{keys.Scratch, "scratch"},
+	{keys.JumpScratch, "jump"},
+	{keys.Export, "export"},
+	{keys.Audit, "audit"},
+	{keys.Dedup, "dedup"},
+	{keys.Inspector, "inspector"},
+	{keys.Help, "help"},
+}
+
+// shortHelp joins the bindings marked for the help line.
+func shortHelp() string {
+	var parts []string
+	for _, short := range shortHelpKeys {
+		parts = append(parts, short.binding.Help().Key+": "+short.label)
+	}
+	return strings.Join(parts, " | ")
+}
+
+// helpColumns renders the keymap as one or more columns of groups that fit
+// in width.
+func helpColumns(width int) []string {
+	keyWidth := 0
+	for _, group := range keymap {
+		for _, binding := range group.Bindings {
+			keyWidth = max(keyWidth, lipgloss.Width(binding.Help().Key))
+		}
+	}
+
+	var blocks [][]string
+	total := 0
+	columnWidth := 0
+	for _, group := range keymap {
+		block := []string{titleStyle.Render(group.Name)}
+		for _, binding := range group.Bindings {
+			line := selectedItemStyle.Render(padRight(binding.Help().Key, keyWidth)) + "  " + normalItemStyle.Render(binding.Help().Desc)
+			columnWidth = max(columnWidth, lipgloss.Width(line))
+			block = append(block, line)
+		}
+		block = append(block, "")
+		blocks = append(blocks, block)
+		total += len(block)
+	}
+
+	numColumns := 1
+	if width >= 2*columnWidth+2 {
+		numColumns = 2
+	}
+
+	// Fill columns in keymap order, moving on once a column holds its
+	// share of the lines.
+	columns := make([][]string, numColumns)
+	perColumn := (total + numColumns - 1) / numColumns
+	col := 0
+	for _, block := range blocks {
+		if col < numColumns-1 && len(columns[col]) > 0 && len(columns[col])+len(block)/2 > perColumn {
+			col++
+		}
+		columns[col] = append(columns[col], block...)
+	}
+
+	rendered := make([]string, numColumns)
+	for i, column := range columns {
+		rendered[i] = lipgloss.NewStyle().Width(columnWidth + 2).Render(strings.Join(column, "\n"))
+	}
+	lines := strings.Split(lipgloss.JoinHorizontal(lipgloss.Top, rendered...), "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func padRight(s string, width int) string {
+	if gap := width - lipgloss.Width(s); gap > 0 {
+		return s + strings.Repeat(" ", gap)
+	}
+	return s
+}
+
+// renderHelpOverlay draws the keymap full screen, scrolled by helpScroll.
+func (m *Model) renderHelpOverlay() string {
+	// The border takes two columns and rows, the padding two more columns.
+	contentWidth := m.width - 4
+	contentHeight := m.height - 2
+
+	lines := helpColumns(contentWidth)
+	bodyHeight := max(contentHeight-2, 1)
+	maxScroll := max(len(lines)-bodyHeight, 0)
+	m.helpScroll = min(m.helpScroll, maxScroll)
+	lines = lines[m.helpScroll:min(m.helpScroll+bodyHeight, len(lines))]
+
+	footer := "?/Esc: close"
+	if maxScroll > 0 {
+		footer = "j/k: scroll | " + footer
+	}
+
+	content := lipgloss.JoinVertical(
+		lipgloss.Left,
+		titleStyle.Render("Keybindings"),
+		lipgloss.NewStyle().MaxWidth(contentWidth).Height(bodyHeight).Render(strings.Join(lines, "\n")),
+		dimStyle.Render(footer),
+	)
+
+	return activePaneStyle.
+		Width(m.width - 2).
+		Height(contentHeight).
+		Render(content)
+}
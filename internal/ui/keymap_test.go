@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/levineuwirth/gophermark/internal/db"
+	"github.com/levineuwirth/gophermark/internal/models"
+)
+
+func newKeyTestModel(t *testing.T) *Model {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	toolbar := &models.Bookmark{ID: 3, Type: models.TypeFolder, Parent: 1, Title: "toolbar", GUID: "toolbar_____"}
+	for i := range 3 {
+		toolbar.Children = append(toolbar.Children, &models.Bookmark{
+			ID: int64(10 + i), Type: models.TypeBookmark, Parent: 3, Position: i,
+			Title: "Example", URL: "https://example.com/",
+		})
+	}
+	root := &models.Bookmark{ID: 1, Type: models.TypeFolder, GUID: "root________", Children: []*models.Bookmark{toolbar}}
+
+	m := NewModel(root, db.GetFolders(root), "")
+	m.activePane = ListPane
+	return m
+}
+
+func press(m *Model, msgs ...tea.KeyMsg) {
+	for _, msg := range msgs {
+		m.Update(msg)
+	}
+}
+
+func TestJumpKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		msgs []tea.KeyMsg
+		want int
+	}{
+		{"G", []tea.KeyMsg{{Type: tea.KeyRunes, Runes: []rune("G")}}, 2},
+		{"G then g", []tea.KeyMsg{{Type: tea.KeyRunes, Runes: []rune("G")}, {Type: tea.KeyRunes, Runes: []rune("g")}}, 0},
+		{"End then Home", []tea.KeyMsg{{Type: tea.KeyEnd}, {Type: tea.KeyHome}}, 0},
+		{"End", []tea.KeyMsg{{Type: tea.KeyEnd}}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newKeyTestModel(t)
+			press(m, tt.msgs...)
+			if m.listCursor != tt.want {
+				t.Fatalf("list cursor = %d, want %d", m.listCursor, tt.want)
+			}
+			if m.editMode != EditNone {
+				t.Fatalf("edit mode = %d, want none", m.editMode)
+			}
+		})
+	}
+}
+
+func TestFolderSwitchKey(t *testing.T) {
+	m := newKeyTestModel(t)
+	press(m, tea.KeyMsg{Type: tea.KeyCtrlG})
+	if m.editMode != FolderSwitchMode {
+		t.Fatalf("edit mode after Ctrl+G = %d, want the folder switcher", m.editMode)
+	}
+}
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/levineuwirth/gophermark/internal/db"
+	"github.com/levineuwirth/gophermark/internal/models"
+)
+
+type dataLoadedMsg struct {
+	root    *models.Bookmark
+	folders []*models.Bookmark
+	orphans int
+	err     error
+}
+
+type loadTickMsg struct{}
+
+// NewLoadingModel is NewModel for bookmarks that haven't been read yet: Init
+// reads them from conn in the background, showing a spinner meanwhile. The
+// model takes conn over and closes it once the bookmarks are read, or the
+// load is cancelled.
+func NewLoadingModel(conn *db.DB, dbPath string) *Model {
+	m := newModel(dbPath)
+	m.loadConn = conn
+	m.loading = true
+	return m
+}
+
+// loadData reads and builds the tree off the UI goroutine. Closing the
+// connection here, rather than when the program quits, makes sure a
+// snapshot copy is removed even if the load is cancelled.
+func (m *Model) loadData() tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.loadCancel = cancel
+	conn := m.loadConn
+	m.loadConn = nil
+
+	return func() tea.Msg {
+		defer conn.Close()
+
+		bookmarks, err := conn.FetchAllBookmarksContext(ctx)
+		if err != nil {
+			return dataLoadedMsg{err: fmt.Errorf("failed to fetch bookmarks: %w", err)}
+		}
+
+		root, orphans, err := db.BuildTreeWithOrphans(bookmarks)
+		if err != nil {
+			return dataLoadedMsg{err: fmt.Errorf("failed to build bookmark tree: %w", err)}
+		}
+
+		return dataLoadedMsg{root: root, folders: db.GetFolders(root), orphans: orphans}
+	}
+}
+
+func (m *Model) tickLoad() tea.Cmd {
+	return tea.Tick(100*time.Millisecond, func(time.Time) tea.Msg {
+		return loadTickMsg{}
+	})
+}
+
+// cancelLoad stops a load in progress. The program quits once loadData
+// has returned and closed the connection.
+func (m *Model) cancelLoad() {
+	if m.loadCancelled {
+		return
+	}
+	m.loadCancelled = true
+	m.loadCancel()
+}
+
+func (m *Model) renderLoading() string {
+	spinnerFrames := []string{"⠋", "⠙", "⠹", "⠸"}
+	if m.loadCancelled {
+		return spinnerFrames[m.scanSpinner] + " Cancelling...\n"
+	}
+	return fmt.Sprintf("%s Loading bookmarks from %s... (Ctrl+C to cancel)\n", spinnerFrames[m.scanSpinner], m.dbPath)
+}
@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"runtime"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/levineuwirth/gophermark/internal/models"
+)
+
+// confirmOpenThreshold is how many tabs can be opened without asking first.
+const confirmOpenThreshold = 10
+
+type urlsOpenedMsg struct {
+	opened int
+	err    error
+}
+
+// openURL hands rawURL to the system's default handler, which opens web
+// links in the default browser.
+func openURL(rawURL string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", rawURL)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", rawURL)
+	default:
+		cmd = exec.Command("xdg-open", rawURL)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// openableURLs returns the http(s) URLs among bookmarks and how many were
+// skipped for having another scheme.
+func openableURLs(bookmarks []*models.Bookmark) (urls []string, skipped int) {
+	for _, bookmark := range bookmarks {
+		if !bookmark.IsBookmark() {
+			continue
+		}
+		u, err := url.Parse(bookmark.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			skipped++
+			continue
+		}
+		urls = append(urls, bookmark.URL)
+	}
+	return urls, skipped
+}
+
+// openURLsCmd launches urls one after another, in list order, so tabs open
+// in the order they're listed; launching them concurrently would shuffle
+// them.
+func openURLsCmd(urls []string) tea.Cmd {
+	return func() tea.Msg {
+		for i, u := range urls {
+			if err := openURL(u); err != nil {
+				return urlsOpenedMsg{opened: i, err: fmt.Errorf("failed to open %s: %w", u, err)}
+			}
+		}
+		return urlsOpenedMsg{opened: len(urls)}
+	}
+}
+
+// startOpenURLs opens the marked bookmarks, or every bookmark in the list
+// pane if none are marked, asking first when that's a lot of tabs.
+func (m *Model) startOpenURLs() tea.Cmd {
+	bookmarks := m.listBookmarks()
+	if len(m.selectedBookmarks) > 0 {
+		bookmarks = m.selectedBookmarkList()
+	}
+
+	urls, skipped := openableURLs(bookmarks)
+	m.openSkipped = skipped
+	if len(urls) == 0 {
+		m.statusMessage = "No web links to open"
+		return nil
+	}
+
+	m.openURLs = urls
+	if len(urls) > confirmOpenThreshold {
+		m.editMode = ConfirmOpen
+		m.statusMessage = fmt.Sprintf("Open %d tabs?", len(urls))
+		return nil
+	}
+	return m.openPendingURLs()
+}
+
+func (m *Model) openPendingURLs() tea.Cmd {
+	urls := m.openURLs
+	m.openURLs = nil
+	m.statusMessage = fmt.Sprintf("Opening %d links...", len(urls))
+	return openURLsCmd(urls)
+}
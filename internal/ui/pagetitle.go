@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pageTitle is the <title> fetched for one bookmark, shown in the inspector
+// while that bookmark is highlighted.
+type pageTitle struct {
+	bookmarkID int64
+	title      string
+	err        error
+	fetching   bool
+}
+
+type pageTitleMsg struct {
+	bookmarkID int64
+	title      string
+	err        error
+}
+
+// fetchPageTitle looks up the highlighted bookmark's page title in the
+// background, with the audit timeout and request settings.
+func (m *Model) fetchPageTitle() tea.Cmd {
+	bookmark := m.selectedBookmark()
+	if bookmark == nil || !bookmark.IsBookmark() {
+		return nil
+	}
+	u, err := url.Parse(bookmark.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		m.statusMessage = "⚠ Only web pages have a title to fetch"
+		return nil
+	}
+
+	m.pageTitle = pageTitle{bookmarkID: bookmark.ID, fetching: true}
+	if !m.showInspector {
+		m.toggleInspector()
+	}
+	m.statusMessage = "Fetching page title..."
+
+	auditor := m.newAuditor(false)
+	id, pageURL := bookmark.ID, bookmark.URL
+	return func() tea.Msg {
+		title, err := auditor.FetchTitle(context.Background(), pageURL)
+		return pageTitleMsg{bookmarkID: id, title: title, err: err}
+	}
+}
+
+// applyPageTitle renames the highlighted bookmark to its fetched page title.
+func (m *Model) applyPageTitle() {
+	bookmark := m.selectedBookmark()
+	if bookmark == nil || m.pageTitle.bookmarkID != bookmark.ID || m.pageTitle.title == "" {
+		m.statusMessage = "⚠ Fetch the page title with p first"
+		return
+	}
+	if m.pageTitle.title == bookmark.Title {
+		m.statusMessage = "The title already matches the page"
+		return
+	}
+
+	if m.stagingDB == nil {
+		var err error
+		m.stagingDB, err = m.createStaging()
+		if err != nil {
+			m.statusMessage = "Failed to create staging database: " + err.Error()
+			return
+		}
+	}
+
+	if err := m.stagingDB.UpdateBookmarkTitle(bookmark.ID, m.pageTitle.title); err != nil {
+		m.statusMessage = "Failed to update title: " + err.Error()
+		return
+	}
+	bookmark.Title = m.pageTitle.title
+	m.markChanged()
+	m.statusMessage = fmt.Sprintf("✓ Renamed to %q (Ctrl+S to commit)", bookmark.Title)
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/levineuwirth/gophermark/internal/models"
+)
+
+type URLReplacement struct {
+	Bookmark *models.Bookmark
+	NewURL   string
+	Selected bool
+	Err      error // set when NewURL isn't a valid URL; such matches start deselected
+}
+
+// FindURLReplacements returns every bookmark under root whose URL changes
+// when find is replaced with replace. With useRegex, find is a Go regular
+// expression and replace may refer to groups as $1.
+func FindURLReplacements(root *models.Bookmark, find, replace string, useRegex bool) ([]URLReplacement, error) {
+	if find == "" {
+		return nil, fmt.Errorf("nothing to find")
+	}
+
+	rewrite := func(url string) string {
+		return strings.ReplaceAll(url, find, replace)
+	}
+	if useRegex {
+		re, err := regexp.Compile(find)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex: %w", err)
+		}
+		rewrite = func(url string) string {
+			return re.ReplaceAllString(url, replace)
+		}
+	}
+
+	var replacements []URLReplacement
+	for _, bookmark := range collectAllBookmarks(root) {
+		if bookmark.URL == "" || bookmark.FK == nil {
+			continue
+		}
+		newURL := rewrite(bookmark.URL)
+		if newURL == bookmark.URL {
+			continue
+		}
+
+		_, err := validateURL(newURL)
+		replacements = append(replacements, URLReplacement{
+			Bookmark: bookmark,
+			NewURL:   newURL,
+			Selected: err == nil,
+			Err:      err,
+		})
+	}
+
+	return replacements, nil
+}
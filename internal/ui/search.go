@@ -1,110 +1,89 @@
 package ui
 
 import (
-	"strings"
+	"sort"
 
+	"github.com/levineuwirth/gophermark/internal/db"
+	"github.com/levineuwirth/gophermark/internal/fuzzy"
 	"github.com/levineuwirth/gophermark/internal/models"
+	"github.com/mattn/go-runewidth"
 )
 
-func levenshteinDistance(s1, s2 string) int {
-	s1 = strings.ToLower(s1)
-	s2 = strings.ToLower(s2)
+type FolderMatch struct {
+	Folder *models.Bookmark
+	Path   string
 
-	if len(s1) == 0 {
-		return len(s2)
-	}
-	if len(s2) == 0 {
-		return len(s1)
-	}
-
-	matrix := make([][]int, len(s1)+1)
-	for i := range matrix {
-		matrix[i] = make([]int, len(s2)+1)
-	}
+	score int
+}
 
-	for i := 0; i <= len(s1); i++ {
-		matrix[i][0] = i
-	}
-	for j := 0; j <= len(s2); j++ {
-		matrix[0][j] = j
-	}
+// SearchFolders matches query against the full path of every titled folder,
+// ranked like search.BookmarksWithPaths. An empty query returns every folder
+// in tree order.
+func SearchFolders(root *models.Bookmark, query string) []FolderMatch {
+	parents := db.FolderPaths(root)
 
-	for i := 1; i <= len(s1); i++ {
-		for j := 1; j <= len(s2); j++ {
-			cost := 1
-			if s1[i-1] == s2[j-1] {
-				cost = 0
-			}
-
-			matrix[i][j] = min(
-				matrix[i-1][j]+1,      // deletion
-				matrix[i][j-1]+1,      // insertion
-				matrix[i-1][j-1]+cost, // substitution
-			)
+	var matches []FolderMatch
+	for _, folder := range db.GetFolders(root) {
+		if folder.Title == "" {
+			continue
 		}
-	}
-
-	return matrix[len(s1)][len(s2)]
-}
 
-func min(a, b, c int) int {
-	if a < b {
-		if a < c {
-			return a
+		path := folder.Title
+		if parent := parents[folder.ID]; parent != "" {
+			path = parent + " / " + folder.Title
 		}
-		return c
-	}
-	if b < c {
-		return b
-	}
-	return c
-}
 
-func fuzzyMatch(query, text string) int {
-	query = strings.ToLower(query)
-	text = strings.ToLower(text)
+		score := 0
+		if query != "" {
+			// Prefer hits on the folder's own name over hits on its parents.
+			score = fuzzy.Match(query, folder.Title)
+			if score < 0 {
+				if score = fuzzy.Match(query, path); score >= 0 {
+					score++
+				}
+			}
+			if score < 0 {
+				continue
+			}
+		}
 
-	if strings.Contains(text, query) {
-		return 0
+		matches = append(matches, FolderMatch{Folder: folder, Path: path, score: score})
 	}
 
-	distance := levenshteinDistance(query, text)
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score < matches[j].score
+	})
+	return matches
+}
 
-	threshold := len(query) / 2
-	if threshold < 2 {
-		threshold = 2
+// truncatePathLeft keeps the innermost folders, which are the most useful
+// part of a path when space runs out. Like truncateDisplay, it measures
+// terminal columns.
+func truncatePathLeft(path string, width int) string {
+	if runewidth.StringWidth(path) <= width {
+		return path
 	}
-
-	if distance <= threshold {
-		return distance
+	if width <= 1 {
+		return ""
 	}
-
-	return -1
+	runes := []rune(path)
+	start, used := len(runes), 1 // the ellipsis
+	for start > 0 && used+runewidth.RuneWidth(runes[start-1]) <= width {
+		start--
+		used += runewidth.RuneWidth(runes[start])
+	}
+	return "…" + string(runes[start:])
 }
 
-func SearchBookmarks(root *models.Bookmark, query string) []*models.Bookmark {
-	if query == "" {
-		return nil
+// truncateDisplay shortens s to fit in width terminal columns, ending it with
+// an ellipsis. Wide characters such as CJK and emoji take two columns and
+// are never split.
+func truncateDisplay(s string, width int) string {
+	if runewidth.StringWidth(s) <= width {
+		return s
 	}
-
-	var results []*models.Bookmark
-
-	var search func(*models.Bookmark)
-	search = func(node *models.Bookmark) {
-		if node.IsBookmark() {
-			titleScore := fuzzyMatch(query, node.Title)
-			urlScore := fuzzyMatch(query, node.URL)
-
-			if titleScore >= 0 || urlScore >= 0 {
-				results = append(results, node)
-			}
-		}
-
-		for _, child := range node.Children {
-			search(child)
-		}
+	if width <= 1 {
+		return ""
 	}
-
-	search(root)
-	return results
+	return runewidth.Truncate(s, width, "…")
 }
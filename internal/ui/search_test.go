@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/mattn/go-runewidth"
+)
+
+func TestTruncateDisplay(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		width int
+		want  string
+	}{
+		{"fits", "hello", 10, "hello"},
+		{"exact fit", "日本", 4, "日本"},
+		{"ascii", "hello world", 8, "hello w…"},
+		{"cjk on the cut", "日本語のタイトル", 8, "日本語…"},
+		{"cjk before the cut", "日本語のタイトル", 7, "日本語…"},
+		{"emoji on the cut", "🎉🎉🎉 party", 4, "🎉…"},
+		{"mixed", "Go 言語 🎉", 6, "Go 言…"},
+		{"width 1", "日本語", 1, ""},
+		{"width 0", "hello", 0, ""},
+		{"negative width", "hello", -3, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateDisplay(tt.s, tt.width)
+			if got != tt.want {
+				t.Fatalf("truncateDisplay(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+			}
+			if w := runewidth.StringWidth(got); w > max(tt.width, 0) {
+				t.Fatalf("truncateDisplay(%q, %d) is %d columns wide", tt.s, tt.width, w)
+			}
+		})
+	}
+}
+
+func TestTruncatePathLeft(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  string
+		width int
+		want  string
+	}{
+		{"fits", "Work / Docs", 20, "Work / Docs"},
+		{"ascii", "Bookmarks Toolbar / Work / Docs", 10, "…rk / Docs"},
+		{"cjk exact", "メニュー / 日本語", 7, "…日本語"},
+		{"cjk on the cut", "メニュー / 日本語", 6, "…本語"},
+		{"emoji on the cut", "Reading / 📚📚", 4, "…📚"},
+		{"width 1", "メニュー / 日本語", 1, ""},
+		{"width 0", "Work / Docs", 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncatePathLeft(tt.path, tt.width)
+			if got != tt.want {
+				t.Fatalf("truncatePathLeft(%q, %d) = %q, want %q", tt.path, tt.width, got, tt.want)
+			}
+			if w := runewidth.StringWidth(got); w > max(tt.width, 0) {
+				t.Fatalf("truncatePathLeft(%q, %d) is %d columns wide", tt.path, tt.width, w)
+			}
+		})
+	}
+}
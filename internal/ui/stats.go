@@ -0,0 +1,191 @@
+package ui
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/levineuwirth/gophermark/internal/models"
+)
+
+// maxStatsBarWidth is how wide the bar for the busiest year is drawn.
+const maxStatsBarWidth = 30
+
+// yearCount is how many bookmarks were added in one year.
+type yearCount struct {
+	Year  int
+	Count int
+}
+
+// collectionStats summarises the whole tree for the statistics screen.
+type collectionStats struct {
+	Bookmarks int
+	Folders   int
+	MaxDepth  int // folder nesting below the root; top-level folders are 1
+
+	Largest      *models.Bookmark // folder holding the most bookmarks itself
+	LargestCount int
+
+	Untitled int
+	NonWeb   int // bookmarks whose URL isn't http or https
+
+	Oldest, Newest time.Time
+
+	Years   []yearCount // oldest first, with empty years filled in
+	Undated int
+}
+
+// computeStats gathers collectionStats in one walk over root. The tags
+// folder only repeats bookmarks filed elsewhere, so it's skipped.
+func computeStats(root *models.Bookmark) collectionStats {
+	var stats collectionStats
+	counts := make(map[int]int)
+
+	var walk func(folder *models.Bookmark, depth int)
+	walk = func(folder *models.Bookmark, depth int) {
+		direct := 0
+		for _, child := range folder.Children {
+			switch {
+			case child.IsFolder():
+				if child.GUID == "tags________" {
+					continue
+				}
+				stats.Folders++
+				stats.MaxDepth = max(stats.MaxDepth, depth+1)
+				walk(child, depth+1)
+			case child.IsBookmark():
+				direct++
+				stats.Bookmarks++
+				if strings.TrimSpace(child.Title) == "" {
+					stats.Untitled++
+				}
+				if u, err := url.Parse(child.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+					stats.NonWeb++
+				}
+
+				added := child.DateAdded
+				if added.IsZero() || added.Unix() <= 0 {
+					stats.Undated++
+					continue
+				}
+				counts[added.Year()]++
+				if stats.Oldest.IsZero() || added.Before(stats.Oldest) {
+					stats.Oldest = added
+				}
+				if added.After(stats.Newest) {
+					stats.Newest = added
+				}
+			}
+		}
+		if folder != root && direct > stats.LargestCount {
+			stats.Largest = folder
+			stats.LargestCount = direct
+		}
+	}
+	walk(root, 0)
+
+	if !stats.Oldest.IsZero() {
+		for year := stats.Oldest.Year(); year <= stats.Newest.Year(); year++ {
+			stats.Years = append(stats.Years, yearCount{Year: year, Count: counts[year]})
+		}
+	}
+	return stats
+}
+
+func (m *Model) enterStatsMode() {
+	m.stats = computeStats(m.root)
+	m.editMode = StatsMode
+	m.statusMessage = ""
+}
+
+func (m *Model) renderStats(maxHeight int) string {
+	var lines []string
+	lines = append(lines, folderStyle.Render("📊 Statistics"))
+	lines = append(lines, "")
+	overview := m.renderStatsOverview()
+	lines = append(lines, overview...)
+	lines = append(lines, "")
+	lines = append(lines, m.renderYearChart(maxHeight-5-len(overview))...)
+	lines = append(lines, "")
+	lines = append(lines, dimStyle.Render("Press any key to close"))
+	return strings.Join(lines, "\n")
+}
+
+// renderStatsOverview lists the totals as label and value columns.
+func (m *Model) renderStatsOverview() []string {
+	stats := m.stats
+	date := func(t time.Time) string {
+		if t.IsZero() {
+			return "-"
+		}
+		return t.Format("2006-01-02")
+	}
+
+	largest := "-"
+	if stats.Largest != nil {
+		largest = fmt.Sprintf("%s (%d)", stats.Largest.DisplayTitle(), stats.LargestCount)
+	}
+
+	rows := [][2]string{
+		{"Bookmarks", fmt.Sprint(stats.Bookmarks)},
+		{"Folders", fmt.Sprint(stats.Folders)},
+		{"Deepest nesting", fmt.Sprintf("%d levels", stats.MaxDepth)},
+		{"Largest folder", largest},
+		{"Untitled", fmt.Sprint(stats.Untitled)},
+		{"Non-web links", fmt.Sprint(stats.NonWeb)},
+		{"Oldest", date(stats.Oldest)},
+		{"Newest", date(stats.Newest)},
+	}
+
+	labelWidth := 0
+	for _, row := range rows {
+		labelWidth = max(labelWidth, len(row[0]))
+	}
+	var lines []string
+	for _, row := range rows {
+		lines = append(lines, "  "+dimStyle.Render(padRight(row[0], labelWidth))+"  "+normalItemStyle.Render(row[1]))
+		if row[0] == "Largest folder" && stats.Largest != nil {
+			path := truncatePathLeft(folderBreadcrumb(m.root, stats.Largest), 50)
+			// normalItemStyle pads its left edge, so the path needs one more space.
+			lines = append(lines, "  "+strings.Repeat(" ", labelWidth)+"   "+dimStyle.Render(path))
+		}
+	}
+	return lines
+}
+
+// renderYearChart draws the per-year counts as one bar per year, keeping the most
+// recent years when they don't all fit in height rows.
+func (m *Model) renderYearChart(height int) []string {
+	lines := []string{normalItemStyle.Render("Bookmarks added per year:")}
+	if len(m.stats.Years) == 0 {
+		return append(lines, dimStyle.Render("  (no dated bookmarks)"))
+	}
+
+	most, countWidth := 0, 0
+	for _, year := range m.stats.Years {
+		most = max(most, year.Count)
+		countWidth = max(countWidth, len(fmt.Sprint(year.Count)))
+	}
+
+	years := m.stats.Years
+	rows := max(height-2, 1)
+	if len(years) > rows {
+		years = years[len(years)-rows:]
+	}
+
+	barStyle := lipgloss.NewStyle().Foreground(accentColor)
+	for _, year := range years {
+		width := year.Count * maxStatsBarWidth / most
+		if year.Count > 0 {
+			width = max(width, 1)
+		}
+		lines = append(lines, fmt.Sprintf("  %s %*d %s",
+			dimStyle.Render(fmt.Sprint(year.Year)), countWidth, year.Count, barStyle.Render(strings.Repeat("█", width))))
+	}
+	if m.stats.Undated > 0 {
+		lines = append(lines, dimStyle.Render(fmt.Sprintf("  %d without a date added", m.stats.Undated)))
+	}
+	return lines
+}
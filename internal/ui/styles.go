@@ -2,49 +2,145 @@ package ui
 
 import "github.com/charmbracelet/lipgloss"
 
+type Theme struct {
+	Name      string
+	Primary   lipgloss.TerminalColor
+	Secondary lipgloss.TerminalColor
+	Accent    lipgloss.TerminalColor
+	Text      lipgloss.TerminalColor
+	Dim       lipgloss.TerminalColor
+	Border    lipgloss.TerminalColor
+}
+
+// themes are cycled in this order; the first is the default.
+var themes = []Theme{
+	{
+		Name:      "default",
+		Primary:   lipgloss.Color("#7D56F4"),
+		Secondary: lipgloss.Color("#3C3C3C"),
+		Accent:    lipgloss.Color("#FF79C6"),
+		Text:      lipgloss.Color("#FAFAFA"),
+		Dim:       lipgloss.Color("#6C6C6C"),
+		Border:    lipgloss.Color("#383838"),
+	},
+	{
+		Name:      "dracula",
+		Primary:   lipgloss.Color("#BD93F9"),
+		Secondary: lipgloss.Color("#44475A"),
+		Accent:    lipgloss.Color("#50FA7B"),
+		Text:      lipgloss.Color("#F8F8F2"),
+		Dim:       lipgloss.Color("#6272A4"),
+		Border:    lipgloss.Color("#44475A"),
+	},
+	{
+		// Dark text for light terminal backgrounds.
+		Name:      "solarized-light",
+		Primary:   lipgloss.Color("#268BD2"),
+		Secondary: lipgloss.Color("#EEE8D5"),
+		Accent:    lipgloss.Color("#D33682"),
+		Text:      lipgloss.Color("#073642"),
+		Dim:       lipgloss.Color("#657B83"),
+		Border:    lipgloss.Color("#93A1A1"),
+	},
+	{
+		// Leaves every color to the terminal, so it works on any background.
+		Name:      "mono",
+		Primary:   lipgloss.NoColor{},
+		Secondary: lipgloss.NoColor{},
+		Accent:    lipgloss.NoColor{},
+		Text:      lipgloss.NoColor{},
+		Dim:       lipgloss.NoColor{},
+		Border:    lipgloss.NoColor{},
+	},
+	{
+		Name:      "high-contrast",
+		Primary:   lipgloss.Color("#FFFF00"),
+		Secondary: lipgloss.Color("#000000"),
+		Accent:    lipgloss.Color("#00FFFF"),
+		Text:      lipgloss.Color("#FFFFFF"),
+		Dim:       lipgloss.Color("#D0D0D0"),
+		Border:    lipgloss.Color("#FFFFFF"),
+	},
+}
+
 var (
-	primaryColor   = lipgloss.Color("#7D56F4")
-	secondaryColor = lipgloss.Color("#3C3C3C")
-	accentColor    = lipgloss.Color("#FF79C6")
-	textColor      = lipgloss.Color("#FAFAFA")
-	dimColor       = lipgloss.Color("#6C6C6C")
-	borderColor    = lipgloss.Color("#383838")
+	primaryColor   lipgloss.TerminalColor
+	secondaryColor lipgloss.TerminalColor
+	accentColor    lipgloss.TerminalColor
+	textColor      lipgloss.TerminalColor
+	dimColor       lipgloss.TerminalColor
+	borderColor    lipgloss.TerminalColor
+
+	baseStyle         lipgloss.Style
+	titleStyle        lipgloss.Style
+	paneStyle         lipgloss.Style
+	activePaneStyle   lipgloss.Style
+	selectedItemStyle lipgloss.Style
+	normalItemStyle   lipgloss.Style
+	folderStyle       lipgloss.Style
+	dimStyle          lipgloss.Style
+	helpStyle         lipgloss.Style
+)
+
+func init() {
+	applyTheme(themes[0])
+}
+
+// findTheme returns the index of the named theme, or 0 for the default.
+func findTheme(name string) int {
+	for i, theme := range themes {
+		if theme.Name == name {
+			return i
+		}
+	}
+	return 0
+}
+
+// applyTheme rebuilds the shared styles from the theme's colors. Styles are
+// values, so anything rendered afterwards picks up the new palette.
+func applyTheme(theme Theme) {
+	primaryColor = theme.Primary
+	secondaryColor = theme.Secondary
+	accentColor = theme.Accent
+	textColor = theme.Text
+	dimColor = theme.Dim
+	borderColor = theme.Border
 
 	baseStyle = lipgloss.NewStyle().
-			Foreground(textColor)
+		Foreground(textColor)
 
 	titleStyle = lipgloss.NewStyle().
-			Foreground(primaryColor).
-			Bold(true).
-			Padding(0, 1)
+		Foreground(primaryColor).
+		Bold(true).
+		Padding(0, 1)
 
 	paneStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(borderColor).
-			Padding(0, 1)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(borderColor).
+		Padding(0, 1)
 
 	activePaneStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(primaryColor).
-			Padding(0, 1)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor).
+		Padding(0, 1)
 
 	selectedItemStyle = lipgloss.NewStyle().
-				Foreground(accentColor).
-				Bold(true).
-				PaddingLeft(1)
+		Foreground(accentColor).
+		Bold(true).
+		PaddingLeft(1)
 
 	normalItemStyle = lipgloss.NewStyle().
-			Foreground(textColor).
-			PaddingLeft(1)
+		Foreground(textColor).
+		PaddingLeft(1)
 
 	folderStyle = lipgloss.NewStyle().
-			Foreground(primaryColor).
-			Bold(true)
+		Foreground(primaryColor).
+		Bold(true)
 
 	dimStyle = lipgloss.NewStyle().
-			Foreground(dimColor)
+		Foreground(dimColor)
 
 	helpStyle = lipgloss.NewStyle().
-			Foreground(dimColor).
-			Padding(1, 0)
-)
+		Foreground(dimColor).
+		Padding(1, 0)
+}
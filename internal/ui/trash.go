@@ -0,0 +1,175 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/levineuwirth/gophermark/internal/models"
+	"github.com/levineuwirth/gophermark/internal/staging"
+)
+
+// trashFolder returns the folder deleted bookmarks are moved to, or nil
+// when nothing has been deleted yet.
+func (m *Model) trashFolder() *models.Bookmark {
+	return findFolderByGUID(m.root, staging.TrashFolderGUID)
+}
+
+func (m *Model) ensureTrashFolder() (*models.Bookmark, error) {
+	return m.ensureMenuFolder(staging.TrashFolderTitle, staging.TrashFolderGUID, m.stagingDB.FindOrCreateTrashFolder)
+}
+
+// inTrash reports whether the list pane is showing the trash, where
+// deleting is permanent.
+func (m *Model) inTrash() bool {
+	trash := m.trashFolder()
+	return trash != nil && !m.inSearchMode && m.currentFolder == trash
+}
+
+func (m *Model) jumpToTrash() {
+	trash := m.trashFolder()
+	if trash == nil {
+		m.statusMessage = "The trash is empty"
+		return
+	}
+
+	ExpandPath(m.root, trash, m.expandedFolders)
+	m.treeNodes = BuildFlatTree(m.root, m.expandedFolders, m.sortFolders)
+	if idx := FindNodeIndex(m.treeNodes, trash.ID); idx >= 0 {
+		m.treeCursor = idx
+	}
+
+	m.inSearchMode = false
+	m.searchResults = nil
+	m.currentFolder = trash
+	m.bookmarks = m.folderContents(trash)
+	m.listCursor = 0
+	m.activePane = ListPane
+	m.statusMessage = fmt.Sprintf("Trash: %d bookmarks (u: restore, d: delete for good, X: empty)", countBookmarks(trash))
+}
+
+// restoreFromTrash puts the marked bookmarks in the trash, or the
+// highlighted one when none are marked, back where they were deleted from.
+func (m *Model) restoreFromTrash() {
+	restored, attempted, err := m.putBack(m.trashFolder(), (*staging.StagingDB).RestoreFromTrash)
+	if attempted == 0 {
+		return
+	}
+
+	if err != nil {
+		m.statusMessage = fmt.Sprintf("⚠ Restored %d, failed %d: %v", restored, attempted-restored, err)
+	} else {
+		m.statusMessage = fmt.Sprintf("✓ Restored %d bookmarks (Ctrl+S to commit)", restored)
+	}
+}
+
+// putBack moves the marked bookmarks in folder, or the highlighted one when
+// none are marked, to the folder restore returns for each, and reports how
+// many went back out of how many it tried, with the last error.
+func (m *Model) putBack(folder *models.Bookmark, restore func(*staging.StagingDB, int64) (int64, error)) (int, int, error) {
+	var bookmarks []*models.Bookmark
+	for _, bookmark := range m.selectedBookmarkList() {
+		if bookmark.Parent == folder.ID {
+			bookmarks = append(bookmarks, bookmark)
+		}
+	}
+	if len(bookmarks) == 0 {
+		if bookmark := m.selectedBookmark(); bookmark != nil && bookmark.IsBookmark() {
+			bookmarks = []*models.Bookmark{bookmark}
+		}
+	}
+	if len(bookmarks) == 0 {
+		return 0, 0, nil
+	}
+
+	if m.stagingDB == nil {
+		var err error
+		m.stagingDB, err = m.createStaging()
+		if err != nil {
+			m.statusMessage = "Failed to create staging database: " + err.Error()
+			return 0, 0, nil
+		}
+	}
+
+	restored := 0
+	var lastErr error
+	for _, bookmark := range bookmarks {
+		parentID, err := restore(m.stagingDB, bookmark.ID)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		parent := findBookmarkByID(m.root, parentID)
+		if parent == nil {
+			lastErr = fmt.Errorf("folder %d not found", parentID)
+			continue
+		}
+
+		removeFromTree(m.root, map[int64]bool{bookmark.ID: true})
+		bookmark.Parent = parent.ID
+		bookmark.Position = len(parent.Children)
+		parent.Children = append(parent.Children, bookmark)
+		delete(m.selectedBookmarks, bookmark.ID)
+		restored++
+	}
+	if restored > 0 {
+		m.markChanged()
+	}
+
+	m.bookmarks = m.folderContents(m.currentFolder)
+	if m.listCursor >= len(m.bookmarks) {
+		m.listCursor = max(len(m.bookmarks)-1, 0)
+	}
+	return restored, len(bookmarks), lastErr
+}
+
+func (m *Model) enterConfirmEmptyTrash() {
+	trash := m.trashFolder()
+	if trash == nil || len(trash.Children) == 0 {
+		m.statusMessage = "The trash is empty"
+		return
+	}
+	m.editMode = ConfirmEmptyTrash
+	m.statusMessage = fmt.Sprintf("Confirm deleting %d bookmarks in the trash", countBookmarks(trash))
+}
+
+// emptyTrash deletes the trash folder and everything in it, leaving the
+// list on the bookmarks menu that held it.
+func (m *Model) emptyTrash() {
+	trash := m.trashFolder()
+	if trash == nil {
+		return
+	}
+	count := countBookmarks(trash)
+
+	if m.stagingDB == nil {
+		var err error
+		m.stagingDB, err = m.createStaging()
+		if err != nil {
+			m.statusMessage = "Failed to create staging database: " + err.Error()
+			return
+		}
+	}
+
+	if err := m.stagingDB.EmptyTrash(); err != nil {
+		m.statusMessage = "❌ " + err.Error()
+		return
+	}
+
+	for _, child := range trash.Children {
+		delete(m.selectedBookmarks, child.ID)
+	}
+	removeFromTree(m.root, map[int64]bool{trash.ID: true})
+	delete(m.expandedFolders, trash.ID)
+
+	if m.currentFolder == trash {
+		m.currentFolder = findFolderByGUID(m.root, "menu________")
+		m.bookmarks = m.folderContents(m.currentFolder)
+		m.listCursor = 0
+	}
+	m.treeNodes = BuildFlatTree(m.root, m.expandedFolders, m.sortFolders)
+	if idx := FindNearestVisibleIndex(m.treeNodes, m.root, m.currentFolder); idx >= 0 {
+		m.treeCursor = idx
+	}
+
+	m.markChanged()
+	m.statusMessage = fmt.Sprintf("✓ Emptied the trash: %d bookmarks deleted (Ctrl+S to commit)", count)
+}
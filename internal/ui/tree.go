@@ -1,6 +1,14 @@
 package ui
 
-import "github.com/levineuwirth/gophermark/internal/models"
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/levineuwirth/gophermark/internal/db"
+	"github.com/levineuwirth/gophermark/internal/dedup"
+	"github.com/levineuwirth/gophermark/internal/models"
+)
 
 type TreeNode struct {
 	Folder   *models.Bookmark
@@ -9,7 +17,10 @@ type TreeNode struct {
 	Expanded bool
 }
 
-func BuildFlatTree(root *models.Bookmark, expandedFolders map[int64]bool) []*TreeNode {
+// BuildFlatTree lists the visible folders in display order: their stored
+// position, or by title when sortByTitle is set. Sorting only affects what's
+// shown; the folders keep their places in the database.
+func BuildFlatTree(root *models.Bookmark, expandedFolders map[int64]bool, sortByTitle bool) []*TreeNode {
 	var nodes []*TreeNode
 
 	var traverse func(*models.Bookmark, int)
@@ -34,7 +45,11 @@ func BuildFlatTree(root *models.Bookmark, expandedFolders map[int64]bool) []*Tre
 			}
 		}
 
-		for _, child := range node.Children {
+		children := node.Children
+		if sortByTitle {
+			children = sortedByTitle(children)
+		}
+		for _, child := range children {
 			if child.IsFolder() {
 				traverse(child, depth+1)
 			}
@@ -45,6 +60,17 @@ func BuildFlatTree(root *models.Bookmark, expandedFolders map[int64]bool) []*Tre
 	return nodes
 }
 
+// sortedByTitle returns a copy of items ordered case-insensitively by title,
+// leaving items itself, and so the stored order, alone.
+func sortedByTitle(items []*models.Bookmark) []*models.Bookmark {
+	sorted := make([]*models.Bookmark, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return strings.ToLower(sorted[i].DisplayTitle()) < strings.ToLower(sorted[j].DisplayTitle())
+	})
+	return sorted
+}
+
 // hasSubfolders checks if a folder contains any subfolders
 func hasSubfolders(folder *models.Bookmark) bool {
 	for _, child := range folder.Children {
@@ -55,10 +81,44 @@ func hasSubfolders(folder *models.Bookmark) bool {
 	return false
 }
 
-func FindBookmarksBar(root *models.Bookmark) *models.Bookmark {
+func countBookmarks(folder *models.Bookmark) int {
+	count := 0
+	for _, child := range folder.Children {
+		if child.IsBookmark() {
+			count++
+		}
+	}
+	return count
+}
+
+func countBookmarksRecursive(folder *models.Bookmark) int {
+	count := 0
+	for _, child := range folder.Children {
+		if child.IsBookmark() {
+			count++
+		} else if child.IsFolder() {
+			count += countBookmarksRecursive(child)
+		}
+	}
+	return count
+}
+
+// bookmarksBarTitles are the English names the toolbar goes by in
+// databases without Firefox's toolbar guid.
+var bookmarksBarTitles = []string{"Bookmarks Bar", "toolbar", "Bookmarks Toolbar"}
+
+// FindBookmarksBar returns the toolbar folder. Firefox's is found by its
+// guid whatever the UI language; otherwise the first folder titled like a
+// toolbar, in English or one of aliases, is used.
+func FindBookmarksBar(root *models.Bookmark, aliases ...string) *models.Bookmark {
+	if toolbar := findFolderByGUID(root, "toolbar_____"); toolbar != nil {
+		return toolbar
+	}
+
+	titles := append(slices.Clone(bookmarksBarTitles), aliases...)
 	var find func(*models.Bookmark) *models.Bookmark
 	find = func(node *models.Bookmark) *models.Bookmark {
-		if node.IsFolder() && (node.Title == "Bookmarks Bar" || node.Title == "toolbar" || node.Title == "Bookmarks Toolbar") {
+		if node.IsFolder() && slices.Contains(titles, node.Title) {
 			return node
 		}
 		for _, child := range node.Children {
@@ -80,6 +140,18 @@ func FindNodeIndex(nodes []*TreeNode, folderID int64) int {
 	return -1
 }
 
+// FindNearestVisibleIndex returns the index of folder in nodes, or of its
+// closest ancestor that is still visible when folder itself is collapsed away.
+func FindNearestVisibleIndex(nodes []*TreeNode, root *models.Bookmark, folder *models.Bookmark) int {
+	path := findPath(root, folder)
+	for i := len(path) - 1; i >= 0; i-- {
+		if idx := FindNodeIndex(nodes, path[i].ID); idx >= 0 {
+			return idx
+		}
+	}
+	return -1
+}
+
 func ExpandPath(root *models.Bookmark, targetFolder *models.Bookmark, expandedFolders map[int64]bool) {
 	path := findPath(root, targetFolder)
 
@@ -105,3 +177,232 @@ func findPath(root *models.Bookmark, target *models.Bookmark) []*models.Bookmark
 
 	return nil
 }
+
+// folderBreadcrumb joins the titles from the top of the tree down to folder,
+// e.g. "toolbar / Work / Docs". The invisible root is left out.
+func folderBreadcrumb(root, folder *models.Bookmark) string {
+	path := findPath(root, folder)
+	if len(path) == 0 {
+		return folder.DisplayTitle()
+	}
+
+	titles := make([]string, 0, len(path))
+	for _, node := range path[1:] {
+		titles = append(titles, node.DisplayTitle())
+	}
+	return strings.Join(titles, " / ")
+}
+
+// pruneTree copies node keeping only the bookmarks keep accepts and the
+// folders leading to them, or returns nil when none are left. Separators are
+// dropped. The bookmarks themselves are shared, not copied.
+func pruneTree(node *models.Bookmark, keep func(*models.Bookmark) bool) *models.Bookmark {
+	if !node.IsFolder() {
+		if node.IsBookmark() && keep(node) {
+			return node
+		}
+		return nil
+	}
+
+	var children []*models.Bookmark
+	for _, child := range node.Children {
+		if kept := pruneTree(child, keep); kept != nil {
+			children = append(children, kept)
+		}
+	}
+	if len(children) == 0 {
+		return nil
+	}
+
+	folder := *node
+	folder.Children = children
+	return &folder
+}
+
+// folderCount is how many of a set of bookmarks are filed in one folder.
+type folderCount struct {
+	Path  string
+	Count int
+}
+
+// countByFolder groups bookmarks by the path of the folder holding them,
+// labelled as in search results, largest group first.
+func countByFolder(root *models.Bookmark, bookmarks []*models.Bookmark) []folderCount {
+	paths := db.FolderPaths(root)
+	counts := make(map[string]int)
+	for _, bookmark := range bookmarks {
+		counts[paths[bookmark.ID]]++
+	}
+
+	groups := make([]folderCount, 0, len(counts))
+	for path, count := range counts {
+		groups = append(groups, folderCount{Path: path, Count: count})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].Path < groups[j].Path
+	})
+	return groups
+}
+
+// duplicateFolders returns the IDs of the folders directly holding a
+// bookmark from one of groups.
+func duplicateFolders(groups []dedup.DuplicateGroup) map[int64]bool {
+	folders := make(map[int64]bool)
+	for _, group := range groups {
+		for _, bookmark := range group.Bookmarks {
+			folders[bookmark.Parent] = true
+		}
+	}
+	return folders
+}
+
+// isBuiltinFolder reports whether folder is one of Firefox's fixed root
+// containers, or the folder holding orphaned bookmarks, which must not be
+// renamed or deleted.
+func isBuiltinFolder(folder *models.Bookmark) bool {
+	return folder.IsBuiltinFolder() || folder.GUID == db.OrphanedFolderGUID
+}
+
+// findEmptyFolders returns folders that contain nothing but other empty
+// folders, children before their parents so they can be deleted in order.
+// Built-in containers and the tags subtree are never reported.
+func findEmptyFolders(root *models.Bookmark) []*models.Bookmark {
+	var empty []*models.Bookmark
+
+	var visit func(*models.Bookmark) bool
+	visit = func(folder *models.Bookmark) bool {
+		if folder.GUID == "tags________" {
+			return false
+		}
+
+		isEmpty := true
+		for _, child := range folder.Children {
+			if !child.IsFolder() || !visit(child) {
+				isEmpty = false
+			}
+		}
+
+		if isEmpty && !isBuiltinFolder(folder) {
+			empty = append(empty, folder)
+		}
+		return isEmpty && !isBuiltinFolder(folder)
+	}
+
+	visit(root)
+	return empty
+}
+
+// countContents tallies everything beneath folder: bookmarks, subfolders
+// and separators at any depth.
+// countFolderTotals counts the bookmarks under every folder in root,
+// subfolders included, in one walk.
+func countFolderTotals(root *models.Bookmark) map[int64]int {
+	totals := make(map[int64]int)
+	var visit func(*models.Bookmark) int
+	visit = func(folder *models.Bookmark) int {
+		count := 0
+		for _, child := range folder.Children {
+			if child.IsBookmark() {
+				count++
+			} else if child.IsFolder() {
+				count += visit(child)
+			}
+		}
+		totals[folder.ID] = count
+		return count
+	}
+	visit(root)
+	return totals
+}
+
+func countContents(folder *models.Bookmark) (bookmarks, folders, separators int) {
+	for _, child := range folder.Children {
+		switch {
+		case child.IsFolder():
+			folders++
+			b, f, sep := countContents(child)
+			bookmarks += b
+			folders += f
+			separators += sep
+		case child.IsSeparator():
+			separators++
+		default:
+			bookmarks++
+		}
+	}
+	return bookmarks, folders, separators
+}
+
+// findTagsRoot returns the folder Firefox keeps tags under, or nil.
+func findTagsRoot(root *models.Bookmark) *models.Bookmark {
+	for _, child := range root.Children {
+		if child.GUID == "tags________" {
+			return child
+		}
+	}
+	return nil
+}
+
+// isTagFolder reports whether folder is a tag, i.e. a folder directly under
+// the tags root.
+func isTagFolder(root, folder *models.Bookmark) bool {
+	tagsRoot := findTagsRoot(root)
+	return tagsRoot != nil && folder.IsFolder() && folder.Parent == tagsRoot.ID
+}
+
+// buildTagIndex maps each tag to the bookmarks carrying it. Bookmarks are
+// collected from the real folders: the rows inside tag folders only point at
+// the tagged places and have no titles of their own.
+func buildTagIndex(root *models.Bookmark) map[string][]*models.Bookmark {
+	index := make(map[string][]*models.Bookmark)
+
+	var visit func(*models.Bookmark)
+	visit = func(node *models.Bookmark) {
+		if node.GUID == "tags________" {
+			return
+		}
+		if node.IsBookmark() {
+			for _, tag := range node.Tags {
+				index[tag] = append(index[tag], node)
+			}
+		}
+		for _, child := range node.Children {
+			visit(child)
+		}
+	}
+
+	visit(root)
+	return index
+}
+
+// countDeadLinks tallies the bookmarks an audit marked dead under each
+// folder, subfolders included. The tags subtree only repeats bookmarks
+// filed elsewhere, so it's skipped.
+func countDeadLinks(root *models.Bookmark, results map[int64]string) map[int64]int {
+	counts := make(map[int64]int)
+	var visit func(*models.Bookmark) int
+	visit = func(folder *models.Bookmark) int {
+		dead := 0
+		for _, child := range folder.Children {
+			switch {
+			case child.IsFolder():
+				if child.GUID != "tags________" {
+					dead += visit(child)
+				}
+			case child.IsBookmark():
+				if results[child.ID] == "DEAD" {
+					dead++
+				}
+			}
+		}
+		if dead > 0 {
+			counts[folder.ID] = dead
+		}
+		return dead
+	}
+	visit(root)
+	return counts
+}
@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/levineuwirth/gophermark/internal/models"
+)
+
+func TestCountFolderTotals(t *testing.T) {
+	bookmark := func(id int64) *models.Bookmark {
+		return &models.Bookmark{ID: id, Type: models.TypeBookmark, URL: "https://example.com/"}
+	}
+	folder := func(id int64, children ...*models.Bookmark) *models.Bookmark {
+		return &models.Bookmark{ID: id, Type: models.TypeFolder, Children: children}
+	}
+	separator := &models.Bookmark{ID: 99, Type: models.TypeSeparator}
+
+	root := folder(1,
+		folder(2, bookmark(20), separator, folder(21, bookmark(210), bookmark(211)), folder(22)),
+		folder(3, folder(30, folder(300, bookmark(3000)))),
+		bookmark(10),
+	)
+
+	totals := countFolderTotals(root)
+	var check func(*models.Bookmark)
+	check = func(f *models.Bookmark) {
+		if got, want := totals[f.ID], countBookmarksRecursive(f); got != want {
+			t.Errorf("folder %d: total = %d, want %d", f.ID, got, want)
+		}
+		for _, child := range f.Children {
+			if child.IsFolder() {
+				check(child)
+			}
+		}
+	}
+	check(root)
+	if totals[1] != 5 {
+		t.Fatalf("root total = %d, want 5", totals[1])
+	}
+}
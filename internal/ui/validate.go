@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// knownSchemes lists the URL schemes Firefox stores in bookmarks. Web
+// schemes additionally require a host.
+var knownSchemes = map[string]bool{
+	"http":          true,
+	"https":         true,
+	"ftp":           true,
+	"file":          true,
+	"about":         true,
+	"place":         true,
+	"javascript":    true,
+	"data":          true,
+	"mailto":        true,
+	"moz-extension": true,
+	"view-source":   true,
+}
+
+var webSchemes = map[string]bool{
+	"http":  true,
+	"https": true,
+	"ftp":   true,
+}
+
+// validateURL checks that raw has a known scheme and, for web URLs, a host.
+// When raw looks like a bare host such as "example.com", it returns an
+// error along with an https:// suggestion the caller can offer instead.
+func validateURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", fmt.Errorf("URL is required")
+	}
+
+	u, err := url.Parse(raw)
+	if err == nil && knownSchemes[strings.ToLower(u.Scheme)] {
+		if webSchemes[strings.ToLower(u.Scheme)] && u.Host == "" {
+			return "", fmt.Errorf("URL is missing a host: %s", raw)
+		}
+		return "", nil
+	}
+
+	if !strings.Contains(raw, "://") {
+		suggestion := "https://" + raw
+		if su, err := url.Parse(suggestion); err == nil && su.Host != "" && !strings.ContainsAny(su.Host, " \t") {
+			return suggestion, fmt.Errorf("URL has no scheme")
+		}
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Scheme == "" {
+		return "", fmt.Errorf("URL has no scheme: %s", raw)
+	}
+	return "", fmt.Errorf("unknown URL scheme %q", u.Scheme)
+}
@@ -0,0 +1,210 @@
+// Package gophermark exposes GopherMark's bookmark handling for use outside
+// the TUI: finding Firefox profiles, loading the bookmark tree, searching,
+// auditing links, finding duplicates and exporting.
+//
+// The types are aliases of the ones the TUI uses, so values can be passed
+// between the functions here freely. None of these functions modify
+// places.sqlite; editing stays in the TUI.
+package gophermark
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/levineuwirth/gophermark/internal/audit"
+	"github.com/levineuwirth/gophermark/internal/db"
+	"github.com/levineuwirth/gophermark/internal/dedup"
+	"github.com/levineuwirth/gophermark/internal/export"
+	"github.com/levineuwirth/gophermark/internal/models"
+	"github.com/levineuwirth/gophermark/internal/search"
+)
+
+type (
+	Bookmark     = models.Bookmark
+	BookmarkType = models.BookmarkType
+
+	Profile = db.ProfileInfo
+	DB      = db.DB
+
+	SearchResult = search.Result
+
+	Auditor    = audit.Auditor
+	LinkStatus = audit.LinkStatus
+	LinkResult = audit.LinkResult
+
+	DuplicateGroup = dedup.DuplicateGroup
+	ScanOptions    = dedup.ScanOptions
+)
+
+const (
+	TypeBookmark  = models.TypeBookmark
+	TypeFolder    = models.TypeFolder
+	TypeSeparator = models.TypeSeparator
+)
+
+const (
+	StatusPending       = audit.StatusPending
+	StatusAlive         = audit.StatusAlive
+	StatusDead          = audit.StatusDead
+	StatusTimeout       = audit.StatusTimeout
+	StatusRedirectHTTPS = audit.StatusRedirectHTTPS
+	StatusSkipped       = audit.StatusSkipped
+	StatusParked        = audit.StatusParked
+)
+
+// Errors returned by the functions here can be matched with errors.Is.
+var (
+	ErrProfileNotFound  = db.ErrProfileNotFound
+	ErrDatabaseNotFound = db.ErrDatabaseNotFound
+	ErrDatabaseLocked   = db.ErrDatabaseLocked
+	ErrCorrupt          = db.ErrCorrupt
+)
+
+// FindAllProfiles returns the Firefox or LibreWolf profiles on this machine
+// that have a places.sqlite.
+func FindAllProfiles() ([]Profile, error) {
+	return db.FindAllProfiles()
+}
+
+// DefaultProfile returns the profile the browser opens by default, or the
+// first one if none is marked. profiles must not be empty.
+func DefaultProfile(profiles []Profile) Profile {
+	return db.DefaultProfile(profiles)
+}
+
+// OpenReadOnly opens the database at dbPath without locking it. Pages the
+// browser hasn't checkpointed from its write-ahead log yet aren't seen;
+// use OpenSnapshot for those.
+func OpenReadOnly(dbPath string) (*DB, error) {
+	return db.OpenReadOnly(dbPath)
+}
+
+// OpenSnapshot reads from a temporary copy of the database and its
+// write-ahead log, which is consistent even while the browser is running.
+// Close removes the copy.
+func OpenSnapshot(dbPath string) (*DB, error) {
+	return db.OpenSnapshot(dbPath)
+}
+
+// LoadTree reads every bookmark from the database at dbPath and returns the
+// root of the tree. The database is opened read-only and closed again.
+func LoadTree(dbPath string) (*Bookmark, error) {
+	conn, err := db.OpenReadOnly(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	bookmarks, err := conn.FetchAllBookmarks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch bookmarks: %w", err)
+	}
+
+	return db.BuildTree(bookmarks)
+}
+
+// BuildTree links bookmarks fetched from a database into a tree and returns
+// its root.
+func BuildTree(bookmarks []*Bookmark) (*Bookmark, error) {
+	return db.BuildTree(bookmarks)
+}
+
+// BuildTreeWithOrphans is BuildTree, but bookmarks whose parent is missing
+// are gathered in a folder at the end of the root instead of being dropped.
+// It also returns how many there were.
+func BuildTreeWithOrphans(bookmarks []*Bookmark) (*Bookmark, int, error) {
+	return db.BuildTreeWithOrphans(bookmarks)
+}
+
+// FolderPaths maps every node ID under root to the " / "-joined titles of
+// the folders containing it.
+func FolderPaths(root *Bookmark) map[int64]string {
+	return db.FolderPaths(root)
+}
+
+// Search finds the bookmarks under root whose title, description, tags or
+// URL match query, tolerating typos, best matches first. A "tag:name" term
+// keeps only bookmarks with that tag.
+func Search(root *Bookmark, query string) []SearchResult {
+	return search.BookmarksWithPaths(root, query)
+}
+
+// NewAuditor returns an auditor that checks links with the given number of
+// concurrent workers, or 10 when workers isn't positive.
+func NewAuditor(workers int) *Auditor {
+	return audit.NewAuditor(workers)
+}
+
+// FindDuplicates groups bookmarks in conn that share a URL.
+func FindDuplicates(conn *DB) ([]DuplicateGroup, error) {
+	return dedup.FindDuplicates(conn.Conn())
+}
+
+// FindDuplicatesWithOptions is FindDuplicates with a custom timeout and
+// progress callback.
+func FindDuplicatesWithOptions(conn *DB, opts ScanOptions) ([]DuplicateGroup, error) {
+	return dedup.FindDuplicatesWithOptions(conn.Conn(), opts)
+}
+
+// ExportJSON writes the tree under root to outputPath as JSON, keeping each
+// item's GUID and ID.
+func ExportJSON(root *Bookmark, outputPath string) error {
+	return export.ExportJSON(root, outputPath)
+}
+
+// ExportJSONL writes one JSON object per bookmark, with its folder path.
+func ExportJSONL(root *Bookmark, outputPath string) error {
+	return export.ExportJSONL(root, outputPath)
+}
+
+// ExportHTML writes a Netscape bookmark file that browsers can import.
+func ExportHTML(root *Bookmark, outputPath string) error {
+	return export.ExportHTML(root, outputPath)
+}
+
+// ExportHTMLViewer writes a standalone page for browsing the bookmarks,
+// with collapsible folders and a search box.
+func ExportHTMLViewer(root *Bookmark, outputPath string) error {
+	return export.ExportHTMLViewer(root, outputPath)
+}
+
+// ExportMarkdown writes the bookmarks as Markdown, a heading per top-level
+// folder with nested lists below it.
+func ExportMarkdown(root *Bookmark, outputPath string) error {
+	return export.ExportMarkdown(root, outputPath)
+}
+
+// ExportOPML writes the bookmarks as an OPML outline.
+func ExportOPML(root *Bookmark, outputPath string) error {
+	return export.ExportOPML(root, outputPath)
+}
+
+// WriteJSON is ExportJSON writing to w.
+func WriteJSON(w io.Writer, root *Bookmark) error {
+	return export.WriteJSON(w, root)
+}
+
+// WriteJSONL is ExportJSONL writing to w.
+func WriteJSONL(w io.Writer, root *Bookmark) error {
+	return export.WriteJSONL(w, root)
+}
+
+// WriteHTML is ExportHTML writing to w.
+func WriteHTML(w io.Writer, root *Bookmark) error {
+	return export.WriteHTML(w, root)
+}
+
+// WriteHTMLViewer is ExportHTMLViewer writing to w.
+func WriteHTMLViewer(w io.Writer, root *Bookmark) error {
+	return export.WriteHTMLViewer(w, root)
+}
+
+// WriteMarkdown is ExportMarkdown writing to w.
+func WriteMarkdown(w io.Writer, root *Bookmark) error {
+	return export.WriteMarkdown(w, root)
+}
+
+// WriteOPML is ExportOPML writing to w.
+func WriteOPML(w io.Writer, root *Bookmark) error {
+	return export.WriteOPML(w, root)
+}